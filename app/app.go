@@ -32,6 +32,10 @@ func CreateApp(conf *config.AppConfig) (app *fiber.App) {
 		JSONEncoder:             json.Marshal,
 		JSONDecoder:             json.Unmarshal,
 		Prefork:                 true,
+		// Bodies are read by controllers.LimitRequestBody, so attachment uploads can go past the
+		// default BodyLimit without raising it for every other route
+		StreamRequestBody:            true,
+		DisablePreParseMultipartForm: true,
 	})
 }
 
@@ -0,0 +1,167 @@
+package breached
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/config"
+)
+
+const PrefixLength int = 5
+
+var ErrInvalidPrefix = errors.New("invalid SHA-1 prefix")
+
+var prefixRegexp = regexp.MustCompile(`^[0-9A-F]{5}$`)
+var rangeFileRegexp = regexp.MustCompile(`^[0-9A-F]{5}\.txt$`)
+
+type Match struct {
+	Suffix string `json:"suffix"`
+	Count  int64  `json:"count"`
+}
+
+// Dataset answers k-anonymity range queries against a local copy of the HIBP Pwned Passwords
+// data. Two layouts are read from the data directory: per-prefix range files as produced by
+// the official downloader (ABCDE.txt holding SUFFIX:COUNT lines), and whole-hash files sorted
+// by hash (HASH:COUNT lines), which are memory-mapped once and binary searched per query.
+type Dataset struct {
+	dir    string
+	sorted [][]byte
+}
+
+func Init(conf *config.AppConfig) *Dataset {
+	dataset, err := Open(conf.BREACHED_DATA_DIR)
+
+	if err != nil {
+		log.Fatalf("Failed to open breached passwords data '%s': %s", conf.BREACHED_DATA_DIR, err)
+	}
+
+	return dataset
+}
+
+func Open(dir string) (*Dataset, error) {
+	entries, err := os.ReadDir(dir)
+
+	if err != nil {
+		return nil, err
+	}
+
+	dataset := &Dataset{dir: dir}
+
+	for _, entry := range entries {
+		name := entry.Name()
+
+		if entry.IsDir() || !strings.HasSuffix(name, ".txt") || rangeFileRegexp.MatchString(name) {
+			continue
+		}
+
+		data, err := mapFile(filepath.Join(dir, name))
+
+		if err != nil {
+			dataset.Close()
+
+			return nil, err
+		}
+
+		if len(data) > 0 {
+			dataset.sorted = append(dataset.sorted, data)
+		}
+	}
+
+	return dataset, nil
+}
+
+func (D *Dataset) Close() {
+	for _, data := range D.sorted {
+		unmapFile(data)
+	}
+
+	D.sorted = nil
+}
+
+// Range returns every known hash suffix for the given 5-character SHA-1 prefix, sorted by suffix.
+func (D *Dataset) Range(prefix string) ([]Match, error) {
+	prefix = strings.ToUpper(prefix)
+
+	if !prefixRegexp.MatchString(prefix) {
+		return nil, ErrInvalidPrefix
+	}
+
+	matches := []Match{}
+
+	if data, err := os.ReadFile(filepath.Join(D.dir, prefix + ".txt")); err == nil {
+		matches = appendMatches(matches, data, 0)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	for _, data := range D.sorted {
+		start := searchPrefix(data, []byte(prefix))
+		end := start
+
+		for end < len(data) && bytes.Equal(linePrefix(data, end), []byte(prefix)) {
+			end = lineEnd(data, end) + 1
+		}
+
+		if start < len(data) {
+			matches = appendMatches(matches, data[start:min(end, len(data))], PrefixLength)
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool { return matches[i].Suffix < matches[j].Suffix })
+
+	return matches, nil
+}
+
+// searchPrefix returns the offset of the first line whose hash sorts at or after prefix.
+func searchPrefix(data, prefix []byte) int {
+	lo, hi := 0, len(data)
+
+	for lo < hi {
+		mid := (lo + hi) / 2
+		start := bytes.LastIndexByte(data[:mid], '\n') + 1
+
+		if bytes.Compare(linePrefix(data, start), prefix) < 0 {
+			lo = lineEnd(data, start) + 1
+		} else {
+			hi = start
+		}
+	}
+
+	return lo
+}
+
+func lineEnd(data []byte, start int) int {
+	if i := bytes.IndexByte(data[start:], '\n'); i >= 0 {
+		return start + i
+	}
+
+	return len(data)
+}
+
+func linePrefix(data []byte, start int) []byte {
+	return bytes.ToUpper(data[start:min(start + PrefixLength, lineEnd(data, start))])
+}
+
+// appendMatches parses HASH:COUNT lines, dropping the first skip characters of each hash.
+func appendMatches(matches []Match, data []byte, skip int) []Match {
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		hash, count, ok := bytes.Cut(bytes.TrimSpace(line), []byte(":"))
+
+		if !ok || len(hash) <= skip {
+			continue
+		}
+
+		if n, err := strconv.ParseInt(string(count), 10, 64); err == nil {
+			matches = append(matches, Match{strings.ToUpper(string(hash[skip:])), n})
+		}
+	}
+
+	return matches
+}
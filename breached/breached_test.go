@@ -0,0 +1,61 @@
+package breached
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDatasetRange(t *testing.T) {
+	sum := sha1.Sum([]byte("password"))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	prefix, suffix := hash[:PrefixLength], hash[PrefixLength:]
+
+	t.Run("sorted_file_binary_search", func(t *testing.T) {
+		dir := t.TempDir()
+		lines := []string{
+			"00000" + strings.Repeat("A", 35) + ":1",
+			"5BAA5" + strings.Repeat("F", 35) + ":2",
+			hash + ":9545824",
+			"5baa61" + strings.Repeat("f", 34) + ":4",
+			"5BAA70" + strings.Repeat("0", 34) + ":5",
+			"FFFFF" + strings.Repeat("0", 35) + ":6",
+		}
+		require.NoError(t, os.WriteFile(
+			filepath.Join(dir, "pwned-passwords-sha1-ordered-by-hash.txt"),
+			[]byte(strings.Join(lines, "\r\n")), 0600,
+		))
+
+		dataset, err := Open(dir)
+		require.NoError(t, err)
+		defer dataset.Close()
+
+		matches, err := dataset.Range(prefix)
+		require.NoError(t, err)
+		require.Equal(t, []Match{
+			{Suffix: suffix, Count: 9545824},
+			{Suffix: "1" + strings.Repeat("F", 34), Count: 4},
+		}, matches)
+
+		matches, err = dataset.Range("00000")
+		require.NoError(t, err)
+		require.Len(t, matches, 1)
+
+		matches, err = dataset.Range("FFFFF")
+		require.NoError(t, err)
+		require.Equal(t, []Match{{Suffix: strings.Repeat("0", 35), Count: 6}}, matches)
+
+		matches, err = dataset.Range("12345")
+		require.NoError(t, err)
+		require.Empty(t, matches)
+
+		_, err = dataset.Range("XYZ12")
+		require.ErrorIs(t, err, ErrInvalidPrefix)
+
+	})
+}
@@ -0,0 +1,12 @@
+//go:build !unix
+
+package breached
+
+import "os"
+
+// Without mmap the sorted files are read into memory, which only suits small datasets
+func mapFile(path string) ([]byte, error) {
+	return os.ReadFile(path)
+}
+
+func unmapFile(data []byte) {}
@@ -0,0 +1,30 @@
+//go:build unix
+
+package breached
+
+import (
+	"os"
+	"syscall"
+)
+
+func mapFile(path string) ([]byte, error) {
+	file, err := os.Open(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	info, err := file.Stat()
+
+	if err != nil || info.Size() == 0 {
+		return nil, err
+	}
+
+	return syscall.Mmap(int(file.Fd()), 0, int(info.Size()), syscall.PROT_READ, syscall.MAP_SHARED)
+}
+
+func unmapFile(data []byte) {
+	syscall.Munmap(data)
+}
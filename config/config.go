@@ -5,6 +5,7 @@ import (
 	"log"
 	"os"
 	"reflect"
+	"strconv"
 	"strings"
 	"testing"
 
@@ -24,6 +25,7 @@ type AppConfig struct {
 	AWS_SES_KEY             string
 	AWS_SES_PASSWORD        string
 	BEHIND_PROXY            bool
+	BREACHED_DATA_DIR       string
 	EMAIL_HOST              string
 	EMAIL_PORT              string
 	ENVIRONMENT             string
@@ -34,15 +36,22 @@ type AppConfig struct {
 	LOGGER_DB_USER          string
 	PASSWORD_HEADER_KEY     string
 	PROXY_IP_ADDRESSES      []string
+	REDIS_HOST              string
 	REDIS_PASSWORD          string
+	REDIS_PORT              string
 	SECRET_KEY              string
+	SECRET_VERSIONS_RETAIN  int
 	SUPPORT_EMAIL						string
+	TRASH_RETENTION_DAYS    int
 	TWILIO_ACCOUNT_SID      string
 	TWILIO_AUTH_TOKEN       string
 	TWILIO_PHONE_NUMBER     string
-	VAULTS_ACCESS_TOKEN     string
-	VAULTS_HOST             string
-	VAULTS_PORT             string
+	VAULTS_BALANCER         string
+	VAULTS_MAX_RESPONSE_BYTES int64
+	VAULTS_TOKEN_KEY        string
+	VAULTS_TOKEN_KEY_ID     string
+	VAULTS_UPSTREAMS        []string
+	VAULTS_UPSTREAMS_FILE   string
 	GO_TESTING_CONTEXT      *testing.T
 }
 
@@ -59,6 +68,7 @@ type envAbsPaths struct {
 	AWS_SES_KEY             string
 	AWS_SES_PASSWORD        string
 	BEHIND_PROXY            string
+	BREACHED_DATA_DIR       string
 	EMAIL_HOST              string
 	EMAIL_PORT              string
 	ENVIRONMENT             string
@@ -69,15 +79,21 @@ type envAbsPaths struct {
 	LOGGER_DB_USER          string
 	PASSWORD_HEADER_KEY     string
 	PROXY_IP_ADDRESSES      string
+	REDIS_HOST              string
 	REDIS_PASSWORD          string
+	REDIS_PORT              string
 	SECRET_KEY              string
+	SECRET_VERSIONS_RETAIN  string
 	SUPPORT_EMAIL						string
+	TRASH_RETENTION_DAYS    string
 	TWILIO_ACCOUNT_SID      string
 	TWILIO_AUTH_TOKEN       string
 	TWILIO_PHONE_NUMBER     string
-	VAULTS_ACCESS_TOKEN     string
-	VAULTS_HOST             string
-	VAULTS_PORT             string
+	VAULTS_BALANCER         string
+	VAULTS_MAX_RESPONSE_BYTES string
+	VAULTS_TOKEN_KEY        string
+	VAULTS_TOKEN_KEY_ID     string
+	VAULTS_UPSTREAMS        string
 }
 
 func scanFileFirstLineToConf(
@@ -101,6 +117,28 @@ func scanFileFirstLineToConf(
 		}
 	} else if fieldName == "PROXY_IP_ADDRESSES" {
 		conf.PROXY_IP_ADDRESSES = strings.Split(contents, ",")
+	} else if fieldName == "VAULTS_MAX_RESPONSE_BYTES" {
+		if n, err := strconv.ParseInt(contents, 10, 64); err != nil || n < 1 {
+			log.Fatalf("Invalid contents of '%s' from environment variable %s", path, fieldName)
+		} else {
+			conf.VAULTS_MAX_RESPONSE_BYTES = n
+		}
+	} else if fieldName == "SECRET_VERSIONS_RETAIN" {
+		if n, err := strconv.Atoi(contents); err != nil || n < 1 {
+			log.Fatalf("Invalid contents of '%s' from environment variable %s", path, fieldName)
+		} else {
+			conf.SECRET_VERSIONS_RETAIN = n
+		}
+	} else if fieldName == "TRASH_RETENTION_DAYS" {
+		if n, err := strconv.Atoi(contents); err != nil || n < 1 || n > 3650 {
+			log.Fatalf("Invalid contents of '%s' from environment variable %s", path, fieldName)
+		} else {
+			conf.TRASH_RETENTION_DAYS = n
+		}
+	} else if fieldName == "VAULTS_UPSTREAMS" {
+		// Keep the path too, the upstream list is re-read from it at runtime
+		conf.VAULTS_UPSTREAMS = strings.Split(contents, ",")
+		conf.VAULTS_UPSTREAMS_FILE = path
 	} else {
 		confElem.FieldByName(fieldName).SetString(contents)
 	}
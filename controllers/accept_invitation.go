@@ -0,0 +1,82 @@
+package controllers
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/models"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+// AcceptInvitation makes the user a member of the vault with the invited role. The member can't
+// decrypt anything until a manager's client wraps the vault key for them, which needs the
+// public key of their key pair, so one has to be set first.
+func (H Handler) AcceptInvitation(c *fiber.Ctx) error {
+	if header := c.Get("Client-Operation"); header != utils.AcceptInvitation {
+		H.logger(c, utils.AcceptInvitation, header, "", "warn", utils.ErrorClientOperation, "")
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	var session *models.ClientSession
+	var ok bool
+
+	if session, ok = c.UserContext().Value(sessionContextKey{}).(*models.ClientSession); !ok {
+		H.logger(c, utils.AcceptInvitation, "", "", "error", "Failed session.User context", "")
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	invitation, err := H.findInvitation(c, utils.AcceptInvitation, session)
+
+	if invitation == nil {
+		return err
+	}
+
+	var count int64
+
+	if result := H.DBs.ApiGateway.Model(&models.UserKeyPair{}).
+		Where("user_slug = ?", session.UserSlug).Count(&count); result.Error != nil {
+		H.logger(
+			c, utils.AcceptInvitation, result.Error.Error(), "", "error", utils.ErrorFailedDB,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	} else if count == 0 {
+		H.logger(c, utils.AcceptInvitation, "", "", "warn", utils.ErrorNoKeyPair, session.UserSlug)
+
+		return utils.RespondWithError(c, 409, utils.ErrorNoKeyPairDetail, nil, nil)
+	}
+
+	member := models.VaultMember{
+		VaultSlug: invitation.VaultSlug,
+		UserSlug:  session.UserSlug,
+		Role:      invitation.Role,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	if err = H.DBs.ApiGateway.Transaction(func(tx *gorm.DB) error {
+		if result := tx.Delete(invitation); result.Error != nil {
+			return result.Error
+		}
+
+		return tx.Omit("SharedVault", "User").Create(&member).Error
+	}); err != nil {
+		H.logger(
+			c, utils.AcceptInvitation, err.Error(), "", "error", utils.ErrorFailedDB, session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	return c.Status(201).JSON(&VaultMemberResponse{
+		UserSlug:     session.UserSlug,
+		Name:         session.User.Name,
+		EmailAddress: session.User.EmailAddress,
+		Role:         member.Role,
+		CreatedAt:    member.CreatedAt,
+	})
+}
@@ -0,0 +1,65 @@
+package controllers
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/models"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+// AcceptOrgInvitation makes the user a member of the organization with the invited role. They
+// reach no org vaults as a member until an admin adds them to a group with grants.
+func (H Handler) AcceptOrgInvitation(c *fiber.Ctx) error {
+	if header := c.Get("Client-Operation"); header != utils.AcceptOrgInvitation {
+		H.logger(c, utils.AcceptOrgInvitation, header, "", "warn", utils.ErrorClientOperation, "")
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	var session *models.ClientSession
+	var ok bool
+
+	if session, ok = c.UserContext().Value(sessionContextKey{}).(*models.ClientSession); !ok {
+		H.logger(c, utils.AcceptOrgInvitation, "", "", "error", "Failed session.User context", "")
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	invitation, err := H.findOrgInvitation(c, utils.AcceptOrgInvitation, session)
+
+	if invitation == nil {
+		return err
+	}
+
+	member := models.OrgMember{
+		OrgSlug:   invitation.OrgSlug,
+		UserSlug:  session.UserSlug,
+		Role:      invitation.Role,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	if err = H.DBs.ApiGateway.Transaction(func(tx *gorm.DB) error {
+		if result := tx.Delete(invitation); result.Error != nil {
+			return result.Error
+		}
+
+		return tx.Omit("Organization", "User").Create(&member).Error
+	}); err != nil {
+		H.logger(
+			c, utils.AcceptOrgInvitation, err.Error(), "", "error", utils.ErrorFailedDB,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	return c.Status(201).JSON(&OrgResponse{
+		OrgSlug:   invitation.OrgSlug,
+		Name:      invitation.Organization.Name,
+		Role:      member.Role,
+		CreatedAt: invitation.Organization.CreatedAt,
+	})
+}
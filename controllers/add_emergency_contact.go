@@ -0,0 +1,140 @@
+package controllers
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/models"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+type AddEmergencyContactRequestBody struct {
+	EmailAddress string `json:"email_address"`
+	WaitingDays  int    `json:"waiting_days"`
+}
+
+// AddEmergencyContact designates someone, by email address, who can ask for read-only access
+// to the user's vaults in an emergency. No vaults are selected until UpdateEmergencyContact
+// selects them.
+func (H Handler) AddEmergencyContact(c *fiber.Ctx) error {
+	if header := c.Get("Client-Operation"); header != utils.AddEmergencyContact {
+		H.logger(c, utils.AddEmergencyContact, header, "", "warn", utils.ErrorClientOperation, "")
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	var session *models.ClientSession
+	var ok bool
+
+	if session, ok = c.UserContext().Value(sessionContextKey{}).(*models.ClientSession); !ok {
+		H.logger(c, utils.AddEmergencyContact, "", "", "error", "Failed session.User context", "")
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	reqBody := AddEmergencyContactRequestBody{}
+
+	if err := c.BodyParser(&reqBody); err != nil {
+		H.logger(
+			c, utils.AddEmergencyContact, err.Error(), "", "error", utils.ErrorParse, session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	reqBody.EmailAddress = strings.TrimSpace(reqBody.EmailAddress)
+	fieldErrors := map[string][]string{}
+
+	if !utils.EmailRegexp.MatchString(reqBody.EmailAddress) {
+		fieldErrors["email_address"] = []string{"Invalid email address"}
+	} else if strings.EqualFold(reqBody.EmailAddress, session.User.EmailAddress) {
+		fieldErrors["email_address"] = []string{"You can't be your own emergency contact"}
+	}
+
+	if daysErrors := utils.ValidateWaitingDays(reqBody.WaitingDays); daysErrors != nil {
+		fieldErrors["waiting_days"] = daysErrors
+	}
+
+	if len(fieldErrors) > 0 {
+		H.logger(
+			c, utils.AddEmergencyContact, "", "", "warn", utils.ErrorEmergencyContact, session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, fieldErrors, nil)
+	}
+
+	var count int64
+
+	if result := H.DBs.ApiGateway.Model(&models.EmergencyContact{}).Where(
+		"grantor_slug = ? AND LOWER(email_address) = LOWER(?)", session.UserSlug,
+		reqBody.EmailAddress,
+	).Count(&count); result.Error != nil {
+		H.logger(
+			c, utils.AddEmergencyContact, result.Error.Error(), "", "error", utils.ErrorFailedDB,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	} else if count > 0 {
+		H.logger(
+			c, utils.AddEmergencyContact, reqBody.EmailAddress, "", "warn",
+			utils.ErrorEmergencyContact, session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 409, utils.ErrorAlreadyEmergencyContact, nil, nil)
+	}
+
+	contact := models.EmergencyContact{
+		GrantorSlug:  session.UserSlug,
+		Grantor:      session.User,
+		EmailAddress: reqBody.EmailAddress,
+		WaitingDays:  reqBody.WaitingDays,
+		CreatedAt:    time.Now().UTC(),
+	}
+
+	if slug, err := utils.GenerateSlug(utils.EmergencyContactSlugLength); err != nil {
+		H.logger(
+			c, utils.AddEmergencyContact, err.Error(), "", "error", "Failed generate contact.Slug",
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	} else {
+		contact.Slug = slug
+	}
+
+	if result := H.DBs.ApiGateway.Omit("Grantor").Create(&contact); result.Error != nil {
+		H.logger(
+			c, utils.AddEmergencyContact, result.Error.Error(), "", "error", utils.ErrorFailedDB,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	// Audit trail - every stage of emergency access is recorded
+	H.logger(
+		c, utils.AddEmergencyContact, contact.EmailAddress, contact.Slug, "info",
+		utils.InfoEmergencyAdded, session.UserSlug,
+	)
+
+	if H.Conf.ENVIRONMENT != "testing" {
+		if err := H.sendEmergencyEmail(
+			session.User.Name + " named you as an emergency contact", contact.EmailAddress,
+			"email_emergency_contact.html", map[string]string{
+				"GrantorName": session.User.Name,
+				"WaitingDays": strconv.Itoa(contact.WaitingDays),
+			},
+		); err != nil {
+			H.logger(
+				c, utils.AddEmergencyContact, err.Error(), contact.Slug, "error",
+				"Failed send emergency contact email", session.UserSlug,
+			)
+		}
+	}
+
+	return c.Status(201).JSON(newEmergencyContactResponse(&contact, nil))
+}
@@ -0,0 +1,73 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm/clause"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/models"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+// AddOrgGroupMember adds one of the organization's members to a group. Adding a member who is
+// already in the group does nothing.
+func (H Handler) AddOrgGroupMember(c *fiber.Ctx) error {
+	if header := c.Get("Client-Operation"); header != utils.AddOrgGroupMember {
+		H.logger(c, utils.AddOrgGroupMember, header, "", "warn", utils.ErrorClientOperation, "")
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	var session *models.ClientSession
+	var ok bool
+
+	if session, ok = c.UserContext().Value(sessionContextKey{}).(*models.ClientSession); !ok {
+		H.logger(c, utils.AddOrgGroupMember, "", "", "error", "Failed session.User context", "")
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	member, err := H.requireOrgRole(c, utils.AddOrgGroupMember, session, utils.OrgRoleAdmin)
+
+	if member == nil {
+		return err
+	}
+
+	group, err := H.findOrgGroup(c, utils.AddOrgGroupMember, session, member.OrgSlug)
+
+	if group == nil {
+		return err
+	}
+
+	userSlug := c.Params("user_slug")
+	target, err := H.findOrgMember(member.OrgSlug, userSlug)
+
+	if err != nil {
+		H.logger(
+			c, utils.AddOrgGroupMember, err.Error(), member.OrgSlug, "error", utils.ErrorFailedDB,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	} else if target == nil {
+		H.logger(
+			c, utils.AddOrgGroupMember, userSlug, member.OrgSlug, "warn", utils.ErrorOrgMember,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 404, utils.ErrorNotFound, nil, nil)
+	}
+
+	if result := H.DBs.ApiGateway.Clauses(clause.OnConflict{DoNothing: true}).
+		Omit("OrgGroup", "User").
+		Create(&models.OrgGroupMember{GroupSlug: group.Slug, UserSlug: userSlug});
+	result.Error != nil {
+		H.logger(
+			c, utils.AddOrgGroupMember, result.Error.Error(), member.OrgSlug, "error",
+			utils.ErrorFailedDB, session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	return c.SendStatus(204)
+}
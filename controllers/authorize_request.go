@@ -22,10 +22,39 @@ var privilegedOps = []string{
 	utils.RetrieveEntry,
 	utils.UpdateVault,
 	utils.UpdateEntry,
+	utils.MoveEntry,
 	utils.UpdateSecret,
 	utils.DeleteVault,
 	utils.DeleteEntry,
 	utils.DeleteSecret,
+	utils.ImportEntries,
+	utils.ExportVaults,
+	utils.VaultHealthReport,
+	utils.GetEntryTOTP,
+	utils.ListSecretVersions,
+	utils.RestoreSecretVersion,
+	utils.RestoreItem,
+	utils.EmptyTrash,
+	utils.Batch,
+	utils.CreateShare,
+	utils.UploadAttachment,
+	utils.DownloadAttachment,
+	utils.DeleteAttachment,
+	utils.SetKeyPair,
+	utils.RetrieveKeyPair,
+	utils.InviteVaultMember,
+	utils.UpdateVaultMember,
+	utils.SetVaultKeys,
+	utils.RemoveVaultMember,
+	utils.InviteOrgMember,
+	utils.UpdateOrgMember,
+	utils.RemoveOrgMember,
+	utils.AddOrgGroupMember,
+	utils.RemoveOrgGroupMember,
+	utils.SetOrgVaultGrant,
+	utils.RemoveOrgVaultGrant,
+	utils.AddEmergencyContact,
+	utils.UpdateEmergencyContact,
 }
 
 func (H Handler) AuthorizeRequest(c *fiber.Ctx) error {
@@ -174,6 +203,11 @@ func (H Handler) AuthorizeRequest(c *fiber.Ctx) error {
 			} else if !utils.CompareHashAndPassword(
 				thisSession.User.PasswordHash, password, thisSession.User.PasswordSalt,
 			) {
+				// Audit trail - a wrong password on a privileged operation, such as an export. Its
+				// body may hold secrets or a passphrase, so it stays out of the log.
+				c.Locals(redactRequestBodyKey{}, true)
+				H.logger(c, clientOperation, "", "", "warn", utils.ErrorAcctPW, thisSession.UserSlug)
+
 				return utils.RespondWithError(c, 401, utils.ErrorAcctPW, nil, nil)
 			}
 
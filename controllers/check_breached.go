@@ -0,0 +1,61 @@
+package controllers
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/breached"
+	"github.com/liobrdev/simplepasswords_api_gateway/models"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+type CheckBreachedResponseBody struct {
+	Prefix  string           `json:"prefix"`
+	Matches []breached.Match `json:"matches"`
+}
+
+// Clients send only the first 5 hex characters of the SHA-1 and compare suffixes locally,
+// so neither the secret nor its full hash ever reaches the gateway.
+func (H Handler) CheckBreached(c *fiber.Ctx) error {
+	if header := c.Get("Client-Operation"); header != utils.CheckBreached {
+		H.logger(c, utils.CheckBreached, header, "", "warn", utils.ErrorClientOperation, "")
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	var session *models.ClientSession
+	var ok bool
+
+	if session, ok = c.UserContext().Value(sessionContextKey{}).(*models.ClientSession); !ok {
+		H.logger(c, utils.CheckBreached, "", "", "error", "Failed session.User context", "")
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	prefix := c.Params("prefix")
+
+	if !utils.HashPrefixRegexp.Match([]byte(prefix)) {
+		H.logger(c, utils.CheckBreached, prefix, "", "warn", utils.ErrorParams, session.UserSlug)
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, map[string][]string{
+			"prefix": {"Must be 5 hexadecimal characters"},
+		}, nil)
+	}
+
+	matches, err := H.Breached.Range(prefix)
+
+	if err != nil {
+		H.logger(
+			c, utils.CheckBreached, err.Error(), prefix, "error", utils.ErrorCheckBreached,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	return c.Status(200).JSON(&CheckBreachedResponseBody{
+		Prefix:  strings.ToUpper(prefix),
+		Matches: matches,
+	})
+}
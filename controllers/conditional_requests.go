@@ -0,0 +1,29 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+// Updates and deletes must name the version they were based on, so that two devices editing
+// the same item can't silently overwrite each other. Vaults does the actual comparison.
+func ifMatchHeader(c *fiber.Ctx) (string, bool) {
+	ifMatch := c.Get(fiber.HeaderIfMatch)
+
+	return ifMatch, utils.EntityTagsRegexp.Match([]byte(ifMatch))
+}
+
+func respondPreconditionRequired(c *fiber.Ctx) error {
+	return utils.RespondWithError(c, 428, utils.ErrorPreconditionRequired, nil, nil)
+}
+
+// Relays a vaults 412 along with the item's current ETag header, so the client can refetch and
+// retry.
+func respondPreconditionFailed(c *fiber.Ctx, resp *fiber.Response) error {
+	if etag := resp.Header.Peek(fiber.HeaderETag); len(etag) > 0 {
+		c.Set(fiber.HeaderETag, string(etag))
+	}
+
+	return utils.RespondWithError(c, 412, utils.ErrorPreconditionFailed, nil, nil)
+}
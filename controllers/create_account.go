@@ -7,7 +7,6 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"gorm.io/gorm"
 
-	"github.com/liobrdev/simplepasswords_api_gateway/config"
 	"github.com/liobrdev/simplepasswords_api_gateway/models"
 	"github.com/liobrdev/simplepasswords_api_gateway/utils"
 )
@@ -130,7 +129,9 @@ func (H Handler) CreateAccount(c *fiber.Ctx) error {
 	}
 
 	if H.Conf.ENVIRONMENT != "testing" {
-		if errorString := vaultsCreateUser(H.Conf, user.Slug); errorString != "" {
+		if errorString := H.vaultsCreateUser(
+			user.Slug, c.GetRespHeader(fiber.HeaderXRequestID),
+		); errorString != "" {
 			H.logger(
 				c, utils.CreateAccount, errorString, "", "error", utils.ErrorVaultsCreateUser, user.Slug,
 			)
@@ -150,11 +151,26 @@ func (H Handler) CreateAccount(c *fiber.Ctx) error {
 	})
 }
 
-func vaultsCreateUser(conf *config.AppConfig, userSlug string) string {
-	agent := fiber.Post("http://" + conf.VAULTS_HOST + ":" + conf.VAULTS_PORT + "/api/users")
+func (H Handler) vaultsCreateUser(userSlug, requestID string) string {
+	token, err := newVaultsToken(H.Conf, utils.CreateUser, userSlug, requestID)
+
+	if err != nil {
+		return err.Error() + ";;"
+	}
+
+	upstream, err := H.Vaults.Acquire()
+
+	if err != nil {
+		return err.Error() + ";;"
+	}
+
+	defer upstream.Release()
+
+	agent := fiber.Post(upstream.URL + "/api/users")
 	agent.Set("Content-Type", "application/json")
 	agent.Set("Client-Operation", utils.CreateUser)
-	agent.Set("Authorization", "Token " + conf.VAULTS_ACCESS_TOKEN)
+	agent.Set("Authorization", "Bearer " + token)
+	agent.Set(fiber.HeaderXRequestID, requestID)
 
 	agent.JSON(fiber.Map{ "user_slug": userSlug })
 	statusCode, body, errs := agent.String()
@@ -0,0 +1,89 @@
+package controllers
+
+import (
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/models"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+type CreateOrgRequestBody struct {
+	Name string `json:"name"`
+}
+
+// CreateOrg creates an organization with the user as its owner
+func (H Handler) CreateOrg(c *fiber.Ctx) error {
+	if header := c.Get("Client-Operation"); header != utils.CreateOrg {
+		H.logger(c, utils.CreateOrg, header, "", "warn", utils.ErrorClientOperation, "")
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	var session *models.ClientSession
+	var ok bool
+
+	if session, ok = c.UserContext().Value(sessionContextKey{}).(*models.ClientSession); !ok {
+		H.logger(c, utils.CreateOrg, "", "", "error", "Failed session.User context", "")
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	reqBody := CreateOrgRequestBody{}
+
+	if err := c.BodyParser(&reqBody); err != nil {
+		H.logger(c, utils.CreateOrg, err.Error(), "", "error", utils.ErrorParse, session.UserSlug)
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	reqBody.Name = strings.TrimSpace(reqBody.Name)
+
+	if nameErrors := utils.ValidateOrgName(reqBody.Name); nameErrors != nil {
+		H.logger(c, utils.CreateOrg, "", "", "warn", utils.ErrorOrg, session.UserSlug)
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, map[string][]string{
+			"name": nameErrors,
+		}, nil)
+	}
+
+	now := time.Now().UTC()
+	org := models.Organization{Name: reqBody.Name, CreatedAt: now}
+
+	if slug, err := utils.GenerateSlug(utils.OrgSlugLength); err != nil {
+		H.logger(
+			c, utils.CreateOrg, err.Error(), "", "error", "Failed generate org.Slug", session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	} else {
+		org.Slug = slug
+	}
+
+	if err := H.DBs.ApiGateway.Transaction(func(tx *gorm.DB) error {
+		if result := tx.Create(&org); result.Error != nil {
+			return result.Error
+		}
+
+		return tx.Omit("Organization", "User").Create(&models.OrgMember{
+			OrgSlug:   org.Slug,
+			UserSlug:  session.UserSlug,
+			Role:      utils.OrgRoleOwner,
+			CreatedAt: now,
+		}).Error
+	}); err != nil {
+		H.logger(c, utils.CreateOrg, err.Error(), "", "error", utils.ErrorFailedDB, session.UserSlug)
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	return c.Status(201).JSON(&OrgResponse{
+		OrgSlug:   org.Slug,
+		Name:      org.Name,
+		Role:      utils.OrgRoleOwner,
+		CreatedAt: org.CreatedAt,
+	})
+}
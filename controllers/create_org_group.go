@@ -0,0 +1,88 @@
+package controllers
+
+import (
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/models"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+type CreateOrgGroupRequestBody struct {
+	Name string `json:"name"`
+}
+
+func (H Handler) CreateOrgGroup(c *fiber.Ctx) error {
+	if header := c.Get("Client-Operation"); header != utils.CreateOrgGroup {
+		H.logger(c, utils.CreateOrgGroup, header, "", "warn", utils.ErrorClientOperation, "")
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	var session *models.ClientSession
+	var ok bool
+
+	if session, ok = c.UserContext().Value(sessionContextKey{}).(*models.ClientSession); !ok {
+		H.logger(c, utils.CreateOrgGroup, "", "", "error", "Failed session.User context", "")
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	member, err := H.requireOrgRole(c, utils.CreateOrgGroup, session, utils.OrgRoleAdmin)
+
+	if member == nil {
+		return err
+	}
+
+	reqBody := CreateOrgGroupRequestBody{}
+
+	if err = c.BodyParser(&reqBody); err != nil {
+		H.logger(c, utils.CreateOrgGroup, err.Error(), "", "error", utils.ErrorParse, session.UserSlug)
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	reqBody.Name = strings.TrimSpace(reqBody.Name)
+
+	if nameErrors := utils.ValidateOrgName(reqBody.Name); nameErrors != nil {
+		H.logger(c, utils.CreateOrgGroup, "", member.OrgSlug, "warn", utils.ErrorOrg, session.UserSlug)
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, map[string][]string{
+			"name": nameErrors,
+		}, nil)
+	}
+
+	group := models.OrgGroup{
+		OrgSlug:   member.OrgSlug,
+		Name:      reqBody.Name,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	if slug, err := utils.GenerateSlug(utils.OrgGroupSlugLength); err != nil {
+		H.logger(
+			c, utils.CreateOrgGroup, err.Error(), "", "error", "Failed generate group.Slug",
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	} else {
+		group.Slug = slug
+	}
+
+	if result := H.DBs.ApiGateway.Omit("Organization").Create(&group); result.Error != nil {
+		H.logger(
+			c, utils.CreateOrgGroup, result.Error.Error(), member.OrgSlug, "error",
+			utils.ErrorFailedDB, session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	return c.Status(201).JSON(&OrgGroupResponse{
+		GroupSlug: group.Slug,
+		Name:      group.Name,
+		CreatedAt: group.CreatedAt,
+	})
+}
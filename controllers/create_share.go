@@ -0,0 +1,127 @@
+package controllers
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/models"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+// The key for Ciphertext isn't part of the request; the client puts it in the fragment of the
+// link it builds from share_slug
+type CreateShareRequestBody struct {
+	Ciphertext string `json:"ciphertext"`
+	Label      string `json:"label"`
+	ExpiresIn  int    `json:"expires_in"`
+	MaxViews   int    `json:"max_views"`
+}
+
+func (H Handler) CreateShare(c *fiber.Ctx) error {
+	if header := c.Get("Client-Operation"); header != utils.CreateShare {
+		H.logger(c, utils.CreateShare, header, "", "warn", utils.ErrorClientOperation, "")
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	var session *models.ClientSession
+	var ok bool
+
+	if session, ok = c.UserContext().Value(sessionContextKey{}).(*models.ClientSession); !ok {
+		H.logger(c, utils.CreateShare, "", "", "error", "Failed session.User context", "")
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	reqBody := CreateShareRequestBody{}
+
+	if err := c.BodyParser(&reqBody); err != nil {
+		H.logger(c, utils.CreateShare, err.Error(), "", "error", utils.ErrorParse, session.UserSlug)
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	if fieldErrors := utils.ValidateShare(
+		reqBody.Ciphertext, reqBody.Label, reqBody.ExpiresIn, reqBody.MaxViews,
+	); fieldErrors != nil {
+		H.logger(c, utils.CreateShare, "", "", "warn", utils.ErrorShare, session.UserSlug)
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, fieldErrors, nil)
+	}
+
+	now := time.Now().UTC()
+
+	if result := H.DBs.ApiGateway.Where("expires_at <= ?", now).Delete(&models.Share{});
+	result.Error != nil {
+		H.logger(
+			c, utils.CreateShare, result.Error.Error(), "", "error", utils.ErrorFailedDB,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	var count int64
+
+	if result := H.DBs.ApiGateway.Model(&models.Share{}).
+		Where("user_slug = ?", session.UserSlug).Count(&count); result.Error != nil {
+		H.logger(
+			c, utils.CreateShare, result.Error.Error(), "", "error", utils.ErrorFailedDB,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	if count >= int64(utils.SharesPerUserMax) {
+		H.logger(c, utils.CreateShare, "", "", "warn", utils.ErrorShareLimit, session.UserSlug)
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, []string{
+			"You can have at most " + strconv.Itoa(utils.SharesPerUserMax) +
+			" active shares - revoke one first",
+		})
+	}
+
+	share := models.Share{
+		UserSlug:   session.UserSlug,
+		Label:      reqBody.Label,
+		Ciphertext: reqBody.Ciphertext,
+		MaxViews:   reqBody.MaxViews,
+		CreatedAt:  now,
+		ExpiresAt:  now.Add(utils.ShareDefaultExpiresIn),
+	}
+
+	if share.MaxViews == 0 {
+		share.MaxViews = 1
+	}
+
+	share.ViewsLeft = share.MaxViews
+
+	if reqBody.ExpiresIn != 0 {
+		share.ExpiresAt = now.Add(time.Duration(reqBody.ExpiresIn) * time.Second)
+	}
+
+	if slug, err := utils.GenerateSlug(utils.ShareSlugLength); err != nil {
+		H.logger(
+			c, utils.CreateShare, err.Error(), "", "error", "Failed generate share.Slug",
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	} else {
+		share.Slug = slug
+	}
+
+	if result := H.DBs.ApiGateway.Create(&share); result.Error != nil {
+		H.logger(
+			c, utils.CreateShare, result.Error.Error(), "", "error", utils.ErrorFailedDB,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	return c.Status(201).JSON(&share)
+}
@@ -0,0 +1,42 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/models"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+func (H Handler) DeclineOrgInvitation(c *fiber.Ctx) error {
+	if header := c.Get("Client-Operation"); header != utils.DeclineOrgInvitation {
+		H.logger(c, utils.DeclineOrgInvitation, header, "", "warn", utils.ErrorClientOperation, "")
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	var session *models.ClientSession
+	var ok bool
+
+	if session, ok = c.UserContext().Value(sessionContextKey{}).(*models.ClientSession); !ok {
+		H.logger(c, utils.DeclineOrgInvitation, "", "", "error", "Failed session.User context", "")
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	invitation, err := H.findOrgInvitation(c, utils.DeclineOrgInvitation, session)
+
+	if invitation == nil {
+		return err
+	}
+
+	if result := H.DBs.ApiGateway.Delete(invitation); result.Error != nil {
+		H.logger(
+			c, utils.DeclineOrgInvitation, result.Error.Error(), "", "error", utils.ErrorFailedDB,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	return c.SendStatus(204)
+}
@@ -0,0 +1,62 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/models"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+// DeleteOrgGroup deletes a group with its memberships and vault grants
+func (H Handler) DeleteOrgGroup(c *fiber.Ctx) error {
+	if header := c.Get("Client-Operation"); header != utils.DeleteOrgGroup {
+		H.logger(c, utils.DeleteOrgGroup, header, "", "warn", utils.ErrorClientOperation, "")
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	var session *models.ClientSession
+	var ok bool
+
+	if session, ok = c.UserContext().Value(sessionContextKey{}).(*models.ClientSession); !ok {
+		H.logger(c, utils.DeleteOrgGroup, "", "", "error", "Failed session.User context", "")
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	member, err := H.requireOrgRole(c, utils.DeleteOrgGroup, session, utils.OrgRoleAdmin)
+
+	if member == nil {
+		return err
+	}
+
+	group, err := H.findOrgGroup(c, utils.DeleteOrgGroup, session, member.OrgSlug)
+
+	if group == nil {
+		return err
+	}
+
+	if err = H.DBs.ApiGateway.Transaction(func(tx *gorm.DB) error {
+		if result := tx.Where("group_slug = ?", group.Slug).Delete(&models.OrgVaultGrant{});
+		result.Error != nil {
+			return result.Error
+		}
+
+		if result := tx.Where("group_slug = ?", group.Slug).Delete(&models.OrgGroupMember{});
+		result.Error != nil {
+			return result.Error
+		}
+
+		return tx.Delete(group).Error
+	}); err != nil {
+		H.logger(
+			c, utils.DeleteOrgGroup, err.Error(), member.OrgSlug, "error", utils.ErrorFailedDB,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	return c.SendStatus(204)
+}
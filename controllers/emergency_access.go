@@ -0,0 +1,195 @@
+package controllers
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/models"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+// Names the emergency contact a vaults request is made as, to read the grantor's vaults
+const emergencyContactSlugHeader string = "Emergency-Contact-Slug"
+
+type EmergencyContactResponse struct {
+	ContactSlug  string     `json:"contact_slug"`
+	EmailAddress string     `json:"email_address"`
+	WaitingDays  int        `json:"waiting_days"`
+	VaultSlugs   []string   `json:"vault_slugs"`
+	RequestedAt  *time.Time `json:"requested_at"`
+	OpensAt      *time.Time `json:"opens_at"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// What an emergency contact sees of a grant. The vaults are only listed once access is open.
+type EmergencyGrantResponse struct {
+	ContactSlug         string     `json:"contact_slug"`
+	GrantorName         string     `json:"grantor_name"`
+	GrantorEmailAddress string     `json:"grantor_email_address"`
+	WaitingDays         int        `json:"waiting_days"`
+	RequestedAt         *time.Time `json:"requested_at"`
+	OpensAt             *time.Time `json:"opens_at"`
+	AccessOpen          bool       `json:"access_open"`
+	VaultSlugs          []string   `json:"vault_slugs"`
+}
+
+func newEmergencyContactResponse(
+	contact *models.EmergencyContact, vaultSlugs []string,
+) EmergencyContactResponse {
+	if vaultSlugs == nil {
+		vaultSlugs = []string{}
+	}
+
+	return EmergencyContactResponse{
+		ContactSlug:  contact.Slug,
+		EmailAddress: contact.EmailAddress,
+		WaitingDays:  contact.WaitingDays,
+		VaultSlugs:   vaultSlugs,
+		RequestedAt:  contact.RequestedAt,
+		OpensAt:      emergencyOpensAt(contact),
+		CreatedAt:    contact.CreatedAt,
+	}
+}
+
+func newEmergencyGrantResponse(
+	contact *models.EmergencyContact, vaultSlugs []string,
+) EmergencyGrantResponse {
+	resBody := EmergencyGrantResponse{
+		ContactSlug:         contact.Slug,
+		GrantorName:         contact.Grantor.Name,
+		GrantorEmailAddress: contact.Grantor.EmailAddress,
+		WaitingDays:         contact.WaitingDays,
+		RequestedAt:         contact.RequestedAt,
+		OpensAt:             emergencyOpensAt(contact),
+		AccessOpen:          emergencyAccessOpen(contact),
+		VaultSlugs:          []string{},
+	}
+
+	if resBody.AccessOpen && vaultSlugs != nil {
+		resBody.VaultSlugs = vaultSlugs
+	}
+
+	return resBody
+}
+
+func emergencyOpensAt(contact *models.EmergencyContact) *time.Time {
+	if contact.RequestedAt == nil {
+		return nil
+	}
+
+	opensAt := utils.EmergencyAccessOpensAt(*contact.RequestedAt, contact.WaitingDays)
+
+	return &opensAt
+}
+
+func emergencyAccessOpen(contact *models.EmergencyContact) bool {
+	opensAt := emergencyOpensAt(contact)
+
+	return opensAt != nil && !time.Now().UTC().Before(*opensAt)
+}
+
+// emergencyContactVaultSlugs returns the vaults selected for each of the contacts
+func (H Handler) emergencyContactVaultSlugs(contactSlugs []string) (map[string][]string, error) {
+	vaults := []models.EmergencyContactVault{}
+	vaultSlugs := map[string][]string{}
+
+	if len(contactSlugs) == 0 {
+		return vaultSlugs, nil
+	}
+
+	if result := H.DBs.ApiGateway.Where("contact_slug IN ?", contactSlugs).
+		Order("vault_slug").Find(&vaults); result.Error != nil {
+		return nil, result.Error
+	}
+
+	for _, vault := range vaults {
+		vaultSlugs[vault.ContactSlug] = append(vaultSlugs[vault.ContactSlug], vault.VaultSlug)
+	}
+
+	return vaultSlugs, nil
+}
+
+// findEmergencyContact returns the emergency contact in the :slug param. The grantor finds the
+// contacts they designated, and anyone else the ones designating their email address. Responds
+// itself and returns nil if there's no such contact.
+func (H Handler) findEmergencyContact(
+	c *fiber.Ctx, clientOperation string, session *models.ClientSession, asGrantor bool,
+) (*models.EmergencyContact, error) {
+	slug := c.Params("slug")
+	var contact models.EmergencyContact
+
+	if !utils.SlugRegexp.MatchString(slug) {
+		H.logger(c, clientOperation, slug, "", "warn", utils.ErrorEmergencyNotFound, session.UserSlug)
+
+		return nil, utils.RespondWithError(c, 404, utils.ErrorNotFound, nil, nil)
+	}
+
+	query := H.DBs.ApiGateway.Preload("Grantor").Where("slug = ?", slug)
+
+	if asGrantor {
+		query = query.Where("grantor_slug = ?", session.UserSlug)
+	} else {
+		query = query.Where("LOWER(email_address) = LOWER(?)", session.User.EmailAddress)
+	}
+
+	if result := query.Limit(1).Find(&contact); result.Error != nil {
+		H.logger(
+			c, clientOperation, result.Error.Error(), "", "error", utils.ErrorFailedDB,
+			session.UserSlug,
+		)
+
+		return nil, utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	} else if result.RowsAffected == 0 {
+		H.logger(c, clientOperation, slug, "", "warn", utils.ErrorEmergencyNotFound, session.UserSlug)
+
+		return nil, utils.RespondWithError(c, 404, utils.ErrorNotFound, nil, nil)
+	}
+
+	return &contact, nil
+}
+
+// emergencyVaultGrant returns what an emergency contact may do in the grantor's vaults, or nil
+// if the user isn't the contact. Once access is open, they may read the selected vaults and
+// nothing else; until then, nothing.
+func (H Handler) emergencyVaultGrant(
+	contactSlug, vaultSlug string, user *models.User,
+) (*vaultGrant, error) {
+	var contact models.EmergencyContact
+
+	result := H.DBs.ApiGateway.Where(
+		"slug = ? AND LOWER(email_address) = LOWER(?)", contactSlug, user.EmailAddress,
+	).Limit(1).Find(&contact)
+
+	if result.Error != nil {
+		return nil, result.Error
+	} else if result.RowsAffected == 0 {
+		return nil, nil
+	}
+
+	grant := vaultGrant{OwnerSlug: contact.GrantorSlug}
+
+	if vaultSlug == "" || !emergencyAccessOpen(&contact) {
+		return &grant, nil
+	}
+
+	var count int64
+
+	if result = H.DBs.ApiGateway.Model(&models.EmergencyContactVault{}).
+		Where("contact_slug = ? AND vault_slug = ?", contact.Slug, vaultSlug).
+		Count(&count); result.Error != nil {
+		return nil, result.Error
+	} else if count > 0 {
+		grant.Role = utils.VaultRoleRead
+	}
+
+	return &grant, nil
+}
+
+func (H Handler) sendEmergencyEmail(
+	subject, emailAddress, templateFile string, data map[string]string,
+) error {
+	data["Link"] = H.Conf.APP_SCHEME + "://" + H.Conf.APP_DOMAIN + "/emergency_access"
+
+	return H.sendEmail(subject, H.Conf.SUPPORT_EMAIL, []string{emailAddress}, templateFile, data)
+}
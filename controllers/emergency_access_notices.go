@@ -0,0 +1,150 @@
+package controllers
+
+import (
+	"log"
+	"time"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/config"
+	"github.com/liobrdev/simplepasswords_api_gateway/databases"
+	"github.com/liobrdev/simplepasswords_api_gateway/models"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+const emergencyNoticeInterval = time.Duration(1) * time.Hour
+
+// The grantor is reminded this long before a waiting period ends, unless the period is no longer
+// than this and the request email was the reminder
+const emergencyReminderLead = time.Duration(24) * time.Hour
+
+// RunEmergencyAccessNotices emails the grantor a reminder as a contact's waiting period nears its
+// end, then both of them once it's over. It runs in a single process per instance.
+func RunEmergencyAccessNotices(conf *config.AppConfig, dbs *databases.Databases) {
+	H := Handler{DBs: dbs, Conf: conf}
+
+	for {
+		time.Sleep(emergencyNoticeInterval)
+
+		if err := H.SendEmergencyAccessNotices(time.Now().UTC()); err != nil {
+			log.Printf("Failed emergency access notices: %s", err.Error())
+		}
+	}
+}
+
+// SendEmergencyAccessNotices sends the notices due at now. Each contact is marked before it's
+// emailed, and only if it wasn't already, so no notice goes out twice, even with several
+// instances running the job.
+func (H Handler) SendEmergencyAccessNotices(now time.Time) error {
+	contacts := []models.EmergencyContact{}
+
+	if result := H.DBs.ApiGateway.Preload("Grantor").
+		Where("requested_at IS NOT NULL AND opened_at IS NULL").Find(&contacts); result.Error != nil {
+		return result.Error
+	}
+
+	for i := range contacts {
+		contact := &contacts[i]
+		opensAt := utils.EmergencyAccessOpensAt(*contact.RequestedAt, contact.WaitingDays)
+		remindAt := opensAt.Add(-emergencyReminderLead)
+
+		if !now.Before(opensAt) {
+			H.sendEmergencyOpened(contact, now)
+		} else if contact.RemindedAt == nil && !now.Before(remindAt) &&
+			remindAt.After(*contact.RequestedAt) {
+			H.sendEmergencyReminder(contact, opensAt, now)
+		}
+	}
+
+	return nil
+}
+
+// markEmergencyNotice sets the column to now, unless it's set already or the request changed
+// since the contact was read. Returns whether this call set it.
+func (H Handler) markEmergencyNotice(
+	contact *models.EmergencyContact, column string, now time.Time,
+) bool {
+	result := H.DBs.ApiGateway.Model(&models.EmergencyContact{}).Where(
+		"slug = ? AND requested_at = ? AND " + column + " IS NULL", contact.Slug,
+		contact.RequestedAt,
+	).Update(column, now)
+
+	if result.Error != nil {
+		H.jobLogger(
+			utils.EmergencyAccessNotices, result.Error.Error(), contact.Slug, "error",
+			utils.ErrorFailedDB, contact.GrantorSlug,
+		)
+
+		return false
+	}
+
+	return result.RowsAffected > 0
+}
+
+func (H Handler) sendEmergencyReminder(
+	contact *models.EmergencyContact, opensAt, now time.Time,
+) {
+	if !H.markEmergencyNotice(contact, "reminded_at", now) {
+		return
+	}
+
+	// Audit trail - every stage of emergency access is recorded
+	H.jobLogger(
+		utils.EmergencyAccessNotices, opensAt.String(), contact.Slug, "info",
+		utils.InfoEmergencyReminded, contact.GrantorSlug,
+	)
+
+	if H.Conf.ENVIRONMENT != "testing" {
+		if err := H.sendEmergencyEmail(
+			"Emergency access to your vaults opens soon", contact.Grantor.EmailAddress,
+			"email_emergency_reminder.html", map[string]string{
+				"Name":         contact.Grantor.Name,
+				"ContactEmail": contact.EmailAddress,
+				"OpensAt":      opensAt.Format(time.RFC1123),
+			},
+		); err != nil {
+			H.jobLogger(
+				utils.EmergencyAccessNotices, err.Error(), contact.Slug, "error",
+				"Failed send emergency reminder email", contact.GrantorSlug,
+			)
+		}
+	}
+}
+
+func (H Handler) sendEmergencyOpened(contact *models.EmergencyContact, now time.Time) {
+	if !H.markEmergencyNotice(contact, "opened_at", now) {
+		return
+	}
+
+	// Audit trail - every stage of emergency access is recorded
+	H.jobLogger(
+		utils.EmergencyAccessNotices, contact.EmailAddress, contact.Slug, "info",
+		utils.InfoEmergencyOpened, contact.GrantorSlug,
+	)
+
+	if H.Conf.ENVIRONMENT == "testing" {
+		return
+	}
+
+	if err := H.sendEmergencyEmail(
+		"Emergency access to your vaults is now open", contact.Grantor.EmailAddress,
+		"email_emergency_granted.html", map[string]string{
+			"Name":         contact.Grantor.Name,
+			"ContactEmail": contact.EmailAddress,
+		},
+	); err != nil {
+		H.jobLogger(
+			utils.EmergencyAccessNotices, err.Error(), contact.Slug, "error",
+			"Failed send emergency granted email", contact.GrantorSlug,
+		)
+	}
+
+	if err := H.sendEmergencyEmail(
+		"Your emergency access to " + contact.Grantor.Name + "'s vaults is open",
+		contact.EmailAddress, "email_emergency_opened.html",
+		map[string]string{"GrantorName": contact.Grantor.Name},
+	); err != nil {
+		H.jobLogger(
+			utils.EmergencyAccessNotices, err.Error(), contact.Slug, "error",
+			"Failed send emergency opened email", contact.GrantorSlug,
+		)
+	}
+}
@@ -0,0 +1,261 @@
+package controllers
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/models"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+const (
+	GenerateModePassword   string = "password"
+	GenerateModePassphrase string = "passphrase"
+)
+
+const (
+	generatePasswordDefaultLength  int = 20
+	generatePasswordMinLength      int = 8
+	generatePasswordMaxLength      int = 128
+	generatePassphraseDefaultWords int = 6
+	generatePassphraseMinWords     int = 4
+	generatePassphraseMaxWords     int = 20
+)
+
+const generatePassphraseDefaultSeparator string = "-"
+
+var generatePassphraseSeparators = []string{"-", "_", ".", " ", ""}
+
+// Omitted class flags default to enabled, omitted minimums to one of each enabled class
+type GeneratePasswordRequestBody struct {
+	Mode             string  `json:"mode"`
+	Length           *int    `json:"length"`
+	Uppercase        *bool   `json:"uppercase"`
+	Lowercase        *bool   `json:"lowercase"`
+	Digits           *bool   `json:"digits"`
+	Special          *bool   `json:"special"`
+	ExcludeAmbiguous bool    `json:"exclude_ambiguous"`
+	MinUppercase     *int    `json:"min_uppercase"`
+	MinLowercase     *int    `json:"min_lowercase"`
+	MinDigits        *int    `json:"min_digits"`
+	MinSpecial       *int    `json:"min_special"`
+	Words            *int    `json:"words"`
+	Separator        *string `json:"separator"`
+	Capitalize       bool    `json:"capitalize"`
+	IncludeNumber    bool    `json:"include_number"`
+}
+
+type GeneratePasswordResponseBody struct {
+	Mode        string  `json:"mode"`
+	Password    string  `json:"password"`
+	EntropyBits float64 `json:"entropy_bits"`
+}
+
+func (H Handler) GeneratePassword(c *fiber.Ctx) error {
+	if header := c.Get("Client-Operation"); header != utils.GeneratePasswordOp {
+		H.logger(c, utils.GeneratePasswordOp, header, "", "warn", utils.ErrorClientOperation, "")
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	var session *models.ClientSession
+	var ok bool
+
+	if session, ok = c.UserContext().Value(sessionContextKey{}).(*models.ClientSession); !ok {
+		H.logger(c, utils.GeneratePasswordOp, "", "", "error", "Failed session.User context", "")
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	reqBody := GeneratePasswordRequestBody{}
+
+	if err := c.BodyParser(&reqBody); err != nil {
+		H.logger(
+			c, utils.GeneratePasswordOp, err.Error(), "", "error", utils.ErrorParse, session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	var password string
+	var entropy float64
+	var err error
+
+	if reqBody.Mode == GenerateModePassphrase {
+		opts, fieldErrors := validateGeneratePassphrase(&reqBody)
+
+		if fieldErrors != nil {
+			H.logger(
+				c, utils.GeneratePasswordOp, reqBody.Mode, "", "warn", utils.ErrorGeneratePassword,
+				session.UserSlug,
+			)
+
+			return utils.RespondWithError(c, 400, utils.ErrorBadRequest, fieldErrors, nil)
+		}
+
+		password, err = utils.GeneratePassphrase(opts)
+		entropy = utils.PassphraseEntropy(opts)
+	} else {
+		opts, fieldErrors, nonFieldErrors := validateGeneratePassword(&reqBody)
+
+		if fieldErrors != nil || nonFieldErrors != nil {
+			H.logger(
+				c, utils.GeneratePasswordOp, reqBody.Mode, "", "warn", utils.ErrorGeneratePassword,
+				session.UserSlug,
+			)
+
+			return utils.RespondWithError(
+				c, 400, utils.ErrorBadRequest, fieldErrors, nonFieldErrors,
+			)
+		}
+
+		password, err = utils.GeneratePasswordWithOptions(opts)
+		entropy = utils.PasswordEntropy(opts)
+	}
+
+	if err != nil {
+		H.logger(
+			c, utils.GeneratePasswordOp, err.Error(), reqBody.Mode, "error",
+			utils.ErrorGeneratePassword, session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	return c.Status(200).JSON(&GeneratePasswordResponseBody{
+		Mode:        reqBody.Mode,
+		Password:    password,
+		EntropyBits: math.Round(entropy * 10) / 10,
+	})
+}
+
+func validateGeneratePassword(
+	reqBody *GeneratePasswordRequestBody,
+) (*utils.PasswordOptions, map[string][]string, []string) {
+	fieldErrors := map[string][]string{}
+	var nonFieldErrors []string
+
+	if reqBody.Mode == "" {
+		reqBody.Mode = GenerateModePassword
+	} else if reqBody.Mode != GenerateModePassword {
+		fieldErrors["mode"] = append(fieldErrors["mode"], "Must be one of password, passphrase")
+	}
+
+	opts := utils.PasswordOptions{
+		Length:           generatePasswordDefaultLength,
+		Uppercase:        reqBody.Uppercase == nil || *reqBody.Uppercase,
+		Lowercase:        reqBody.Lowercase == nil || *reqBody.Lowercase,
+		Digits:           reqBody.Digits == nil || *reqBody.Digits,
+		Special:          reqBody.Special == nil || *reqBody.Special,
+		ExcludeAmbiguous: reqBody.ExcludeAmbiguous,
+	}
+
+	if reqBody.Length != nil {
+		if n := *reqBody.Length; n < generatePasswordMinLength || n > generatePasswordMaxLength {
+			fieldErrors["length"] = append(
+				fieldErrors["length"],
+				"Must be an integer from " + strconv.Itoa(generatePasswordMinLength) + " to " +
+				strconv.Itoa(generatePasswordMaxLength),
+			)
+		} else {
+			opts.Length = n
+		}
+	}
+
+	if !opts.Uppercase && !opts.Lowercase && !opts.Digits && !opts.Special {
+		nonFieldErrors = append(nonFieldErrors, "Must enable at least one character class")
+	}
+
+	validateMin := func(field string, enabled bool, min *int) int {
+		if min == nil {
+			if enabled {
+				return 1
+			}
+
+			return 0
+		}
+
+		if *min < 0 || *min > generatePasswordMaxLength {
+			fieldErrors[field] = append(
+				fieldErrors[field],
+				"Must be an integer from 0 to " + strconv.Itoa(generatePasswordMaxLength),
+			)
+		} else if !enabled && *min > 0 {
+			fieldErrors[field] = append(fieldErrors[field], "Character class is disabled")
+		}
+
+		return *min
+	}
+
+	opts.MinUppercase = validateMin("min_uppercase", opts.Uppercase, reqBody.MinUppercase)
+	opts.MinLowercase = validateMin("min_lowercase", opts.Lowercase, reqBody.MinLowercase)
+	opts.MinDigits = validateMin("min_digits", opts.Digits, reqBody.MinDigits)
+	opts.MinSpecial = validateMin("min_special", opts.Special, reqBody.MinSpecial)
+
+	if len(fieldErrors) == 0 && nonFieldErrors == nil &&
+	opts.MinUppercase + opts.MinLowercase + opts.MinDigits + opts.MinSpecial > opts.Length {
+		nonFieldErrors = append(nonFieldErrors, "Required minimums exceed length")
+	}
+
+	if len(fieldErrors) == 0 {
+		fieldErrors = nil
+	}
+
+	if fieldErrors != nil || nonFieldErrors != nil {
+		return nil, fieldErrors, nonFieldErrors
+	}
+
+	return &opts, nil, nil
+}
+
+func validateGeneratePassphrase(
+	reqBody *GeneratePasswordRequestBody,
+) (*utils.PassphraseOptions, map[string][]string) {
+	fieldErrors := map[string][]string{}
+
+	opts := utils.PassphraseOptions{
+		Words:         generatePassphraseDefaultWords,
+		Separator:     generatePassphraseDefaultSeparator,
+		Capitalize:    reqBody.Capitalize,
+		IncludeNumber: reqBody.IncludeNumber,
+	}
+
+	if reqBody.Words != nil {
+		if n := *reqBody.Words; n < generatePassphraseMinWords || n > generatePassphraseMaxWords {
+			fieldErrors["words"] = append(
+				fieldErrors["words"],
+				"Must be an integer from " + strconv.Itoa(generatePassphraseMinWords) + " to " +
+				strconv.Itoa(generatePassphraseMaxWords),
+			)
+		} else {
+			opts.Words = n
+		}
+	}
+
+	if reqBody.Separator != nil {
+		valid := false
+
+		for _, sep := range generatePassphraseSeparators {
+			if *reqBody.Separator == sep {
+				valid = true
+				break
+			}
+		}
+
+		if !valid {
+			fieldErrors["separator"] = append(
+				fieldErrors["separator"], "Must be one of \"-\", \"_\", \".\", \" \", \"\"",
+			)
+		} else {
+			opts.Separator = *reqBody.Separator
+		}
+	}
+
+	if len(fieldErrors) > 0 {
+		return nil, fieldErrors
+	}
+
+	return &opts, nil
+}
@@ -12,14 +12,23 @@ import (
 	"github.com/twilio/twilio-go"
 	twilioApi "github.com/twilio/twilio-go/rest/api/v2010"
 
+	"github.com/liobrdev/simplepasswords_api_gateway/breached"
 	"github.com/liobrdev/simplepasswords_api_gateway/config"
 	"github.com/liobrdev/simplepasswords_api_gateway/databases"
+	"github.com/liobrdev/simplepasswords_api_gateway/idempotency"
 	"github.com/liobrdev/simplepasswords_api_gateway/models"
+	"github.com/liobrdev/simplepasswords_api_gateway/upstreams"
 )
 
+// Set in c.Locals by handlers whose request bodies must never reach the logs
+type redactRequestBodyKey struct{}
+
 type Handler struct {
-	DBs  *databases.Databases
-	Conf *config.AppConfig
+	DBs         *databases.Databases
+	Conf        *config.AppConfig
+	Vaults      *upstreams.Pool
+	Breached    *breached.Dataset
+	Idempotency *idempotency.Store
 }
 
 func (H Handler) createLog(
@@ -33,6 +42,12 @@ func (H Handler) createLog(
 		clientIP = c.IP()
 	}
 
+	requestBody := string(c.Body())
+
+	if redact, _ := c.Locals(redactRequestBodyKey{}).(bool); redact && requestBody != "" {
+		requestBody = "[redacted]"
+	}
+
 	H.DBs.Logger.Create(&models.Log{
 		Caller:          caller,
 		ClientIP:        clientIP,
@@ -42,7 +57,7 @@ func (H Handler) createLog(
 		Extra:           extra,
 		Level:           level,
 		Message:         message,
-		RequestBody:     string(c.Body()),
+		RequestBody:     requestBody,
 		UserSlug:				 userSlug,
 	})
 }
@@ -58,6 +73,21 @@ func (H Handler) logger(
 	)
 }
 
+// jobLogger logs for a background job, which has no request to take a client IP or body from
+func (H Handler) jobLogger(clientOperation, detail, extra, level, message, userSlug string) {
+	_, file, line, _ := runtime.Caller(1)
+
+	H.DBs.Logger.Create(&models.Log{
+		Caller:          file + ":" + strconv.FormatInt(int64(line), 10),
+		ClientOperation: clientOperation,
+		Detail:          detail,
+		Extra:           extra,
+		Level:           level,
+		Message:         message,
+		UserSlug:				 userSlug,
+	})
+}
+
 func (H Handler) sendSMS(phoneNumber, messageBody string) error {
 	client := twilio.NewRestClientWithParams(twilio.ClientParams{
 		Username: H.Conf.TWILIO_ACCOUNT_SID,
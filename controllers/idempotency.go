@@ -0,0 +1,114 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/idempotency"
+	"github.com/liobrdev/simplepasswords_api_gateway/models"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+const (
+	idempotencyKeyHeader     string = "Idempotency-Key"
+	idempotentReplayedHeader string = "Idempotent-Replayed"
+)
+
+// Creates and moves are the operations a client can't blindly retry after a dropped
+// connection, so only these honour an Idempotency-Key header
+var idempotentOps = map[string]bool{
+	utils.CreateVault:      true,
+	utils.CreateEntry:      true,
+	utils.CreateSecret:     true,
+	utils.MoveSecret:       true,
+	utils.MoveEntry:        true,
+	utils.UploadAttachment: true,
+}
+
+// CheckIdempotencyKey replays the stored response for a repeated Idempotency-Key instead of running
+// the handler again. Only successful responses are kept, so a failed request can be retried
+// with the same key once its cause is fixed.
+func (H Handler) CheckIdempotencyKey(c *fiber.Ctx) error {
+	key := c.Get(idempotencyKeyHeader)
+	clientOperation := c.Get("Client-Operation")
+
+	if key == "" || !idempotentOps[clientOperation] {
+		return c.Next()
+	}
+
+	if !utils.IdempotencyKeyRegexp.MatchString(key) {
+		H.logger(c, clientOperation, key, "", "warn", utils.ErrorIdempotencyKey, "")
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	var session *models.ClientSession
+	var ok bool
+
+	if session, ok = c.UserContext().Value(sessionContextKey{}).(*models.ClientSession); !ok {
+		H.logger(c, clientOperation, "", "", "error", "Failed session.User context", "")
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	ctx := c.UserContext()
+	storeKey := idempotency.Key(session.UserSlug, clientOperation, key)
+	fingerprint := idempotency.Fingerprint(c.Method(), c.Path(), c.Body())
+	stored, err := H.Idempotency.Begin(ctx, storeKey, session.UserSlug, fingerprint)
+
+	switch err {
+	case nil:
+	case idempotency.ErrMismatch:
+		H.logger(
+			c, clientOperation, key, "", "warn", utils.ErrorIdempotencyMismatch, session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 422, utils.ErrorIdempotencyKeyReused, nil, nil)
+	case idempotency.ErrInProgress:
+		H.logger(
+			c, clientOperation, key, "", "warn", utils.ErrorIdempotencyInProgress, session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 409, utils.ErrorIdempotencyKeyInProgress, nil, nil)
+	default:
+		H.logger(
+			c, clientOperation, err.Error(), "", "error", utils.ErrorIdempotency, session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	if stored != nil {
+		c.Set(idempotentReplayedHeader, "true")
+
+		if stored.ContentType != "" {
+			c.Set(fiber.HeaderContentType, stored.ContentType)
+		}
+
+		return c.Status(stored.StatusCode).Send(stored.Body)
+	}
+
+	if err = c.Next(); err != nil {
+		H.Idempotency.Release(ctx, storeKey)
+
+		return err
+	}
+
+	if statusCode := c.Response().StatusCode(); statusCode >= 400 {
+		if err = H.Idempotency.Release(ctx, storeKey); err != nil {
+			H.logger(
+				c, clientOperation, err.Error(), "", "error", utils.ErrorIdempotency, session.UserSlug,
+			)
+		}
+	} else if err = H.Idempotency.Complete(ctx, storeKey, fingerprint, &idempotency.Response{
+		StatusCode:  statusCode,
+		ContentType: string(c.Response().Header.ContentType()),
+		Body:        append([]byte(nil), c.Response().Body()...),
+	}); err != nil {
+		// The request itself succeeded, so the client still gets its response
+		H.logger(
+			c, clientOperation, err.Error(), "", "error", utils.ErrorIdempotency, session.UserSlug,
+		)
+	}
+
+	return nil
+}
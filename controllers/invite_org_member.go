@@ -0,0 +1,169 @@
+package controllers
+
+import (
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/models"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+type InviteOrgMemberRequestBody struct {
+	EmailAddress string `json:"email_address"`
+	Role         string `json:"role"`
+}
+
+// InviteOrgMember emails an invitation to join an organization. Admins can invite admins and
+// members; only owners can invite owners. Inviting an address again replaces the pending
+// invitation and restarts its expiry.
+func (H Handler) InviteOrgMember(c *fiber.Ctx) error {
+	if header := c.Get("Client-Operation"); header != utils.InviteOrgMember {
+		H.logger(c, utils.InviteOrgMember, header, "", "warn", utils.ErrorClientOperation, "")
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	var session *models.ClientSession
+	var ok bool
+
+	if session, ok = c.UserContext().Value(sessionContextKey{}).(*models.ClientSession); !ok {
+		H.logger(c, utils.InviteOrgMember, "", "", "error", "Failed session.User context", "")
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	member, err := H.requireOrgRole(c, utils.InviteOrgMember, session, utils.OrgRoleAdmin)
+
+	if member == nil {
+		return err
+	}
+
+	reqBody := InviteOrgMemberRequestBody{}
+
+	if err = c.BodyParser(&reqBody); err != nil {
+		H.logger(
+			c, utils.InviteOrgMember, err.Error(), "", "error", utils.ErrorParse, session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	reqBody.EmailAddress = strings.TrimSpace(reqBody.EmailAddress)
+	fieldErrors := map[string][]string{}
+
+	if !utils.EmailRegexp.MatchString(reqBody.EmailAddress) {
+		fieldErrors["email_address"] = []string{"Invalid email address"}
+	}
+
+	if !utils.ValidOrgRole(reqBody.Role) {
+		fieldErrors["role"] = []string{"Must be one of: " + strings.Join(utils.OrgRoles, ", ")}
+	}
+
+	if len(fieldErrors) > 0 {
+		H.logger(
+			c, utils.InviteOrgMember, "", member.OrgSlug, "warn", utils.ErrorInvitation,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, fieldErrors, nil)
+	}
+
+	if !utils.OrgRoleAtLeast(member.Role, reqBody.Role) {
+		H.logger(
+			c, utils.InviteOrgMember, member.Role, member.OrgSlug, "warn", utils.ErrorOrgRole,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 403, utils.ErrorOrgPermission, nil, nil)
+	}
+
+	var count int64
+
+	if result := H.DBs.ApiGateway.Model(&models.OrgMember{}).
+		Joins("JOIN users ON users.slug = org_members.user_slug").
+		Where("org_members.org_slug = ? AND LOWER(users.email_address) = LOWER(?)",
+			member.OrgSlug, reqBody.EmailAddress).
+		Count(&count); result.Error != nil {
+		H.logger(
+			c, utils.InviteOrgMember, result.Error.Error(), member.OrgSlug, "error",
+			utils.ErrorFailedDB, session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	} else if count > 0 {
+		H.logger(
+			c, utils.InviteOrgMember, reqBody.EmailAddress, member.OrgSlug, "warn",
+			utils.ErrorInvitation, session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 409, utils.ErrorAlreadyOrgMember, nil, nil)
+	}
+
+	now := time.Now().UTC()
+
+	invitation := models.OrgInvitation{
+		OrgSlug:      member.OrgSlug,
+		Organization: member.Organization,
+		InviterSlug:  session.UserSlug,
+		Inviter:      session.User,
+		EmailAddress: reqBody.EmailAddress,
+		Role:         reqBody.Role,
+		CreatedAt:    now,
+		ExpiresAt:    now.Add(utils.OrgInvitationTTL),
+	}
+
+	if slug, err := utils.GenerateSlug(utils.OrgInvitationSlugLength); err != nil {
+		H.logger(
+			c, utils.InviteOrgMember, err.Error(), "", "error", "Failed generate invitation.Slug",
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	} else {
+		invitation.Slug = slug
+	}
+
+	if err = H.DBs.ApiGateway.Transaction(func(tx *gorm.DB) error {
+		if result := tx.Where(
+			"org_slug = ? AND LOWER(email_address) = LOWER(?)", member.OrgSlug, reqBody.EmailAddress,
+		).Delete(&models.OrgInvitation{}); result.Error != nil {
+			return result.Error
+		}
+
+		return tx.Omit("Organization", "Inviter").Create(&invitation).Error
+	}); err != nil {
+		H.logger(
+			c, utils.InviteOrgMember, err.Error(), member.OrgSlug, "error", utils.ErrorFailedDB,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	if H.Conf.ENVIRONMENT != "testing" {
+		if err = H.sendOrgInvitationEmail(&invitation); err != nil {
+			H.logger(
+				c, utils.InviteOrgMember, err.Error(), member.OrgSlug, "error",
+				"Failed send org invitation email", session.UserSlug,
+			)
+		}
+	}
+
+	return c.Status(201).JSON(newOrgInvitationResponse(&invitation))
+}
+
+func (H Handler) sendOrgInvitationEmail(invitation *models.OrgInvitation) error {
+	return H.sendEmail(
+		"You've been invited to join " + invitation.Organization.Name, H.Conf.SUPPORT_EMAIL,
+		[]string{invitation.EmailAddress}, "email_org_invitation.html", map[string]string{
+			"InviterName": invitation.Inviter.Name,
+			"OrgName": invitation.Organization.Name,
+			"Role": invitation.Role,
+			"ExpiresAt": invitation.ExpiresAt.Format(time.RFC1123),
+			"Link": H.Conf.APP_SCHEME + "://" + H.Conf.APP_DOMAIN + "/invitations",
+		},
+	)
+}
@@ -0,0 +1,262 @@
+package controllers
+
+import (
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/models"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+type InviteVaultMemberRequestBody struct {
+	EmailAddress string `json:"email_address"`
+	Role         string `json:"role"`
+}
+
+// InviteVaultMember emails an invitation to join a vault. The first invitation to one of the
+// user's own vaults makes it a shared vault, with the user as its owner. Inviting an address
+// again replaces the pending invitation and restarts its expiry.
+func (H Handler) InviteVaultMember(c *fiber.Ctx) error {
+	if header := c.Get("Client-Operation"); header != utils.InviteVaultMember {
+		H.logger(c, utils.InviteVaultMember, header, "", "warn", utils.ErrorClientOperation, "")
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	var session *models.ClientSession
+	var ok bool
+
+	if session, ok = c.UserContext().Value(sessionContextKey{}).(*models.ClientSession); !ok {
+		H.logger(c, utils.InviteVaultMember, "", "", "error", "Failed session.User context", "")
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	vaultSlug := c.Params("slug")
+
+	if !utils.SlugRegexp.MatchString(vaultSlug) {
+		H.logger(c, utils.InviteVaultMember, vaultSlug, "", "warn", utils.ErrorParams, session.UserSlug)
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	reqBody := InviteVaultMemberRequestBody{}
+
+	if err := c.BodyParser(&reqBody); err != nil {
+		H.logger(
+			c, utils.InviteVaultMember, err.Error(), "", "error", utils.ErrorParse, session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	reqBody.EmailAddress = strings.TrimSpace(reqBody.EmailAddress)
+	fieldErrors := map[string][]string{}
+
+	if !utils.EmailRegexp.MatchString(reqBody.EmailAddress) {
+		fieldErrors["email_address"] = []string{"Invalid email address"}
+	} else if strings.EqualFold(reqBody.EmailAddress, session.User.EmailAddress) {
+		fieldErrors["email_address"] = []string{"You can't invite yourself"}
+	}
+
+	if !utils.ValidVaultRole(reqBody.Role) {
+		fieldErrors["role"] = []string{"Must be one of: " + strings.Join(utils.VaultRoles, ", ")}
+	}
+
+	if len(fieldErrors) > 0 {
+		H.logger(
+			c, utils.InviteVaultMember, "", vaultSlug, "warn", utils.ErrorInvitation, session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, fieldErrors, nil)
+	}
+
+	member, err := H.findVaultMember(vaultSlug, session.UserSlug)
+
+	if err != nil {
+		H.logger(
+			c, utils.InviteVaultMember, err.Error(), vaultSlug, "error", utils.ErrorFailedDB,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	now := time.Now().UTC()
+
+	if member == nil {
+		if member, err = H.shareVault(c, session, vaultSlug, now); member == nil {
+			return err
+		}
+	}
+
+	if !utils.VaultRoleAtLeast(member.Role, utils.VaultRoleManage) {
+		H.logger(
+			c, utils.InviteVaultMember, member.Role, vaultSlug, "warn", utils.ErrorVaultRole,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 403, utils.ErrorVaultPermission, nil, nil)
+	}
+
+	var count int64
+
+	if result := H.DBs.ApiGateway.Model(&models.VaultMember{}).
+		Joins("JOIN users ON users.slug = vault_members.user_slug").
+		Where("vault_members.vault_slug = ? AND LOWER(users.email_address) = LOWER(?)",
+			vaultSlug, reqBody.EmailAddress).
+		Count(&count); result.Error != nil {
+		H.logger(
+			c, utils.InviteVaultMember, result.Error.Error(), vaultSlug, "error", utils.ErrorFailedDB,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	} else if count > 0 {
+		H.logger(
+			c, utils.InviteVaultMember, reqBody.EmailAddress, vaultSlug, "warn", utils.ErrorInvitation,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 409, utils.ErrorAlreadyMember, nil, nil)
+	}
+
+	invitation := models.VaultInvitation{
+		VaultSlug:    vaultSlug,
+		InviterSlug:  session.UserSlug,
+		Inviter:      session.User,
+		EmailAddress: reqBody.EmailAddress,
+		Role:         reqBody.Role,
+		CreatedAt:    now,
+		ExpiresAt:    now.Add(utils.VaultInvitationTTL),
+	}
+
+	if slug, err := utils.GenerateSlug(utils.InvitationSlugLength); err != nil {
+		H.logger(
+			c, utils.InviteVaultMember, err.Error(), "", "error", "Failed generate invitation.Slug",
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	} else {
+		invitation.Slug = slug
+	}
+
+	if err = H.DBs.ApiGateway.Transaction(func(tx *gorm.DB) error {
+		if result := tx.Where(
+			"vault_slug = ? AND LOWER(email_address) = LOWER(?)", vaultSlug, reqBody.EmailAddress,
+		).Delete(&models.VaultInvitation{}); result.Error != nil {
+			return result.Error
+		}
+
+		return tx.Omit("SharedVault", "Inviter").Create(&invitation).Error
+	}); err != nil {
+		H.logger(
+			c, utils.InviteVaultMember, err.Error(), vaultSlug, "error", utils.ErrorFailedDB,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	if H.Conf.ENVIRONMENT != "testing" {
+		if err = H.sendVaultInvitationEmail(&invitation); err != nil {
+			H.logger(
+				c, utils.InviteVaultMember, err.Error(), vaultSlug, "error",
+				"Failed send vault invitation email", session.UserSlug,
+			)
+		}
+	}
+
+	return c.Status(201).JSON(newVaultInvitationResponse(&invitation))
+}
+
+// shareVault makes one of the session user's own vaults a shared vault, with the user as its
+// only member. Responds itself and returns a nil member if the vault isn't the user's.
+func (H Handler) shareVault(
+	c *fiber.Ctx, session *models.ClientSession, vaultSlug string, now time.Time,
+) (*models.VaultMember, error) {
+	var count int64
+
+	if result := H.DBs.ApiGateway.Model(&models.SharedVault{}).
+		Where("vault_slug = ?", vaultSlug).Count(&count); result.Error != nil {
+		H.logger(
+			c, utils.InviteVaultMember, result.Error.Error(), vaultSlug, "error", utils.ErrorFailedDB,
+			session.UserSlug,
+		)
+
+		return nil, utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	} else if count > 0 {
+		H.logger(
+			c, utils.InviteVaultMember, vaultSlug, "", "warn", utils.ErrorVaultNotShared,
+			session.UserSlug,
+		)
+
+		return nil, utils.RespondWithError(c, 404, utils.ErrorNotFound, nil, nil)
+	}
+
+	vaultSlugs, statusCode, errString := H.vaultsUserVaultSlugs(c, session.UserSlug)
+
+	if errString != "" {
+		H.logger(
+			c, utils.InviteVaultMember, errString, vaultSlug, "error", utils.ErrorVaultsListVaults,
+			session.UserSlug,
+		)
+
+		return nil, respondVaultsJSONError(c, statusCode)
+	} else if !vaultSlugs[vaultSlug] {
+		H.logger(
+			c, utils.InviteVaultMember, vaultSlug, "", "warn", utils.ErrorVaultNotShared,
+			session.UserSlug,
+		)
+
+		return nil, utils.RespondWithError(c, 404, utils.ErrorNotFound, nil, nil)
+	}
+
+	sharedVault := models.SharedVault{
+		VaultSlug: vaultSlug,
+		OwnerSlug: session.UserSlug,
+		CreatedAt: now,
+	}
+
+	member := models.VaultMember{
+		VaultSlug: vaultSlug,
+		UserSlug:  session.UserSlug,
+		Role:      utils.VaultRoleManage,
+		CreatedAt: now,
+	}
+
+	if err := H.DBs.ApiGateway.Transaction(func(tx *gorm.DB) error {
+		if result := tx.Omit("Owner").Create(&sharedVault); result.Error != nil {
+			return result.Error
+		}
+
+		return tx.Omit("SharedVault", "User").Create(&member).Error
+	}); err != nil {
+		H.logger(
+			c, utils.InviteVaultMember, err.Error(), vaultSlug, "error", utils.ErrorFailedDB,
+			session.UserSlug,
+		)
+
+		return nil, utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	member.SharedVault = sharedVault
+
+	return &member, nil
+}
+
+func (H Handler) sendVaultInvitationEmail(invitation *models.VaultInvitation) error {
+	return H.sendEmail(
+		"You've been invited to a shared vault", H.Conf.SUPPORT_EMAIL,
+		[]string{invitation.EmailAddress}, "email_vault_invitation.html", map[string]string{
+			"InviterName": invitation.Inviter.Name,
+			"Role": invitation.Role,
+			"ExpiresAt": invitation.ExpiresAt.Format(time.RFC1123),
+			"Link": H.Conf.APP_SCHEME + "://" + H.Conf.APP_DOMAIN + "/invitations",
+		},
+	)
+}
@@ -0,0 +1,56 @@
+package controllers
+
+import (
+	"io"
+	"regexp"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+// Room for the largest attachment plus its multipart framing
+const uploadBodyLimit int64 = utils.AttachmentMaxSize + 64 << 10
+
+var uploadAttachmentPath = regexp.MustCompile(`^/api/entries/[^/]+/attachments/?$`)
+
+// LimitRequestBody reads the request body, which the app streams rather than reading up front,
+// and rejects it if it's over fiber's default limit. Attachment uploads are the one route
+// allowed more.
+func (H Handler) LimitRequestBody(c *fiber.Ctx) error {
+	if !c.Request().IsBodyStream() {
+		return c.Next()
+	}
+
+	limit := int64(fiber.DefaultBodyLimit)
+
+	if c.Method() == fiber.MethodPost && uploadAttachmentPath.MatchString(c.Path()) {
+		limit = uploadBodyLimit
+	}
+
+	body, err := io.ReadAll(io.LimitReader(c.Context().RequestBodyStream(), limit + 1))
+
+	if err != nil {
+		c.Request().ResetBody()
+		c.Context().SetConnectionClose()
+		H.logger(c, c.Get("Client-Operation"), err.Error(), "", "warn", utils.ErrorParse, "")
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	if int64(len(body)) > limit {
+		// The rest of the body is never read, so the connection can't be reused
+		c.Request().ResetBody()
+		c.Context().SetConnectionClose()
+		H.logger(
+			c, c.Get("Client-Operation"), c.Get(fiber.HeaderContentLength), c.Path(), "warn",
+			utils.ErrorBodyLimit, "",
+		)
+
+		return utils.RespondWithError(c, 413, utils.ErrorRequestTooLarge, nil, nil)
+	}
+
+	c.Request().SetBody(body)
+
+	return c.Next()
+}
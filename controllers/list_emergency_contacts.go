@@ -0,0 +1,96 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/models"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+type ListEmergencyContactsResponseBody struct {
+	Contacts []EmergencyContactResponse `json:"contacts"`
+	Grants   []EmergencyGrantResponse   `json:"grants"`
+}
+
+// ListEmergencyContacts returns the user's emergency contacts, and the users who named them as
+// an emergency contact
+func (H Handler) ListEmergencyContacts(c *fiber.Ctx) error {
+	if header := c.Get("Client-Operation"); header != utils.ListEmergencyContacts {
+		H.logger(c, utils.ListEmergencyContacts, header, "", "warn", utils.ErrorClientOperation, "")
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	var session *models.ClientSession
+	var ok bool
+
+	if session, ok = c.UserContext().Value(sessionContextKey{}).(*models.ClientSession); !ok {
+		H.logger(c, utils.ListEmergencyContacts, "", "", "error", "Failed session.User context", "")
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	contacts := []models.EmergencyContact{}
+	grants := []models.EmergencyContact{}
+
+	if result := H.DBs.ApiGateway.Where("grantor_slug = ?", session.UserSlug).
+		Order("created_at").Find(&contacts); result.Error != nil {
+		H.logger(
+			c, utils.ListEmergencyContacts, result.Error.Error(), "", "error", utils.ErrorFailedDB,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	if result := H.DBs.ApiGateway.Preload("Grantor").
+		Where("LOWER(email_address) = LOWER(?)", session.User.EmailAddress).
+		Order("created_at").Find(&grants); result.Error != nil {
+		H.logger(
+			c, utils.ListEmergencyContacts, result.Error.Error(), "", "error", utils.ErrorFailedDB,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	contactSlugs := []string{}
+
+	for _, contact := range contacts {
+		contactSlugs = append(contactSlugs, contact.Slug)
+	}
+
+	for _, contact := range grants {
+		contactSlugs = append(contactSlugs, contact.Slug)
+	}
+
+	vaultSlugs, err := H.emergencyContactVaultSlugs(contactSlugs)
+
+	if err != nil {
+		H.logger(
+			c, utils.ListEmergencyContacts, err.Error(), "", "error", utils.ErrorFailedDB,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	resBody := ListEmergencyContactsResponseBody{
+		Contacts: []EmergencyContactResponse{},
+		Grants:   []EmergencyGrantResponse{},
+	}
+
+	for i := range contacts {
+		resBody.Contacts = append(
+			resBody.Contacts, newEmergencyContactResponse(&contacts[i], vaultSlugs[contacts[i].Slug]),
+		)
+	}
+
+	for i := range grants {
+		resBody.Grants = append(
+			resBody.Grants, newEmergencyGrantResponse(&grants[i], vaultSlugs[grants[i].Slug]),
+		)
+	}
+
+	return c.Status(200).JSON(&resBody)
+}
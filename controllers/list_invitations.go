@@ -0,0 +1,79 @@
+package controllers
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/models"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+type ListInvitationsResponseBody struct {
+	Invitations    []VaultInvitationResponse `json:"invitations"`
+	OrgInvitations []OrgInvitationResponse   `json:"org_invitations"`
+}
+
+// ListInvitations returns the pending vault and organization invitations sent to the user's
+// email address, newest first
+func (H Handler) ListInvitations(c *fiber.Ctx) error {
+	if header := c.Get("Client-Operation"); header != utils.ListInvitations {
+		H.logger(c, utils.ListInvitations, header, "", "warn", utils.ErrorClientOperation, "")
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	var session *models.ClientSession
+	var ok bool
+
+	if session, ok = c.UserContext().Value(sessionContextKey{}).(*models.ClientSession); !ok {
+		H.logger(c, utils.ListInvitations, "", "", "error", "Failed session.User context", "")
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	invitations := []models.VaultInvitation{}
+
+	if result := H.DBs.ApiGateway.Preload("Inviter").Where(
+		"LOWER(email_address) = LOWER(?) AND expires_at > ?", session.User.EmailAddress,
+		time.Now().UTC(),
+	).Order("created_at DESC").Find(&invitations); result.Error != nil {
+		H.logger(
+			c, utils.ListInvitations, result.Error.Error(), "", "error", utils.ErrorFailedDB,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	orgInvitations := []models.OrgInvitation{}
+
+	if result := H.DBs.ApiGateway.Preload("Organization").Preload("Inviter").Where(
+		"LOWER(email_address) = LOWER(?) AND expires_at > ?", session.User.EmailAddress,
+		time.Now().UTC(),
+	).Order("created_at DESC").Find(&orgInvitations); result.Error != nil {
+		H.logger(
+			c, utils.ListInvitations, result.Error.Error(), "", "error", utils.ErrorFailedDB,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	resBody := ListInvitationsResponseBody{
+		Invitations:    []VaultInvitationResponse{},
+		OrgInvitations: []OrgInvitationResponse{},
+	}
+
+	for i := range invitations {
+		resBody.Invitations = append(resBody.Invitations, newVaultInvitationResponse(&invitations[i]))
+	}
+
+	for i := range orgInvitations {
+		resBody.OrgInvitations = append(
+			resBody.OrgInvitations, newOrgInvitationResponse(&orgInvitations[i]),
+		)
+	}
+
+	return c.Status(200).JSON(&resBody)
+}
@@ -0,0 +1,133 @@
+package controllers
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/models"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+type OrgGroupResponse struct {
+	GroupSlug string    `json:"group_slug"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type ListOrgMembersResponseBody struct {
+	Members     []OrgMemberResponse     `json:"members"`
+	Groups      []OrgGroupResponse      `json:"groups"`
+	Invitations []OrgInvitationResponse `json:"invitations,omitempty"`
+}
+
+// ListOrgMembers returns an organization's members with the groups each is in, and its groups.
+// Admins also see the pending invitations.
+func (H Handler) ListOrgMembers(c *fiber.Ctx) error {
+	if header := c.Get("Client-Operation"); header != utils.ListOrgMembers {
+		H.logger(c, utils.ListOrgMembers, header, "", "warn", utils.ErrorClientOperation, "")
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	var session *models.ClientSession
+	var ok bool
+
+	if session, ok = c.UserContext().Value(sessionContextKey{}).(*models.ClientSession); !ok {
+		H.logger(c, utils.ListOrgMembers, "", "", "error", "Failed session.User context", "")
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	member, err := H.requireOrgRole(c, utils.ListOrgMembers, session, utils.OrgRoleMember)
+
+	if member == nil {
+		return err
+	}
+
+	members := []models.OrgMember{}
+	groups := []models.OrgGroup{}
+	groupMembers := []models.OrgGroupMember{}
+
+	if err = H.DBs.ApiGateway.Preload("User").Where("org_slug = ?", member.OrgSlug).
+		Order("created_at").Find(&members).Error; err == nil {
+		err = H.DBs.ApiGateway.Where("org_slug = ?", member.OrgSlug).Order("name").
+			Find(&groups).Error
+	}
+
+	if err == nil {
+		err = H.DBs.ApiGateway.
+			Joins("JOIN org_groups ON org_groups.slug = org_group_members.group_slug").
+			Where("org_groups.org_slug = ?", member.OrgSlug).Find(&groupMembers).Error
+	}
+
+	if err != nil {
+		H.logger(
+			c, utils.ListOrgMembers, err.Error(), member.OrgSlug, "error", utils.ErrorFailedDB,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	userGroups := map[string][]string{}
+
+	for _, groupMember := range groupMembers {
+		userGroups[groupMember.UserSlug] = append(
+			userGroups[groupMember.UserSlug], groupMember.GroupSlug,
+		)
+	}
+
+	resBody := ListOrgMembersResponseBody{
+		Members: []OrgMemberResponse{},
+		Groups:  []OrgGroupResponse{},
+	}
+
+	for _, m := range members {
+		groupSlugs := userGroups[m.UserSlug]
+
+		if groupSlugs == nil {
+			groupSlugs = []string{}
+		}
+
+		resBody.Members = append(resBody.Members, OrgMemberResponse{
+			UserSlug:     m.UserSlug,
+			Name:         m.User.Name,
+			EmailAddress: m.User.EmailAddress,
+			Role:         m.Role,
+			GroupSlugs:   groupSlugs,
+			CreatedAt:    m.CreatedAt,
+		})
+	}
+
+	for _, group := range groups {
+		resBody.Groups = append(resBody.Groups, OrgGroupResponse{
+			GroupSlug: group.Slug,
+			Name:      group.Name,
+			CreatedAt: group.CreatedAt,
+		})
+	}
+
+	if utils.OrgRoleAtLeast(member.Role, utils.OrgRoleAdmin) {
+		invitations := []models.OrgInvitation{}
+
+		if result := H.DBs.ApiGateway.Preload("Organization").Preload("Inviter").Where(
+			"org_slug = ? AND expires_at > ?", member.OrgSlug, time.Now().UTC(),
+		).Order("created_at DESC").Find(&invitations); result.Error != nil {
+			H.logger(
+				c, utils.ListOrgMembers, result.Error.Error(), member.OrgSlug, "error",
+				utils.ErrorFailedDB, session.UserSlug,
+			)
+
+			return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+		}
+
+		for i := range invitations {
+			resBody.Invitations = append(
+				resBody.Invitations, newOrgInvitationResponse(&invitations[i]),
+			)
+		}
+	}
+
+	return c.Status(200).JSON(&resBody)
+}
@@ -0,0 +1,105 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/models"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+type OrgVaultGrantResponse struct {
+	GroupSlug string `json:"group_slug"`
+	VaultSlug string `json:"vault_slug"`
+	Role      string `json:"role"`
+}
+
+type OrgVaultResponse struct {
+	VaultSlug string `json:"vault_slug"`
+	Role      string `json:"role"`
+}
+
+type ListOrgVaultsResponseBody struct {
+	Vaults []OrgVaultResponse      `json:"vaults"`
+	Grants []OrgVaultGrantResponse `json:"grants,omitempty"`
+}
+
+// ListOrgVaults returns the organization's vaults the user reaches through their groups, with
+// their role in each. Admins, who reach every org vault, get the grants of all groups instead;
+// the vaults themselves are listed by list_vaults with the Org-Slug header.
+func (H Handler) ListOrgVaults(c *fiber.Ctx) error {
+	if header := c.Get("Client-Operation"); header != utils.ListOrgVaults {
+		H.logger(c, utils.ListOrgVaults, header, "", "warn", utils.ErrorClientOperation, "")
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	var session *models.ClientSession
+	var ok bool
+
+	if session, ok = c.UserContext().Value(sessionContextKey{}).(*models.ClientSession); !ok {
+		H.logger(c, utils.ListOrgVaults, "", "", "error", "Failed session.User context", "")
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	member, err := H.requireOrgRole(c, utils.ListOrgVaults, session, utils.OrgRoleMember)
+
+	if member == nil {
+		return err
+	}
+
+	isAdmin := utils.OrgRoleAtLeast(member.Role, utils.OrgRoleAdmin)
+	grants := []models.OrgVaultGrant{}
+	query := H.DBs.ApiGateway.
+		Joins("JOIN org_groups ON org_groups.slug = org_vault_grants.group_slug").
+		Where("org_groups.org_slug = ?", member.OrgSlug)
+
+	if !isAdmin {
+		query = query.Joins(
+			"JOIN org_group_members ON org_group_members.group_slug = org_vault_grants.group_slug",
+		).Where("org_group_members.user_slug = ?", session.UserSlug)
+	}
+
+	if result := query.Order("org_vault_grants.vault_slug").Find(&grants); result.Error != nil {
+		H.logger(
+			c, utils.ListOrgVaults, result.Error.Error(), member.OrgSlug, "error", utils.ErrorFailedDB,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	resBody := ListOrgVaultsResponseBody{Vaults: []OrgVaultResponse{}}
+
+	if isAdmin {
+		resBody.Grants = []OrgVaultGrantResponse{}
+
+		for _, grant := range grants {
+			resBody.Grants = append(resBody.Grants, OrgVaultGrantResponse{
+				GroupSlug: grant.GroupSlug,
+				VaultSlug: grant.VaultSlug,
+				Role:      grant.Role,
+			})
+		}
+
+		return c.Status(200).JSON(&resBody)
+	}
+
+	// Grants are ordered by vault, so each vault's grants are next to each other
+	for _, grant := range grants {
+		last := len(resBody.Vaults) - 1
+
+		if last >= 0 && resBody.Vaults[last].VaultSlug == grant.VaultSlug {
+			if utils.VaultRoleAtLeast(grant.Role, resBody.Vaults[last].Role) {
+				resBody.Vaults[last].Role = grant.Role
+			}
+		} else {
+			resBody.Vaults = append(resBody.Vaults, OrgVaultResponse{
+				VaultSlug: grant.VaultSlug,
+				Role:      grant.Role,
+			})
+		}
+	}
+
+	return c.Status(200).JSON(&resBody)
+}
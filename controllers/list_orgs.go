@@ -0,0 +1,54 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/models"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+type ListOrgsResponseBody struct {
+	Orgs []OrgResponse `json:"orgs"`
+}
+
+// ListOrgs returns the organizations the user is a member of, with their role in each
+func (H Handler) ListOrgs(c *fiber.Ctx) error {
+	if header := c.Get("Client-Operation"); header != utils.ListOrgs {
+		H.logger(c, utils.ListOrgs, header, "", "warn", utils.ErrorClientOperation, "")
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	var session *models.ClientSession
+	var ok bool
+
+	if session, ok = c.UserContext().Value(sessionContextKey{}).(*models.ClientSession); !ok {
+		H.logger(c, utils.ListOrgs, "", "", "error", "Failed session.User context", "")
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	members := []models.OrgMember{}
+
+	if result := H.DBs.ApiGateway.Preload("Organization").Where("user_slug = ?", session.UserSlug).
+		Order("created_at").Find(&members); result.Error != nil {
+		H.logger(
+			c, utils.ListOrgs, result.Error.Error(), "", "error", utils.ErrorFailedDB, session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	resBody := ListOrgsResponseBody{Orgs: []OrgResponse{}}
+
+	for _, member := range members {
+		resBody.Orgs = append(resBody.Orgs, OrgResponse{
+			OrgSlug:   member.OrgSlug,
+			Name:      member.Organization.Name,
+			Role:      member.Role,
+			CreatedAt: member.Organization.CreatedAt,
+		})
+	}
+
+	return c.Status(200).JSON(&resBody)
+}
@@ -0,0 +1,72 @@
+package controllers
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/models"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+type SharedVaultResponse struct {
+	VaultSlug     string    `json:"vault_slug"`
+	OwnerName     string    `json:"owner_name"`
+	IsOwner       bool      `json:"is_owner"`
+	Role          string    `json:"role"`
+	HasKey        bool      `json:"has_key"`
+	RekeyRequired bool      `json:"rekey_required"`
+	JoinedAt      time.Time `json:"joined_at"`
+}
+
+type ListSharedVaultsResponseBody struct {
+	SharedVaults []SharedVaultResponse `json:"shared_vaults"`
+}
+
+// ListSharedVaults returns every shared vault the user is a member of, including the ones they
+// own, so clients know which vaults to look up with the user's role and wrapped key
+func (H Handler) ListSharedVaults(c *fiber.Ctx) error {
+	if header := c.Get("Client-Operation"); header != utils.ListSharedVaults {
+		H.logger(c, utils.ListSharedVaults, header, "", "warn", utils.ErrorClientOperation, "")
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	var session *models.ClientSession
+	var ok bool
+
+	if session, ok = c.UserContext().Value(sessionContextKey{}).(*models.ClientSession); !ok {
+		H.logger(c, utils.ListSharedVaults, "", "", "error", "Failed session.User context", "")
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	members := []models.VaultMember{}
+
+	if result := H.DBs.ApiGateway.Preload("SharedVault.Owner").
+		Where("user_slug = ?", session.UserSlug).Order("created_at").Find(&members);
+	result.Error != nil {
+		H.logger(
+			c, utils.ListSharedVaults, result.Error.Error(), "", "error", utils.ErrorFailedDB,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	resBody := ListSharedVaultsResponseBody{SharedVaults: []SharedVaultResponse{}}
+
+	for _, member := range members {
+		resBody.SharedVaults = append(resBody.SharedVaults, SharedVaultResponse{
+			VaultSlug:     member.VaultSlug,
+			OwnerName:     member.SharedVault.Owner.Name,
+			IsOwner:       member.SharedVault.OwnerSlug == session.UserSlug,
+			Role:          member.Role,
+			HasKey:        len(member.WrappedKey) > 0,
+			RekeyRequired: member.SharedVault.RekeyRequired,
+			JoinedAt:      member.CreatedAt,
+		})
+	}
+
+	return c.Status(200).JSON(&resBody)
+}
@@ -0,0 +1,48 @@
+package controllers
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/models"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+type ListSharesResponseBody struct {
+	Shares []models.Share `json:"shares"`
+}
+
+// ListShares returns the user's shares that can still be opened, newest first. Ciphertexts
+// are left out.
+func (H Handler) ListShares(c *fiber.Ctx) error {
+	if header := c.Get("Client-Operation"); header != utils.ListShares {
+		H.logger(c, utils.ListShares, header, "", "warn", utils.ErrorClientOperation, "")
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	var session *models.ClientSession
+	var ok bool
+
+	if session, ok = c.UserContext().Value(sessionContextKey{}).(*models.ClientSession); !ok {
+		H.logger(c, utils.ListShares, "", "", "error", "Failed session.User context", "")
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	shares := []models.Share{}
+
+	if result := H.DBs.ApiGateway.
+		Where("user_slug = ? AND expires_at > ?", session.UserSlug, time.Now().UTC()).
+		Order("created_at DESC").Find(&shares); result.Error != nil {
+		H.logger(
+			c, utils.ListShares, result.Error.Error(), "", "error", utils.ErrorFailedDB,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	return c.Status(200).JSON(&ListSharesResponseBody{Shares: shares})
+}
@@ -0,0 +1,117 @@
+package controllers
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/models"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+type ListVaultMembersResponseBody struct {
+	Members     []VaultMemberResponse     `json:"members"`
+	Invitations []VaultInvitationResponse `json:"invitations,omitempty"`
+}
+
+// ListVaultMembers returns the members of a shared vault with their public keys, which a
+// manager's client needs to wrap the vault key for each of them. Managers also see the pending
+// invitations.
+func (H Handler) ListVaultMembers(c *fiber.Ctx) error {
+	if header := c.Get("Client-Operation"); header != utils.ListVaultMembers {
+		H.logger(c, utils.ListVaultMembers, header, "", "warn", utils.ErrorClientOperation, "")
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	var session *models.ClientSession
+	var ok bool
+
+	if session, ok = c.UserContext().Value(sessionContextKey{}).(*models.ClientSession); !ok {
+		H.logger(c, utils.ListVaultMembers, "", "", "error", "Failed session.User context", "")
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	member, err := H.requireVaultRole(c, utils.ListVaultMembers, session, utils.VaultRoleRead)
+
+	if member == nil {
+		return err
+	}
+
+	members := []models.VaultMember{}
+
+	if result := H.DBs.ApiGateway.Preload("User").Where("vault_slug = ?", member.VaultSlug).
+		Order("created_at").Find(&members); result.Error != nil {
+		H.logger(
+			c, utils.ListVaultMembers, result.Error.Error(), "", "error", utils.ErrorFailedDB,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	userSlugs := make([]string, 0, len(members))
+
+	for _, m := range members {
+		userSlugs = append(userSlugs, m.UserSlug)
+	}
+
+	keyPairs := []models.UserKeyPair{}
+
+	if result := H.DBs.ApiGateway.Where("user_slug IN ?", userSlugs).Find(&keyPairs);
+	result.Error != nil {
+		H.logger(
+			c, utils.ListVaultMembers, result.Error.Error(), "", "error", utils.ErrorFailedDB,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	publicKeys := map[string][]byte{}
+
+	for _, keyPair := range keyPairs {
+		publicKeys[keyPair.UserSlug] = keyPair.PublicKey
+	}
+
+	resBody := ListVaultMembersResponseBody{Members: []VaultMemberResponse{}}
+
+	for _, m := range members {
+		resBody.Members = append(resBody.Members, VaultMemberResponse{
+			UserSlug:     m.UserSlug,
+			Name:         m.User.Name,
+			EmailAddress: m.User.EmailAddress,
+			Role:         m.Role,
+			IsOwner:      m.UserSlug == member.SharedVault.OwnerSlug,
+			PublicKey:    publicKeys[m.UserSlug],
+			HasKey:       len(m.WrappedKey) > 0,
+			CreatedAt:    m.CreatedAt,
+		})
+	}
+
+	if utils.VaultRoleAtLeast(member.Role, utils.VaultRoleManage) {
+		invitations := []models.VaultInvitation{}
+
+		if result := H.DBs.ApiGateway.Preload("Inviter").Where(
+			"vault_slug = ? AND expires_at > ?", member.VaultSlug, time.Now().UTC(),
+		).Order("created_at DESC").Find(&invitations); result.Error != nil {
+			H.logger(
+				c, utils.ListVaultMembers, result.Error.Error(), "", "error", utils.ErrorFailedDB,
+				session.UserSlug,
+			)
+
+			return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+		}
+
+		resBody.Invitations = []VaultInvitationResponse{}
+
+		for i := range invitations {
+			resBody.Invitations = append(
+				resBody.Invitations, newVaultInvitationResponse(&invitations[i]),
+			)
+		}
+	}
+
+	return c.Status(200).JSON(&resBody)
+}
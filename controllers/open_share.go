@@ -0,0 +1,81 @@
+package controllers
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/models"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+type OpenShareResponseBody struct {
+	Ciphertext string    `json:"ciphertext"`
+	ViewsLeft  int       `json:"views_left"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// OpenShare needs no session, since recipients don't have accounts. It's a POST so link
+// previews and prefetching, which only GET, can't use up a view.
+func (H Handler) OpenShare(c *fiber.Ctx) error {
+	if header := c.Get("Client-Operation"); header != utils.OpenShare {
+		H.logger(c, utils.OpenShare, header, "", "warn", utils.ErrorClientOperation, "")
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	slug := c.Params("slug")
+
+	if !utils.ShareSlugRegexp.MatchString(slug) {
+		H.logger(c, utils.OpenShare, "", "", "warn", utils.ErrorShareNotFound, "")
+
+		return utils.RespondWithError(c, 404, utils.ErrorNotFound, nil, nil)
+	}
+
+	now := time.Now().UTC()
+	var share models.Share
+
+	// The conditional decrement is what stops two concurrent opens both taking the last view
+	err := H.DBs.ApiGateway.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Share{}).
+			Where("slug = ? AND views_left > 0 AND expires_at > ?", slug, now).
+			Update("views_left", gorm.Expr("views_left - 1"))
+
+		if result.Error != nil {
+			return result.Error
+		} else if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		if result = tx.Where("slug = ?", slug).First(&share); result.Error != nil {
+			return result.Error
+		}
+
+		if share.ViewsLeft == 0 {
+			return tx.Delete(&share).Error
+		}
+
+		return nil
+	})
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		H.DBs.ApiGateway.Where("slug = ? AND expires_at <= ?", slug, now).Delete(&models.Share{})
+		H.logger(c, utils.OpenShare, "", "", "warn", utils.ErrorShareNotFound, "")
+
+		return utils.RespondWithError(c, 404, utils.ErrorNotFound, nil, nil)
+	} else if err != nil {
+		H.logger(c, utils.OpenShare, err.Error(), "", "error", utils.ErrorFailedDB, "")
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	c.Set(fiber.HeaderCacheControl, "no-store")
+
+	return c.Status(200).JSON(&OpenShareResponseBody{
+		Ciphertext: share.Ciphertext,
+		ViewsLeft:  share.ViewsLeft,
+		ExpiresAt:  share.ExpiresAt,
+	})
+}
@@ -0,0 +1,224 @@
+package controllers
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/models"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+// Names the organization a vaults request acts for, in place of the session user
+const orgSlugHeader string = "Org-Slug"
+
+type OrgResponse struct {
+	OrgSlug   string    `json:"org_slug"`
+	Name      string    `json:"name"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type OrgMemberResponse struct {
+	UserSlug     string    `json:"user_slug"`
+	Name         string    `json:"name"`
+	EmailAddress string    `json:"email_address"`
+	Role         string    `json:"role"`
+	GroupSlugs   []string  `json:"group_slugs"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type OrgInvitationResponse struct {
+	InvitationSlug string    `json:"invitation_slug"`
+	OrgSlug        string    `json:"org_slug"`
+	OrgName        string    `json:"org_name"`
+	InviterName    string    `json:"inviter_name"`
+	EmailAddress   string    `json:"email_address"`
+	Role           string    `json:"role"`
+	CreatedAt      time.Time `json:"created_at"`
+	ExpiresAt      time.Time `json:"expires_at"`
+}
+
+func newOrgInvitationResponse(invitation *models.OrgInvitation) OrgInvitationResponse {
+	return OrgInvitationResponse{
+		InvitationSlug: invitation.Slug,
+		OrgSlug:        invitation.OrgSlug,
+		OrgName:        invitation.Organization.Name,
+		InviterName:    invitation.Inviter.Name,
+		EmailAddress:   invitation.EmailAddress,
+		Role:           invitation.Role,
+		CreatedAt:      invitation.CreatedAt,
+		ExpiresAt:      invitation.ExpiresAt,
+	}
+}
+
+// findOrgMember returns the user's membership of an organization with its Organization, or nil
+// if they aren't a member
+func (H Handler) findOrgMember(orgSlug, userSlug string) (*models.OrgMember, error) {
+	var member models.OrgMember
+
+	result := H.DBs.ApiGateway.Preload("Organization").
+		Where("org_slug = ? AND user_slug = ?", orgSlug, userSlug).Limit(1).Find(&member)
+
+	if result.Error != nil {
+		return nil, result.Error
+	} else if result.RowsAffected == 0 {
+		return nil, nil
+	}
+
+	return &member, nil
+}
+
+// orgVaultGrant returns what an organization's member may do when acting for it, or nil if the
+// user isn't a member. Owners and admins may do everything. A member's vault role is the
+// greatest their groups were granted on the vault, and without a vault they may do nothing.
+func (H Handler) orgVaultGrant(orgSlug, vaultSlug, userSlug string) (*vaultGrant, error) {
+	member, err := H.findOrgMember(orgSlug, userSlug)
+
+	if member == nil {
+		return nil, err
+	}
+
+	grant := vaultGrant{OwnerSlug: orgSlug}
+
+	if utils.OrgRoleAtLeast(member.Role, utils.OrgRoleAdmin) {
+		grant.Role = utils.VaultRoleManage
+		grant.Full = true
+
+		return &grant, nil
+	} else if vaultSlug == "" {
+		return &grant, nil
+	}
+
+	roles := []string{}
+
+	if result := H.DBs.ApiGateway.Model(&models.OrgVaultGrant{}).
+		Joins("JOIN org_groups ON org_groups.slug = org_vault_grants.group_slug").
+		Joins("JOIN org_group_members ON org_group_members.group_slug = org_vault_grants.group_slug").
+		Where(
+			"org_groups.org_slug = ? AND org_vault_grants.vault_slug = ? AND " +
+			"org_group_members.user_slug = ?", orgSlug, vaultSlug, userSlug,
+		).Pluck("org_vault_grants.role", &roles); result.Error != nil {
+		return nil, result.Error
+	}
+
+	for _, role := range roles {
+		if utils.VaultRoleAtLeast(role, grant.Role) {
+			grant.Role = role
+		}
+	}
+
+	return &grant, nil
+}
+
+// requireOrgRole loads the session user's membership of the organization in the :slug param and
+// checks it grants at least the required role. On failure it responds itself and returns a nil
+// member, so handlers return the error it gives.
+func (H Handler) requireOrgRole(
+	c *fiber.Ctx, clientOperation string, session *models.ClientSession, required string,
+) (*models.OrgMember, error) {
+	orgSlug := c.Params("slug")
+	var member *models.OrgMember
+	var err error
+
+	if utils.SlugRegexp.MatchString(orgSlug) {
+		if member, err = H.findOrgMember(orgSlug, session.UserSlug); err != nil {
+			H.logger(
+				c, clientOperation, err.Error(), orgSlug, "error", utils.ErrorFailedDB, session.UserSlug,
+			)
+
+			return nil, utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+		}
+	}
+
+	if member == nil {
+		H.logger(c, clientOperation, orgSlug, "", "warn", utils.ErrorOrgNotFound, session.UserSlug)
+
+		return nil, utils.RespondWithError(c, 404, utils.ErrorNotFound, nil, nil)
+	} else if !utils.OrgRoleAtLeast(member.Role, required) {
+		H.logger(c, clientOperation, member.Role, orgSlug, "warn", utils.ErrorOrgRole, session.UserSlug)
+
+		return nil, utils.RespondWithError(c, 403, utils.ErrorOrgPermission, nil, nil)
+	}
+
+	return member, nil
+}
+
+// findOrgGroup returns the organization's group in the :group_slug param. Responds itself and
+// returns nil if there's no such group in the organization.
+func (H Handler) findOrgGroup(
+	c *fiber.Ctx, clientOperation string, session *models.ClientSession, orgSlug string,
+) (*models.OrgGroup, error) {
+	groupSlug := c.Params("group_slug")
+	var group models.OrgGroup
+
+	result := H.DBs.ApiGateway.Where("slug = ? AND org_slug = ?", groupSlug, orgSlug).Limit(1).
+		Find(&group)
+
+	if result.Error != nil {
+		H.logger(
+			c, clientOperation, result.Error.Error(), orgSlug, "error", utils.ErrorFailedDB,
+			session.UserSlug,
+		)
+
+		return nil, utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	} else if result.RowsAffected == 0 {
+		H.logger(
+			c, clientOperation, groupSlug, orgSlug, "warn", utils.ErrorOrgGroupNotFound,
+			session.UserSlug,
+		)
+
+		return nil, utils.RespondWithError(c, 404, utils.ErrorNotFound, nil, nil)
+	}
+
+	return &group, nil
+}
+
+// countOrgOwners counts the organization's owners, so the last one can't be demoted or removed
+func (H Handler) countOrgOwners(orgSlug string) (int64, error) {
+	var count int64
+
+	result := H.DBs.ApiGateway.Model(&models.OrgMember{}).
+		Where("org_slug = ? AND role = ?", orgSlug, utils.OrgRoleOwner).Count(&count)
+
+	return count, result.Error
+}
+
+// findOrgInvitation returns the session user's pending organization invitation in the :slug
+// param. Responds itself and returns nil if there's no such invitation.
+func (H Handler) findOrgInvitation(
+	c *fiber.Ctx, clientOperation string, session *models.ClientSession,
+) (*models.OrgInvitation, error) {
+	slug := c.Params("slug")
+	var invitation models.OrgInvitation
+
+	if !utils.InvitationSlugRegexp.MatchString(slug) {
+		H.logger(
+			c, clientOperation, slug, "", "warn", utils.ErrorInvitationNotFound, session.UserSlug,
+		)
+
+		return nil, utils.RespondWithError(c, 404, utils.ErrorNotFound, nil, nil)
+	}
+
+	result := H.DBs.ApiGateway.Preload("Organization").Where(
+		"slug = ? AND LOWER(email_address) = LOWER(?) AND expires_at > ?", slug,
+		session.User.EmailAddress, time.Now().UTC(),
+	).Limit(1).Find(&invitation)
+
+	if result.Error != nil {
+		H.logger(
+			c, clientOperation, result.Error.Error(), "", "error", utils.ErrorFailedDB,
+			session.UserSlug,
+		)
+
+		return nil, utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	} else if result.RowsAffected == 0 {
+		H.logger(
+			c, clientOperation, slug, "", "warn", utils.ErrorInvitationNotFound, session.UserSlug,
+		)
+
+		return nil, utils.RespondWithError(c, 404, utils.ErrorNotFound, nil, nil)
+	}
+
+	return &invitation, nil
+}
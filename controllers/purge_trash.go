@@ -0,0 +1,58 @@
+package controllers
+
+import (
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	fiberUtils "github.com/gofiber/fiber/v2/utils"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/config"
+	"github.com/liobrdev/simplepasswords_api_gateway/upstreams"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+const trashPurgeInterval = time.Duration(1) * time.Hour
+
+// RunTrashPurge has vaults permanently delete trashed items past TRASH_RETENTION_DAYS, for
+// every user at once. It runs in a single process per instance; the purge is idempotent, so it
+// doesn't matter if several instances run it. The first purge waits an interval, by which time
+// the health checks have found the upstreams.
+func RunTrashPurge(conf *config.AppConfig, vaults *upstreams.Pool) {
+	for {
+		time.Sleep(trashPurgeInterval)
+
+		if errString := purgeTrash(conf, vaults); errString != "" {
+			log.Printf("Failed vaults API purge_trash: %s", errString)
+		}
+	}
+}
+
+func purgeTrash(conf *config.AppConfig, vaults *upstreams.Pool) string {
+	requestID := fiberUtils.UUIDv4()
+	token, err := newVaultsToken(conf, utils.PurgeTrash, utils.ServiceSubjectPurgeTrash, requestID)
+
+	if err != nil {
+		return err.Error() + ";;"
+	}
+
+	upstream, err := vaults.Acquire()
+
+	if err != nil {
+		return err.Error() + ";;"
+	}
+
+	defer upstream.Release()
+
+	agent := fiber.Delete(upstream.URL + "/api/trash/expired")
+	agent.Set("Content-Type", "application/json")
+	agent.Set("Client-Operation", utils.PurgeTrash)
+	agent.Set("Authorization", "Bearer " + token)
+	agent.Set(fiber.HeaderXRequestID, requestID)
+	agent.Set(trashRetentionHeader, strconv.Itoa(conf.TRASH_RETENTION_DAYS))
+
+	_, _, errString := checkVaultsResponse(agent)
+
+	return errString
+}
@@ -0,0 +1,76 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/models"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+// RejectEmergencyAccess turns down an emergency contact's request, whether it's still waiting or
+// access was already granted. The contact stays designated and can ask again.
+func (H Handler) RejectEmergencyAccess(c *fiber.Ctx) error {
+	if header := c.Get("Client-Operation"); header != utils.RejectEmergencyAccess {
+		H.logger(c, utils.RejectEmergencyAccess, header, "", "warn", utils.ErrorClientOperation, "")
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	var session *models.ClientSession
+	var ok bool
+
+	if session, ok = c.UserContext().Value(sessionContextKey{}).(*models.ClientSession); !ok {
+		H.logger(c, utils.RejectEmergencyAccess, "", "", "error", "Failed session.User context", "")
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	contact, err := H.findEmergencyContact(c, utils.RejectEmergencyAccess, session, true)
+
+	if contact == nil {
+		return err
+	}
+
+	if contact.RequestedAt == nil {
+		H.logger(
+			c, utils.RejectEmergencyAccess, "", contact.Slug, "warn", utils.ErrorEmergencyContact,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 409, utils.ErrorNoEmergencyRequest, nil, nil)
+	}
+
+	// Clearing the notices too means a later request is reminded and opened afresh
+	if result := H.DBs.ApiGateway.Model(contact).Updates(map[string]interface{}{
+		"requested_at": nil,
+		"reminded_at":  nil,
+		"opened_at":    nil,
+	}); result.Error != nil {
+		H.logger(
+			c, utils.RejectEmergencyAccess, result.Error.Error(), contact.Slug, "error",
+			utils.ErrorFailedDB, session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	// Audit trail - every stage of emergency access is recorded
+	H.logger(
+		c, utils.RejectEmergencyAccess, contact.EmailAddress, contact.Slug, "info",
+		utils.InfoEmergencyRejected, session.UserSlug,
+	)
+
+	if H.Conf.ENVIRONMENT != "testing" {
+		if err = H.sendEmergencyEmail(
+			session.User.Name + " rejected your emergency access request", contact.EmailAddress,
+			"email_emergency_rejected.html", map[string]string{"GrantorName": session.User.Name},
+		); err != nil {
+			H.logger(
+				c, utils.RejectEmergencyAccess, err.Error(), contact.Slug, "error",
+				"Failed send emergency rejected email", session.UserSlug,
+			)
+		}
+	}
+
+	return c.SendStatus(204)
+}
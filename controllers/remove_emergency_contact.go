@@ -0,0 +1,58 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/models"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+// RemoveEmergencyContact revokes an emergency contact, along with any access they requested or
+// were granted
+func (H Handler) RemoveEmergencyContact(c *fiber.Ctx) error {
+	if header := c.Get("Client-Operation"); header != utils.RemoveEmergencyContact {
+		H.logger(c, utils.RemoveEmergencyContact, header, "", "warn", utils.ErrorClientOperation, "")
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	var session *models.ClientSession
+	var ok bool
+
+	if session, ok = c.UserContext().Value(sessionContextKey{}).(*models.ClientSession); !ok {
+		H.logger(c, utils.RemoveEmergencyContact, "", "", "error", "Failed session.User context", "")
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	contact, err := H.findEmergencyContact(c, utils.RemoveEmergencyContact, session, true)
+
+	if contact == nil {
+		return err
+	}
+
+	if err = H.DBs.ApiGateway.Transaction(func(tx *gorm.DB) error {
+		if result := tx.Where("contact_slug = ?", contact.Slug).
+			Delete(&models.EmergencyContactVault{}); result.Error != nil {
+			return result.Error
+		}
+
+		return tx.Delete(contact).Error
+	}); err != nil {
+		H.logger(
+			c, utils.RemoveEmergencyContact, err.Error(), contact.Slug, "error", utils.ErrorFailedDB,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	// Audit trail - every stage of emergency access is recorded
+	H.logger(
+		c, utils.RemoveEmergencyContact, contact.EmailAddress, contact.Slug, "info",
+		utils.InfoEmergencyRemoved, session.UserSlug,
+	)
+
+	return c.SendStatus(204)
+}
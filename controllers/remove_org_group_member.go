@@ -0,0 +1,59 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/models"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+func (H Handler) RemoveOrgGroupMember(c *fiber.Ctx) error {
+	if header := c.Get("Client-Operation"); header != utils.RemoveOrgGroupMember {
+		H.logger(c, utils.RemoveOrgGroupMember, header, "", "warn", utils.ErrorClientOperation, "")
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	var session *models.ClientSession
+	var ok bool
+
+	if session, ok = c.UserContext().Value(sessionContextKey{}).(*models.ClientSession); !ok {
+		H.logger(c, utils.RemoveOrgGroupMember, "", "", "error", "Failed session.User context", "")
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	member, err := H.requireOrgRole(c, utils.RemoveOrgGroupMember, session, utils.OrgRoleAdmin)
+
+	if member == nil {
+		return err
+	}
+
+	group, err := H.findOrgGroup(c, utils.RemoveOrgGroupMember, session, member.OrgSlug)
+
+	if group == nil {
+		return err
+	}
+
+	userSlug := c.Params("user_slug")
+	result := H.DBs.ApiGateway.Where("group_slug = ? AND user_slug = ?", group.Slug, userSlug).
+		Delete(&models.OrgGroupMember{})
+
+	if result.Error != nil {
+		H.logger(
+			c, utils.RemoveOrgGroupMember, result.Error.Error(), member.OrgSlug, "error",
+			utils.ErrorFailedDB, session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	} else if result.RowsAffected == 0 {
+		H.logger(
+			c, utils.RemoveOrgGroupMember, userSlug, group.Slug, "warn", utils.ErrorOrgMember,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 404, utils.ErrorNotFound, nil, nil)
+	}
+
+	return c.SendStatus(204)
+}
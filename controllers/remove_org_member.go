@@ -0,0 +1,107 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/models"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+// RemoveOrgMember removes a member from an organization and all of its groups, or lets a member
+// leave. Only owners can remove owners, and the last owner can't leave.
+func (H Handler) RemoveOrgMember(c *fiber.Ctx) error {
+	if header := c.Get("Client-Operation"); header != utils.RemoveOrgMember {
+		H.logger(c, utils.RemoveOrgMember, header, "", "warn", utils.ErrorClientOperation, "")
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	var session *models.ClientSession
+	var ok bool
+
+	if session, ok = c.UserContext().Value(sessionContextKey{}).(*models.ClientSession); !ok {
+		H.logger(c, utils.RemoveOrgMember, "", "", "error", "Failed session.User context", "")
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	userSlug := c.Params("user_slug")
+	required := utils.OrgRoleAdmin
+
+	if userSlug == session.UserSlug {
+		required = utils.OrgRoleMember
+	}
+
+	member, err := H.requireOrgRole(c, utils.RemoveOrgMember, session, required)
+
+	if member == nil {
+		return err
+	}
+
+	target, err := H.findOrgMember(member.OrgSlug, userSlug)
+
+	if err != nil {
+		H.logger(
+			c, utils.RemoveOrgMember, err.Error(), member.OrgSlug, "error", utils.ErrorFailedDB,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	} else if target == nil {
+		H.logger(
+			c, utils.RemoveOrgMember, userSlug, member.OrgSlug, "warn", utils.ErrorOrgMember,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 404, utils.ErrorNotFound, nil, nil)
+	}
+
+	if target.Role == utils.OrgRoleOwner {
+		if member.Role != utils.OrgRoleOwner {
+			H.logger(
+				c, utils.RemoveOrgMember, member.Role, member.OrgSlug, "warn", utils.ErrorOrgRole,
+				session.UserSlug,
+			)
+
+			return utils.RespondWithError(c, 403, utils.ErrorOrgPermission, nil, nil)
+		}
+
+		if count, err := H.countOrgOwners(member.OrgSlug); err != nil {
+			H.logger(
+				c, utils.RemoveOrgMember, err.Error(), member.OrgSlug, "error", utils.ErrorFailedDB,
+				session.UserSlug,
+			)
+
+			return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+		} else if count <= 1 {
+			H.logger(
+				c, utils.RemoveOrgMember, userSlug, member.OrgSlug, "warn", utils.ErrorOrgMember,
+				session.UserSlug,
+			)
+
+			return utils.RespondWithError(c, 409, utils.ErrorLastOrgOwner, nil, nil)
+		}
+	}
+
+	if err = H.DBs.ApiGateway.Transaction(func(tx *gorm.DB) error {
+		if result := tx.Where(
+			"user_slug = ? AND group_slug IN (?)", userSlug,
+			tx.Model(&models.OrgGroup{}).Select("slug").Where("org_slug = ?", member.OrgSlug),
+		).Delete(&models.OrgGroupMember{}); result.Error != nil {
+			return result.Error
+		}
+
+		return tx.Where("org_slug = ? AND user_slug = ?", member.OrgSlug, userSlug).
+			Delete(&models.OrgMember{}).Error
+	}); err != nil {
+		H.logger(
+			c, utils.RemoveOrgMember, err.Error(), member.OrgSlug, "error", utils.ErrorFailedDB,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	return c.SendStatus(204)
+}
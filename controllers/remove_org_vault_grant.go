@@ -0,0 +1,59 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/models"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+func (H Handler) RemoveOrgVaultGrant(c *fiber.Ctx) error {
+	if header := c.Get("Client-Operation"); header != utils.RemoveOrgVaultGrant {
+		H.logger(c, utils.RemoveOrgVaultGrant, header, "", "warn", utils.ErrorClientOperation, "")
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	var session *models.ClientSession
+	var ok bool
+
+	if session, ok = c.UserContext().Value(sessionContextKey{}).(*models.ClientSession); !ok {
+		H.logger(c, utils.RemoveOrgVaultGrant, "", "", "error", "Failed session.User context", "")
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	member, err := H.requireOrgRole(c, utils.RemoveOrgVaultGrant, session, utils.OrgRoleAdmin)
+
+	if member == nil {
+		return err
+	}
+
+	group, err := H.findOrgGroup(c, utils.RemoveOrgVaultGrant, session, member.OrgSlug)
+
+	if group == nil {
+		return err
+	}
+
+	vaultSlug := c.Params("vault_slug")
+	result := H.DBs.ApiGateway.Where("group_slug = ? AND vault_slug = ?", group.Slug, vaultSlug).
+		Delete(&models.OrgVaultGrant{})
+
+	if result.Error != nil {
+		H.logger(
+			c, utils.RemoveOrgVaultGrant, result.Error.Error(), member.OrgSlug, "error",
+			utils.ErrorFailedDB, session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	} else if result.RowsAffected == 0 {
+		H.logger(
+			c, utils.RemoveOrgVaultGrant, vaultSlug, group.Slug, "warn", utils.ErrorOrgVaultGrant,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 404, utils.ErrorNotFound, nil, nil)
+	}
+
+	return c.SendStatus(204)
+}
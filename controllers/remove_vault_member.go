@@ -0,0 +1,82 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/models"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+// RemoveVaultMember removes a member from a shared vault, or lets a member leave it. The member
+// may still hold the old vault key, so the vault is marked as needing a new one, and SetVaultKeys
+// only takes a full rotation until a manager's client has re-encrypted it.
+func (H Handler) RemoveVaultMember(c *fiber.Ctx) error {
+	if header := c.Get("Client-Operation"); header != utils.RemoveVaultMember {
+		H.logger(c, utils.RemoveVaultMember, header, "", "warn", utils.ErrorClientOperation, "")
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	var session *models.ClientSession
+	var ok bool
+
+	if session, ok = c.UserContext().Value(sessionContextKey{}).(*models.ClientSession); !ok {
+		H.logger(c, utils.RemoveVaultMember, "", "", "error", "Failed session.User context", "")
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	userSlug := c.Params("user_slug")
+	required := utils.VaultRoleManage
+
+	if userSlug == session.UserSlug {
+		required = utils.VaultRoleRead
+	}
+
+	member, err := H.requireVaultRole(c, utils.RemoveVaultMember, session, required)
+
+	if member == nil {
+		return err
+	}
+
+	if userSlug == member.SharedVault.OwnerSlug {
+		H.logger(
+			c, utils.RemoveVaultMember, userSlug, member.VaultSlug, "warn", utils.ErrorVaultMember,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, []string{
+			"The owner can't be removed from their vault",
+		})
+	}
+
+	var rowsAffected int64
+
+	if err = H.DBs.ApiGateway.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("vault_slug = ? AND user_slug = ?", member.VaultSlug, userSlug).
+			Delete(&models.VaultMember{})
+
+		if rowsAffected = result.RowsAffected; result.Error != nil || rowsAffected == 0 {
+			return result.Error
+		}
+
+		return tx.Model(&models.SharedVault{}).Where("vault_slug = ?", member.VaultSlug).
+			Update("rekey_required", true).Error
+	}); err != nil {
+		H.logger(
+			c, utils.RemoveVaultMember, err.Error(), "", "error", utils.ErrorFailedDB, session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	} else if rowsAffected == 0 {
+		H.logger(
+			c, utils.RemoveVaultMember, userSlug, member.VaultSlug, "warn", utils.ErrorVaultNotShared,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 404, utils.ErrorNotFound, nil, nil)
+	}
+
+	return c.SendStatus(204)
+}
@@ -0,0 +1,97 @@
+package controllers
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/models"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+// RequestEmergencyAccess starts an emergency contact's waiting period. The grantor is emailed
+// straight away, and the contact can read the selected vaults once the period is over, unless
+// the grantor rejects the request first.
+func (H Handler) RequestEmergencyAccess(c *fiber.Ctx) error {
+	if header := c.Get("Client-Operation"); header != utils.RequestEmergencyAccess {
+		H.logger(c, utils.RequestEmergencyAccess, header, "", "warn", utils.ErrorClientOperation, "")
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	var session *models.ClientSession
+	var ok bool
+
+	if session, ok = c.UserContext().Value(sessionContextKey{}).(*models.ClientSession); !ok {
+		H.logger(c, utils.RequestEmergencyAccess, "", "", "error", "Failed session.User context", "")
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	contact, err := H.findEmergencyContact(c, utils.RequestEmergencyAccess, session, false)
+
+	if contact == nil {
+		return err
+	}
+
+	if contact.RequestedAt != nil {
+		H.logger(
+			c, utils.RequestEmergencyAccess, contact.RequestedAt.String(), contact.Slug, "warn",
+			utils.ErrorEmergencyContact, session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 409, utils.ErrorEmergencyRequested, nil, nil)
+	}
+
+	now := time.Now().UTC()
+
+	if result := H.DBs.ApiGateway.Model(contact).Update("requested_at", now); result.Error != nil {
+		H.logger(
+			c, utils.RequestEmergencyAccess, result.Error.Error(), contact.Slug, "error",
+			utils.ErrorFailedDB, session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	contact.RequestedAt = &now
+	opensAt := emergencyOpensAt(contact)
+
+	// Audit trail - every stage of emergency access is recorded
+	H.logger(
+		c, utils.RequestEmergencyAccess, opensAt.String(), contact.Slug, "info",
+		utils.InfoEmergencyRequested, session.UserSlug,
+	)
+
+	if H.Conf.ENVIRONMENT != "testing" {
+		if err = H.sendEmergencyEmail(
+			"Emergency access to your vaults was requested", contact.Grantor.EmailAddress,
+			"email_emergency_request.html", map[string]string{
+				"Name":         contact.Grantor.Name,
+				"ContactName":  session.User.Name,
+				"ContactEmail": contact.EmailAddress,
+				"WaitingDays":  strconv.Itoa(contact.WaitingDays),
+				"OpensAt":      opensAt.Format(time.RFC1123),
+			},
+		); err != nil {
+			H.logger(
+				c, utils.RequestEmergencyAccess, err.Error(), contact.Slug, "error",
+				"Failed send emergency request email", session.UserSlug,
+			)
+		}
+	}
+
+	vaultSlugs, err := H.emergencyContactVaultSlugs([]string{contact.Slug})
+
+	if err != nil {
+		H.logger(
+			c, utils.RequestEmergencyAccess, err.Error(), contact.Slug, "error", utils.ErrorFailedDB,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	return c.Status(200).JSON(newEmergencyGrantResponse(contact, vaultSlugs[contact.Slug]))
+}
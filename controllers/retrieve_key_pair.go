@@ -0,0 +1,51 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/models"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+type RetrieveKeyPairResponseBody struct {
+	PublicKey           []byte `json:"public_key"`
+	EncryptedPrivateKey []byte `json:"encrypted_private_key"`
+}
+
+func (H Handler) RetrieveKeyPair(c *fiber.Ctx) error {
+	if header := c.Get("Client-Operation"); header != utils.RetrieveKeyPair {
+		H.logger(c, utils.RetrieveKeyPair, header, "", "warn", utils.ErrorClientOperation, "")
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	var session *models.ClientSession
+	var ok bool
+
+	if session, ok = c.UserContext().Value(sessionContextKey{}).(*models.ClientSession); !ok {
+		H.logger(c, utils.RetrieveKeyPair, "", "", "error", "Failed session.User context", "")
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	var keyPair models.UserKeyPair
+
+	if result := H.DBs.ApiGateway.Where("user_slug = ?", session.UserSlug).Limit(1).
+		Find(&keyPair); result.Error != nil {
+		H.logger(
+			c, utils.RetrieveKeyPair, result.Error.Error(), "", "error", utils.ErrorFailedDB,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	} else if result.RowsAffected == 0 {
+		H.logger(c, utils.RetrieveKeyPair, "", "", "warn", utils.ErrorNoKeyPair, session.UserSlug)
+
+		return utils.RespondWithError(c, 404, utils.ErrorNotFound, nil, nil)
+	}
+
+	return c.Status(200).JSON(&RetrieveKeyPairResponseBody{
+		PublicKey:           keyPair.PublicKey,
+		EncryptedPrivateKey: keyPair.EncryptedPrivateKey,
+	})
+}
@@ -0,0 +1,52 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/models"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+type RetrieveVaultKeyResponseBody struct {
+	WrappedKey    []byte `json:"wrapped_key"`
+	RekeyRequired bool   `json:"rekey_required"`
+}
+
+// RetrieveVaultKey returns the vault key wrapped for the user, for their client to open with
+// its private key
+func (H Handler) RetrieveVaultKey(c *fiber.Ctx) error {
+	if header := c.Get("Client-Operation"); header != utils.RetrieveVaultKey {
+		H.logger(c, utils.RetrieveVaultKey, header, "", "warn", utils.ErrorClientOperation, "")
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	var session *models.ClientSession
+	var ok bool
+
+	if session, ok = c.UserContext().Value(sessionContextKey{}).(*models.ClientSession); !ok {
+		H.logger(c, utils.RetrieveVaultKey, "", "", "error", "Failed session.User context", "")
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	member, err := H.requireVaultRole(c, utils.RetrieveVaultKey, session, utils.VaultRoleRead)
+
+	if member == nil {
+		return err
+	}
+
+	if len(member.WrappedKey) == 0 {
+		H.logger(
+			c, utils.RetrieveVaultKey, "No wrapped key", member.VaultSlug, "warn",
+			utils.ErrorVaultKeys, session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 404, utils.ErrorNotFound, nil, nil)
+	}
+
+	return c.Status(200).JSON(&RetrieveVaultKeyResponseBody{
+		WrappedKey:    member.WrappedKey,
+		RekeyRequired: member.SharedVault.RekeyRequired,
+	})
+}
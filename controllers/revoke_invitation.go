@@ -0,0 +1,63 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/models"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+func (H Handler) RevokeInvitation(c *fiber.Ctx) error {
+	if header := c.Get("Client-Operation"); header != utils.RevokeInvitation {
+		H.logger(c, utils.RevokeInvitation, header, "", "warn", utils.ErrorClientOperation, "")
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	var session *models.ClientSession
+	var ok bool
+
+	if session, ok = c.UserContext().Value(sessionContextKey{}).(*models.ClientSession); !ok {
+		H.logger(c, utils.RevokeInvitation, "", "", "error", "Failed session.User context", "")
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	member, err := H.requireVaultRole(c, utils.RevokeInvitation, session, utils.VaultRoleManage)
+
+	if member == nil {
+		return err
+	}
+
+	slug := c.Params("invitation_slug")
+
+	if !utils.InvitationSlugRegexp.MatchString(slug) {
+		H.logger(
+			c, utils.RevokeInvitation, slug, "", "warn", utils.ErrorInvitationNotFound,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 404, utils.ErrorNotFound, nil, nil)
+	}
+
+	result := H.DBs.ApiGateway.Where("slug = ? AND vault_slug = ?", slug, member.VaultSlug).
+		Delete(&models.VaultInvitation{})
+
+	if result.Error != nil {
+		H.logger(
+			c, utils.RevokeInvitation, result.Error.Error(), "", "error", utils.ErrorFailedDB,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	} else if result.RowsAffected == 0 {
+		H.logger(
+			c, utils.RevokeInvitation, slug, "", "warn", utils.ErrorInvitationNotFound,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 404, utils.ErrorNotFound, nil, nil)
+	}
+
+	return c.SendStatus(204)
+}
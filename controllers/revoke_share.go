@@ -0,0 +1,52 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/models"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+func (H Handler) RevokeShare(c *fiber.Ctx) error {
+	if header := c.Get("Client-Operation"); header != utils.RevokeShare {
+		H.logger(c, utils.RevokeShare, header, "", "warn", utils.ErrorClientOperation, "")
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	var session *models.ClientSession
+	var ok bool
+
+	if session, ok = c.UserContext().Value(sessionContextKey{}).(*models.ClientSession); !ok {
+		H.logger(c, utils.RevokeShare, "", "", "error", "Failed session.User context", "")
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	slug := c.Params("slug")
+
+	if !utils.ShareSlugRegexp.MatchString(slug) {
+		H.logger(c, utils.RevokeShare, slug, "", "warn", utils.ErrorShareNotFound, session.UserSlug)
+
+		return utils.RespondWithError(c, 404, utils.ErrorNotFound, nil, nil)
+	}
+
+	// Scoped to the owner, so another user's share looks the same as one that doesn't exist
+	result := H.DBs.ApiGateway.Where("slug = ? AND user_slug = ?", slug, session.UserSlug).
+		Delete(&models.Share{})
+
+	if result.Error != nil {
+		H.logger(
+			c, utils.RevokeShare, result.Error.Error(), "", "error", utils.ErrorFailedDB,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	} else if result.RowsAffected == 0 {
+		H.logger(c, utils.RevokeShare, slug, "", "warn", utils.ErrorShareNotFound, session.UserSlug)
+
+		return utils.RespondWithError(c, 404, utils.ErrorNotFound, nil, nil)
+	}
+
+	return c.SendStatus(204)
+}
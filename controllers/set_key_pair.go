@@ -0,0 +1,89 @@
+package controllers
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/models"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+// Both keys are base64. EncryptedPrivateKey is opaque to the gateway.
+type SetKeyPairRequestBody struct {
+	PublicKey           string `json:"public_key"`
+	EncryptedPrivateKey string `json:"encrypted_private_key"`
+}
+
+// SetKeyPair stores the user's key pair, replacing any earlier one. Vault keys wrapped for the
+// old public key can't be opened any more, so they're cleared until a manager wraps them again.
+func (H Handler) SetKeyPair(c *fiber.Ctx) error {
+	if header := c.Get("Client-Operation"); header != utils.SetKeyPair {
+		H.logger(c, utils.SetKeyPair, header, "", "warn", utils.ErrorClientOperation, "")
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	var session *models.ClientSession
+	var ok bool
+
+	if session, ok = c.UserContext().Value(sessionContextKey{}).(*models.ClientSession); !ok {
+		H.logger(c, utils.SetKeyPair, "", "", "error", "Failed session.User context", "")
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	reqBody := SetKeyPairRequestBody{}
+
+	if err := c.BodyParser(&reqBody); err != nil {
+		H.logger(c, utils.SetKeyPair, err.Error(), "", "error", utils.ErrorParse, session.UserSlug)
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	fieldErrors := map[string][]string{}
+	publicKey, validPublicKey := utils.DecodePublicKey(reqBody.PublicKey)
+
+	if !validPublicKey {
+		fieldErrors["public_key"] = []string{"Must be a base64 X25519 public key"}
+	}
+
+	encryptedPrivateKey, validPrivateKey := utils.DecodeKeyBlob(
+		reqBody.EncryptedPrivateKey, utils.EncryptedPrivateKeyMaxLength,
+	)
+
+	if !validPrivateKey {
+		fieldErrors["encrypted_private_key"] = []string{"Must be base64, at most 4096 bytes"}
+	}
+
+	if len(fieldErrors) > 0 {
+		H.logger(c, utils.SetKeyPair, "", "", "warn", utils.ErrorKeyPair, session.UserSlug)
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, fieldErrors, nil)
+	}
+
+	keyPair := models.UserKeyPair{
+		UserSlug:            session.UserSlug,
+		PublicKey:           publicKey,
+		EncryptedPrivateKey: encryptedPrivateKey,
+		UpdatedAt:           time.Now().UTC(),
+	}
+
+	if err := H.DBs.ApiGateway.Transaction(func(tx *gorm.DB) error {
+		if result := tx.Clauses(clause.OnConflict{UpdateAll: true}).Create(&keyPair);
+		result.Error != nil {
+			return result.Error
+		}
+
+		return tx.Model(&models.VaultMember{}).Where("user_slug = ?", session.UserSlug).
+			Update("wrapped_key", nil).Error
+	}); err != nil {
+		H.logger(c, utils.SetKeyPair, err.Error(), "", "error", utils.ErrorFailedDB, session.UserSlug)
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	return c.SendStatus(204)
+}
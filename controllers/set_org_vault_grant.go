@@ -0,0 +1,113 @@
+package controllers
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm/clause"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/models"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+type SetOrgVaultGrantRequestBody struct {
+	Role string `json:"role"`
+}
+
+// SetOrgVaultGrant gives a group a vault role on one of the organization's vaults, replacing
+// any role it had there
+func (H Handler) SetOrgVaultGrant(c *fiber.Ctx) error {
+	if header := c.Get("Client-Operation"); header != utils.SetOrgVaultGrant {
+		H.logger(c, utils.SetOrgVaultGrant, header, "", "warn", utils.ErrorClientOperation, "")
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	var session *models.ClientSession
+	var ok bool
+
+	if session, ok = c.UserContext().Value(sessionContextKey{}).(*models.ClientSession); !ok {
+		H.logger(c, utils.SetOrgVaultGrant, "", "", "error", "Failed session.User context", "")
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	member, err := H.requireOrgRole(c, utils.SetOrgVaultGrant, session, utils.OrgRoleAdmin)
+
+	if member == nil {
+		return err
+	}
+
+	group, err := H.findOrgGroup(c, utils.SetOrgVaultGrant, session, member.OrgSlug)
+
+	if group == nil {
+		return err
+	}
+
+	reqBody := SetOrgVaultGrantRequestBody{}
+
+	if err = c.BodyParser(&reqBody); err != nil {
+		H.logger(
+			c, utils.SetOrgVaultGrant, err.Error(), "", "error", utils.ErrorParse, session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	if !utils.ValidVaultRole(reqBody.Role) {
+		H.logger(
+			c, utils.SetOrgVaultGrant, reqBody.Role, member.OrgSlug, "warn", utils.ErrorOrgVaultGrant,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, map[string][]string{
+			"role": {"Must be one of: " + strings.Join(utils.VaultRoles, ", ")},
+		}, nil)
+	}
+
+	vaultSlug := c.Params("vault_slug")
+
+	if !utils.SlugRegexp.MatchString(vaultSlug) {
+		H.logger(
+			c, utils.SetOrgVaultGrant, vaultSlug, "", "warn", utils.ErrorParams, session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	vaultSlugs, statusCode, errString := H.vaultsUserVaultSlugs(c, member.OrgSlug)
+
+	if errString != "" {
+		H.logger(
+			c, utils.SetOrgVaultGrant, errString, member.OrgSlug, "error", utils.ErrorVaultsListVaults,
+			session.UserSlug,
+		)
+
+		return respondVaultsJSONError(c, statusCode)
+	} else if !vaultSlugs[vaultSlug] {
+		H.logger(
+			c, utils.SetOrgVaultGrant, vaultSlug, member.OrgSlug, "warn", utils.ErrorOrgVaultGrant,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 404, utils.ErrorNotFound, nil, nil)
+	}
+
+	if result := H.DBs.ApiGateway.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "group_slug"}, {Name: "vault_slug"}},
+		DoUpdates: clause.AssignmentColumns([]string{"role"}),
+	}).Omit("OrgGroup").Create(&models.OrgVaultGrant{
+		GroupSlug: group.Slug,
+		VaultSlug: vaultSlug,
+		Role:      reqBody.Role,
+	}); result.Error != nil {
+		H.logger(
+			c, utils.SetOrgVaultGrant, result.Error.Error(), member.OrgSlug, "error",
+			utils.ErrorFailedDB, session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	return c.SendStatus(204)
+}
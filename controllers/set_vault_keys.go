@@ -0,0 +1,160 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/models"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+// Keys maps member user slugs to the vault key sealed to their public key, in base64. Rotated
+// says the vault's items were re-encrypted under a new key, which every member then needs.
+type SetVaultKeysRequestBody struct {
+	Keys    map[string]string `json:"keys"`
+	Rotated bool              `json:"rotated"`
+}
+
+// SetVaultKeys stores vault keys that a manager's client wrapped for members, such as for ones
+// who just joined. After a member was removed, only a rotation covering every member is taken.
+func (H Handler) SetVaultKeys(c *fiber.Ctx) error {
+	if header := c.Get("Client-Operation"); header != utils.SetVaultKeys {
+		H.logger(c, utils.SetVaultKeys, header, "", "warn", utils.ErrorClientOperation, "")
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	var session *models.ClientSession
+	var ok bool
+
+	if session, ok = c.UserContext().Value(sessionContextKey{}).(*models.ClientSession); !ok {
+		H.logger(c, utils.SetVaultKeys, "", "", "error", "Failed session.User context", "")
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	member, err := H.requireVaultRole(c, utils.SetVaultKeys, session, utils.VaultRoleManage)
+
+	if member == nil {
+		return err
+	}
+
+	reqBody := SetVaultKeysRequestBody{}
+
+	if err = c.BodyParser(&reqBody); err != nil {
+		H.logger(c, utils.SetVaultKeys, err.Error(), "", "error", utils.ErrorParse, session.UserSlug)
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	if len(reqBody.Keys) == 0 {
+		H.logger(
+			c, utils.SetVaultKeys, "", member.VaultSlug, "warn", utils.ErrorVaultKeys,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, map[string][]string{
+			"keys": {"Required"},
+		}, nil)
+	}
+
+	if member.SharedVault.RekeyRequired && !reqBody.Rotated {
+		H.logger(
+			c, utils.SetVaultKeys, "Rekey required", member.VaultSlug, "warn", utils.ErrorVaultKeys,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 409, utils.ErrorRekeyRequired, nil, nil)
+	}
+
+	userSlugs := []string{}
+
+	if result := H.DBs.ApiGateway.Model(&models.VaultMember{}).
+		Where("vault_slug = ?", member.VaultSlug).Pluck("user_slug", &userSlugs); result.Error != nil {
+		H.logger(
+			c, utils.SetVaultKeys, result.Error.Error(), "", "error", utils.ErrorFailedDB,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	withKeyPairs := []string{}
+
+	if result := H.DBs.ApiGateway.Model(&models.UserKeyPair{}).
+		Where("user_slug IN ?", userSlugs).Pluck("user_slug", &withKeyPairs); result.Error != nil {
+		H.logger(
+			c, utils.SetVaultKeys, result.Error.Error(), "", "error", utils.ErrorFailedDB,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	hasKeyPair := map[string]bool{}
+
+	for _, userSlug := range withKeyPairs {
+		hasKeyPair[userSlug] = true
+	}
+
+	fieldErrors := map[string][]string{}
+	wrappedKeys := map[string][]byte{}
+
+	for userSlug, encoded := range reqBody.Keys {
+		if wrappedKey, valid := utils.DecodeKeyBlob(encoded, utils.WrappedKeyMaxLength); !valid {
+			fieldErrors["keys." + userSlug] = []string{"Must be base64, at most 1024 bytes"}
+		} else if !hasKeyPair[userSlug] {
+			fieldErrors["keys." + userSlug] = []string{"Not a member with a key pair"}
+		} else {
+			wrappedKeys[userSlug] = wrappedKey
+		}
+	}
+
+	if reqBody.Rotated {
+		for _, userSlug := range withKeyPairs {
+			if _, ok := reqBody.Keys[userSlug]; !ok {
+				fieldErrors["keys." + userSlug] = []string{"Required when rotating the vault key"}
+			}
+		}
+	}
+
+	if len(fieldErrors) > 0 {
+		H.logger(
+			c, utils.SetVaultKeys, "", member.VaultSlug, "warn", utils.ErrorVaultKeys,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, fieldErrors, nil)
+	}
+
+	if err = H.DBs.ApiGateway.Transaction(func(tx *gorm.DB) error {
+		if reqBody.Rotated {
+			// Members without a key pair yet can't be given the new key
+			if result := tx.Model(&models.VaultMember{}).Where("vault_slug = ?", member.VaultSlug).
+				Update("wrapped_key", nil); result.Error != nil {
+				return result.Error
+			}
+		}
+
+		for userSlug, wrappedKey := range wrappedKeys {
+			if result := tx.Model(&models.VaultMember{}).
+				Where("vault_slug = ? AND user_slug = ?", member.VaultSlug, userSlug).
+				Update("wrapped_key", wrappedKey); result.Error != nil {
+				return result.Error
+			}
+		}
+
+		if !reqBody.Rotated {
+			return nil
+		}
+
+		return tx.Model(&models.SharedVault{}).Where("vault_slug = ?", member.VaultSlug).
+			Update("rekey_required", false).Error
+	}); err != nil {
+		H.logger(c, utils.SetVaultKeys, err.Error(), "", "error", utils.ErrorFailedDB, session.UserSlug)
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	return c.SendStatus(204)
+}
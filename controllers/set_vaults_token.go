@@ -0,0 +1,40 @@
+package controllers
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/config"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+func newVaultsToken(conf *config.AppConfig, clientOperation, userSlug, requestID string) (
+	string, error,
+) {
+	return utils.SignServiceToken(
+		conf.VAULTS_TOKEN_KEY_ID, conf.VAULTS_TOKEN_KEY, userSlug, clientOperation, requestID,
+		time.Duration(60) * time.Second,
+	)
+}
+
+// Authorizes a vaults request on behalf of the session user, or the owner of the shared vault
+// it's on, with a short-lived signed token. Secret requests on a shared vault also name the
+// vault, so vaults keeps them to it.
+func (H Handler) setVaultsToken(c *fiber.Ctx, agent *fiber.Agent, clientOperation string) error {
+	userSlug := vaultsUserSlug(c)
+	requestID := c.GetRespHeader(fiber.HeaderXRequestID)
+
+	if token, err := newVaultsToken(H.Conf, clientOperation, userSlug, requestID); err != nil {
+		return err
+	} else {
+		agent.Set("Authorization", "Bearer " + token)
+		agent.Set(fiber.HeaderXRequestID, requestID)
+	}
+
+	if vaultSlug, ok := c.UserContext().Value(vaultScopeContextKey{}).(string); ok {
+		agent.Set(vaultSlugHeader, vaultSlug)
+	}
+
+	return nil
+}
@@ -0,0 +1,164 @@
+package controllers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/upstreams"
+)
+
+var errVaultsResponseTooLarge = errors.New("vaults response exceeds VAULTS_MAX_RESPONSE_BYTES")
+
+var vaultsStreamClient = &http.Client{
+	Transport: &http.Transport{
+		MaxIdleConnsPerHost:   64,
+		ResponseHeaderTimeout: time.Duration(10) * time.Second,
+	},
+}
+
+var streamedVaultsHeaders = []string{
+	fiber.HeaderContentType,
+	fiber.HeaderETag,
+	fiber.HeaderLastModified,
+	fiber.HeaderCacheControl,
+	fiber.HeaderContentDisposition,
+	"X-Next-Cursor",
+}
+
+type limitedVaultsBody struct {
+	body      io.ReadCloser
+	remaining int64
+	release   func()
+	once      sync.Once
+}
+
+func (L *limitedVaultsBody) Read(p []byte) (int, error) {
+	if L.remaining <= 0 {
+		// Limit reached - the body is only too large if there's actually more of it
+		n, err := L.body.Read(make([]byte, 1))
+
+		if n > 0 {
+			return 0, errVaultsResponseTooLarge
+		}
+
+		return 0, err
+	}
+
+	if int64(len(p)) > L.remaining {
+		p = p[:L.remaining]
+	}
+
+	n, err := L.body.Read(p)
+	L.remaining -= int64(n)
+
+	return n, err
+}
+
+func (L *limitedVaultsBody) Close() error {
+	L.once.Do(L.release)
+
+	return L.body.Close()
+}
+
+// Pipes a vaults response body straight through to the client, so gateway memory stays bounded
+// by the copy buffer instead of growing with the size of the vault. Takes ownership of
+// upstream, which is released once the body has been sent or on error.
+func (H Handler) streamVaultsResponse(
+	c *fiber.Ctx, upstream *upstreams.Upstream, method, path, clientOperation string,
+	headers map[string]string,
+) (errString string) {
+	released := false
+
+	defer func() {
+		if !released {
+			upstream.Release()
+		}
+	}()
+
+	req, err := http.NewRequest(method, upstream.URL + path, nil)
+
+	if err != nil {
+		return err.Error() + ";;"
+	}
+
+	requestID := c.GetRespHeader(fiber.HeaderXRequestID)
+	token, err := newVaultsToken(H.Conf, clientOperation, vaultsUserSlug(c), requestID)
+
+	if err != nil {
+		return err.Error() + ";;"
+	}
+
+	req.Header.Set("Authorization", "Bearer " + token)
+	req.Header.Set("Client-Operation", clientOperation)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(fiber.HeaderXRequestID, requestID)
+
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	if ifNoneMatch := c.Get(fiber.HeaderIfNoneMatch); ifNoneMatch != "" {
+		req.Header.Set(fiber.HeaderIfNoneMatch, ifNoneMatch)
+	}
+
+	resp, err := vaultsStreamClient.Do(req)
+
+	if err != nil {
+		return err.Error() + ";;"
+	}
+
+	if resp.StatusCode == 304 {
+		resp.Body.Close()
+
+		for _, key := range streamedVaultsHeaders {
+			if value := resp.Header.Get(key); value != "" && key != fiber.HeaderContentType {
+				c.Set(key, value)
+			}
+		}
+
+		c.Status(304)
+
+		return ""
+	}
+
+	if resp.StatusCode != 200 {
+		defer resp.Body.Close()
+
+		errString = strconv.Itoa(resp.StatusCode) + ";;"
+
+		if body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096)); len(body) > 0 {
+			errString += string(body) + ";;"
+		}
+
+		return errString
+	}
+
+	if resp.ContentLength > H.Conf.VAULTS_MAX_RESPONSE_BYTES {
+		resp.Body.Close()
+
+		return errVaultsResponseTooLarge.Error() + ";;" +
+			strconv.FormatInt(resp.ContentLength, 10) + ";;"
+	}
+
+	for _, key := range streamedVaultsHeaders {
+		if value := resp.Header.Get(key); value != "" {
+			c.Set(key, value)
+		}
+	}
+
+	released = true
+
+	c.Status(resp.StatusCode).SendStream(&limitedVaultsBody{
+		body:      resp.Body,
+		remaining: H.Conf.VAULTS_MAX_RESPONSE_BYTES,
+		release:   upstream.Release,
+	}, int(resp.ContentLength))
+
+	return ""
+}
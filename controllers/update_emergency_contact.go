@@ -0,0 +1,173 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/models"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+type UpdateEmergencyContactRequestBody struct {
+	WaitingDays *int      `json:"waiting_days"`
+	VaultSlugs  *[]string `json:"vault_slugs"`
+}
+
+// UpdateEmergencyContact changes an emergency contact's waiting period, or replaces the vaults
+// they can read once access is open. Only the user's own vaults can be selected.
+func (H Handler) UpdateEmergencyContact(c *fiber.Ctx) error {
+	if header := c.Get("Client-Operation"); header != utils.UpdateEmergencyContact {
+		H.logger(c, utils.UpdateEmergencyContact, header, "", "warn", utils.ErrorClientOperation, "")
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	var session *models.ClientSession
+	var ok bool
+
+	if session, ok = c.UserContext().Value(sessionContextKey{}).(*models.ClientSession); !ok {
+		H.logger(c, utils.UpdateEmergencyContact, "", "", "error", "Failed session.User context", "")
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	contact, err := H.findEmergencyContact(c, utils.UpdateEmergencyContact, session, true)
+
+	if contact == nil {
+		return err
+	}
+
+	reqBody := UpdateEmergencyContactRequestBody{}
+
+	if err = c.BodyParser(&reqBody); err != nil {
+		H.logger(
+			c, utils.UpdateEmergencyContact, err.Error(), "", "error", utils.ErrorParse,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	if reqBody.WaitingDays == nil && reqBody.VaultSlugs == nil {
+		H.logger(
+			c, utils.UpdateEmergencyContact, "", contact.Slug, "warn", utils.ErrorEmergencyContact,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, []string{
+			"Nothing to update",
+		})
+	}
+
+	fieldErrors := map[string][]string{}
+
+	if reqBody.WaitingDays != nil {
+		if daysErrors := utils.ValidateWaitingDays(*reqBody.WaitingDays); daysErrors != nil {
+			fieldErrors["waiting_days"] = daysErrors
+		}
+	}
+
+	selected := map[string]bool{}
+
+	if reqBody.VaultSlugs != nil {
+		for _, vaultSlug := range *reqBody.VaultSlugs {
+			if !utils.SlugRegexp.MatchString(vaultSlug) {
+				fieldErrors["vault_slugs"] = []string{"Invalid vault slug"}
+
+				break
+			}
+
+			selected[vaultSlug] = true
+		}
+	}
+
+	if len(fieldErrors) > 0 {
+		H.logger(
+			c, utils.UpdateEmergencyContact, "", contact.Slug, "warn", utils.ErrorEmergencyContact,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, fieldErrors, nil)
+	}
+
+	if len(selected) > 0 {
+		vaultSlugs, statusCode, errString := H.vaultsUserVaultSlugs(c, session.UserSlug)
+
+		if errString != "" {
+			H.logger(
+				c, utils.UpdateEmergencyContact, errString, contact.Slug, "error",
+				utils.ErrorVaultsListVaults, session.UserSlug,
+			)
+
+			return respondVaultsJSONError(c, statusCode)
+		}
+
+		for vaultSlug := range selected {
+			if !vaultSlugs[vaultSlug] {
+				H.logger(
+					c, utils.UpdateEmergencyContact, vaultSlug, contact.Slug, "warn",
+					utils.ErrorEmergencyContact, session.UserSlug,
+				)
+
+				return utils.RespondWithError(c, 404, utils.ErrorNotFound, nil, nil)
+			}
+		}
+	}
+
+	if reqBody.WaitingDays != nil {
+		contact.WaitingDays = *reqBody.WaitingDays
+	}
+
+	if err = H.DBs.ApiGateway.Transaction(func(tx *gorm.DB) error {
+		if result := tx.Model(contact).Update("waiting_days", contact.WaitingDays);
+		result.Error != nil {
+			return result.Error
+		}
+
+		if reqBody.VaultSlugs == nil {
+			return nil
+		}
+
+		if result := tx.Where("contact_slug = ?", contact.Slug).
+			Delete(&models.EmergencyContactVault{}); result.Error != nil {
+			return result.Error
+		}
+
+		for vaultSlug := range selected {
+			if result := tx.Omit("EmergencyContact").Create(&models.EmergencyContactVault{
+				ContactSlug: contact.Slug,
+				VaultSlug:   vaultSlug,
+			}); result.Error != nil {
+				return result.Error
+			}
+		}
+
+		return nil
+	}); err != nil {
+		H.logger(
+			c, utils.UpdateEmergencyContact, err.Error(), contact.Slug, "error", utils.ErrorFailedDB,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	vaultSlugs, err := H.emergencyContactVaultSlugs([]string{contact.Slug})
+
+	if err != nil {
+		H.logger(
+			c, utils.UpdateEmergencyContact, err.Error(), contact.Slug, "error", utils.ErrorFailedDB,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	// Audit trail - every stage of emergency access is recorded
+	H.logger(
+		c, utils.UpdateEmergencyContact, contact.EmailAddress, contact.Slug, "info",
+		utils.InfoEmergencyUpdated, session.UserSlug,
+	)
+
+	return c.Status(200).JSON(newEmergencyContactResponse(contact, vaultSlugs[contact.Slug]))
+}
@@ -0,0 +1,120 @@
+package controllers
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/models"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+type UpdateOrgMemberRequestBody struct {
+	Role string `json:"role"`
+}
+
+// UpdateOrgMember changes a member's org role. Only owners can make someone an owner or change
+// an owner's role, and the last owner can't step down.
+func (H Handler) UpdateOrgMember(c *fiber.Ctx) error {
+	if header := c.Get("Client-Operation"); header != utils.UpdateOrgMember {
+		H.logger(c, utils.UpdateOrgMember, header, "", "warn", utils.ErrorClientOperation, "")
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	var session *models.ClientSession
+	var ok bool
+
+	if session, ok = c.UserContext().Value(sessionContextKey{}).(*models.ClientSession); !ok {
+		H.logger(c, utils.UpdateOrgMember, "", "", "error", "Failed session.User context", "")
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	member, err := H.requireOrgRole(c, utils.UpdateOrgMember, session, utils.OrgRoleAdmin)
+
+	if member == nil {
+		return err
+	}
+
+	reqBody := UpdateOrgMemberRequestBody{}
+
+	if err = c.BodyParser(&reqBody); err != nil {
+		H.logger(
+			c, utils.UpdateOrgMember, err.Error(), "", "error", utils.ErrorParse, session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	if !utils.ValidOrgRole(reqBody.Role) {
+		H.logger(
+			c, utils.UpdateOrgMember, reqBody.Role, member.OrgSlug, "warn", utils.ErrorOrgMember,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, map[string][]string{
+			"role": {"Must be one of: " + strings.Join(utils.OrgRoles, ", ")},
+		}, nil)
+	}
+
+	userSlug := c.Params("user_slug")
+	target, err := H.findOrgMember(member.OrgSlug, userSlug)
+
+	if err != nil {
+		H.logger(
+			c, utils.UpdateOrgMember, err.Error(), member.OrgSlug, "error", utils.ErrorFailedDB,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	} else if target == nil {
+		H.logger(
+			c, utils.UpdateOrgMember, userSlug, member.OrgSlug, "warn", utils.ErrorOrgMember,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 404, utils.ErrorNotFound, nil, nil)
+	}
+
+	if (target.Role == utils.OrgRoleOwner || reqBody.Role == utils.OrgRoleOwner) &&
+	member.Role != utils.OrgRoleOwner {
+		H.logger(
+			c, utils.UpdateOrgMember, member.Role, member.OrgSlug, "warn", utils.ErrorOrgRole,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 403, utils.ErrorOrgPermission, nil, nil)
+	}
+
+	if target.Role == utils.OrgRoleOwner && reqBody.Role != utils.OrgRoleOwner {
+		if count, err := H.countOrgOwners(member.OrgSlug); err != nil {
+			H.logger(
+				c, utils.UpdateOrgMember, err.Error(), member.OrgSlug, "error", utils.ErrorFailedDB,
+				session.UserSlug,
+			)
+
+			return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+		} else if count <= 1 {
+			H.logger(
+				c, utils.UpdateOrgMember, userSlug, member.OrgSlug, "warn", utils.ErrorOrgMember,
+				session.UserSlug,
+			)
+
+			return utils.RespondWithError(c, 409, utils.ErrorLastOrgOwner, nil, nil)
+		}
+	}
+
+	if result := H.DBs.ApiGateway.Model(&models.OrgMember{}).
+		Where("org_slug = ? AND user_slug = ?", member.OrgSlug, userSlug).
+		Update("role", reqBody.Role); result.Error != nil {
+		H.logger(
+			c, utils.UpdateOrgMember, result.Error.Error(), "", "error", utils.ErrorFailedDB,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	return c.SendStatus(204)
+}
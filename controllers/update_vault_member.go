@@ -0,0 +1,94 @@
+package controllers
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/models"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+type UpdateVaultMemberRequestBody struct {
+	Role string `json:"role"`
+}
+
+// UpdateVaultMember changes a member's role. The owner always keeps the manage role.
+func (H Handler) UpdateVaultMember(c *fiber.Ctx) error {
+	if header := c.Get("Client-Operation"); header != utils.UpdateVaultMember {
+		H.logger(c, utils.UpdateVaultMember, header, "", "warn", utils.ErrorClientOperation, "")
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	var session *models.ClientSession
+	var ok bool
+
+	if session, ok = c.UserContext().Value(sessionContextKey{}).(*models.ClientSession); !ok {
+		H.logger(c, utils.UpdateVaultMember, "", "", "error", "Failed session.User context", "")
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	member, err := H.requireVaultRole(c, utils.UpdateVaultMember, session, utils.VaultRoleManage)
+
+	if member == nil {
+		return err
+	}
+
+	reqBody := UpdateVaultMemberRequestBody{}
+
+	if err = c.BodyParser(&reqBody); err != nil {
+		H.logger(
+			c, utils.UpdateVaultMember, err.Error(), "", "error", utils.ErrorParse, session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	if !utils.ValidVaultRole(reqBody.Role) {
+		H.logger(
+			c, utils.UpdateVaultMember, reqBody.Role, member.VaultSlug, "warn", utils.ErrorVaultMember,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, map[string][]string{
+			"role": {"Must be one of: " + strings.Join(utils.VaultRoles, ", ")},
+		}, nil)
+	}
+
+	userSlug := c.Params("user_slug")
+
+	if userSlug == member.SharedVault.OwnerSlug {
+		H.logger(
+			c, utils.UpdateVaultMember, userSlug, member.VaultSlug, "warn", utils.ErrorVaultMember,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, []string{
+			"The owner's role can't be changed",
+		})
+	}
+
+	result := H.DBs.ApiGateway.Model(&models.VaultMember{}).
+		Where("vault_slug = ? AND user_slug = ?", member.VaultSlug, userSlug).
+		Update("role", reqBody.Role)
+
+	if result.Error != nil {
+		H.logger(
+			c, utils.UpdateVaultMember, result.Error.Error(), "", "error", utils.ErrorFailedDB,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	} else if result.RowsAffected == 0 {
+		H.logger(
+			c, utils.UpdateVaultMember, userSlug, member.VaultSlug, "warn", utils.ErrorVaultNotShared,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 404, utils.ErrorNotFound, nil, nil)
+	}
+
+	return c.SendStatus(204)
+}
@@ -0,0 +1,392 @@
+package controllers
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/models"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+// Set by CheckVaultAccess to the owner of the shared vault, or the organization, that a request
+// acts for
+type vaultOwnerContextKey struct{}
+
+// Set by CheckVaultAccess to the shared vault a secret request is limited to, which vaults is
+// told so it can check the secret is really in it
+type vaultScopeContextKey struct{}
+
+const vaultSlugHeader string = "Vault-Slug"
+
+// The least role a member needs for each operation in a shared vault. Operations missing here
+// are the owner's alone - that includes move_entry and move_secret, since the policy only sees
+// one of the two vaults a move touches.
+var vaultOpRoles = map[string]string{
+	utils.RetrieveVault:        utils.VaultRoleRead,
+	utils.RetrieveEntry:        utils.VaultRoleRead,
+	utils.GetEntryTOTP:         utils.VaultRoleRead,
+	utils.ListSecretVersions:   utils.VaultRoleRead,
+	utils.ListAttachments:      utils.VaultRoleRead,
+	utils.DownloadAttachment:   utils.VaultRoleRead,
+	utils.CreateEntry:          utils.VaultRoleWrite,
+	utils.UpdateEntry:          utils.VaultRoleWrite,
+	utils.DeleteEntry:          utils.VaultRoleWrite,
+	utils.CreateSecret:         utils.VaultRoleWrite,
+	utils.UpdateSecret:         utils.VaultRoleWrite,
+	utils.DeleteSecret:         utils.VaultRoleWrite,
+	utils.RestoreSecretVersion: utils.VaultRoleWrite,
+	utils.AddTag:               utils.VaultRoleWrite,
+	utils.RemoveTag:            utils.VaultRoleWrite,
+	utils.SetFavorite:          utils.VaultRoleWrite,
+	utils.UploadAttachment:     utils.VaultRoleWrite,
+	utils.DeleteAttachment:     utils.VaultRoleWrite,
+	utils.UpdateVault:          utils.VaultRoleManage,
+}
+
+type VaultMemberResponse struct {
+	UserSlug     string    `json:"user_slug"`
+	Name         string    `json:"name"`
+	EmailAddress string    `json:"email_address"`
+	Role         string    `json:"role"`
+	IsOwner      bool      `json:"is_owner"`
+	PublicKey    []byte    `json:"public_key"`
+	HasKey       bool      `json:"has_key"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type VaultInvitationResponse struct {
+	InvitationSlug string    `json:"invitation_slug"`
+	VaultSlug      string    `json:"vault_slug"`
+	InviterName    string    `json:"inviter_name"`
+	EmailAddress   string    `json:"email_address"`
+	Role           string    `json:"role"`
+	CreatedAt      time.Time `json:"created_at"`
+	ExpiresAt      time.Time `json:"expires_at"`
+}
+
+func newVaultInvitationResponse(invitation *models.VaultInvitation) VaultInvitationResponse {
+	return VaultInvitationResponse{
+		InvitationSlug: invitation.Slug,
+		VaultSlug:      invitation.VaultSlug,
+		InviterName:    invitation.Inviter.Name,
+		EmailAddress:   invitation.EmailAddress,
+		Role:           invitation.Role,
+		CreatedAt:      invitation.CreatedAt,
+		ExpiresAt:      invitation.ExpiresAt,
+	}
+}
+
+// vaultsUserSlug is the user that vaults requests are made for: the owner of the shared vault
+// or the organization if CheckVaultAccess let the request through for one, otherwise the
+// session user
+func vaultsUserSlug(c *fiber.Ctx) string {
+	if ownerSlug, ok := c.UserContext().Value(vaultOwnerContextKey{}).(string); ok {
+		return ownerSlug
+	}
+
+	if session, ok := c.UserContext().Value(sessionContextKey{}).(*models.ClientSession); ok {
+		return session.UserSlug
+	}
+
+	return ""
+}
+
+// findVaultMember returns the user's membership of a shared vault with its SharedVault, or nil
+// if the vault isn't shared with them
+func (H Handler) findVaultMember(vaultSlug, userSlug string) (*models.VaultMember, error) {
+	var member models.VaultMember
+
+	result := H.DBs.ApiGateway.Preload("SharedVault").
+		Where("vault_slug = ? AND user_slug = ?", vaultSlug, userSlug).Limit(1).Find(&member)
+
+	if result.Error != nil {
+		return nil, result.Error
+	} else if result.RowsAffected == 0 {
+		return nil, nil
+	}
+
+	return &member, nil
+}
+
+// What the policy grants the session user for a request: the vaults service user to act for,
+// and the vault role that decides which operations are allowed, unless Full allows them all
+type vaultGrant struct {
+	OwnerSlug string
+	Role      string
+	Full      bool
+}
+
+func (grant *vaultGrant) allows(clientOperation string) bool {
+	if grant.Full {
+		return true
+	}
+
+	required, ok := vaultOpRoles[clientOperation]
+
+	return ok && utils.VaultRoleAtLeast(grant.Role, required)
+}
+
+// sharedVaultGrant returns what a shared vault's member may do in it, or nil if the vault
+// isn't shared with them
+func (H Handler) sharedVaultGrant(vaultSlug, userSlug string) (*vaultGrant, error) {
+	member, err := H.findVaultMember(vaultSlug, userSlug)
+
+	if member == nil {
+		return nil, err
+	}
+
+	return &vaultGrant{
+		OwnerSlug: member.SharedVault.OwnerSlug,
+		Role:      member.Role,
+		Full:      member.SharedVault.OwnerSlug == userSlug,
+	}, nil
+}
+
+// CheckVaultAccess is the policy every vaults request goes through before it reaches the vaults
+// service. Requests with an Org-Slug header act for that organization, as far as the user's org
+// role and groups allow, and requests with an Emergency-Contact-Slug header read the grantor's
+// vaults once the contact's emergency access is open. Otherwise the vault is the :slug of
+// /api/vaults routes, the vault_slug of a create_entry body, or, for requests on a shared
+// vault's entries, the Vault-Slug header, and a member of a shared vault acts for its owner as
+// far as their vault role allows. Any other request passes through unchanged, so the vaults
+// service keeps it to the user's own vaults.
+func (H Handler) CheckVaultAccess(c *fiber.Ctx) error {
+	clientOperation := c.Get("Client-Operation")
+
+	var session *models.ClientSession
+	var ok bool
+
+	if session, ok = c.UserContext().Value(sessionContextKey{}).(*models.ClientSession); !ok {
+		H.logger(c, clientOperation, "", "", "error", "Failed session.User context", "")
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	vaultSlug, fromHeader := requestVaultSlug(c, clientOperation)
+
+	if !utils.SlugRegexp.MatchString(vaultSlug) {
+		if fromHeader {
+			H.logger(c, clientOperation, vaultSlug, "", "warn", utils.ErrorParams, session.UserSlug)
+
+			return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+		}
+
+		vaultSlug = ""
+	}
+
+	orgSlug := c.Get(orgSlugHeader)
+	contactSlug := c.Get(emergencyContactSlugHeader)
+	var grant *vaultGrant
+	var err error
+
+	if orgSlug != "" {
+		grant, err = H.orgVaultGrant(orgSlug, vaultSlug, session.UserSlug)
+	} else if contactSlug != "" {
+		grant, err = H.emergencyVaultGrant(contactSlug, vaultSlug, &session.User)
+	} else if vaultSlug != "" {
+		grant, err = H.sharedVaultGrant(vaultSlug, session.UserSlug)
+	}
+
+	if err != nil {
+		H.logger(
+			c, clientOperation, err.Error(), vaultSlug, "error", utils.ErrorFailedDB, session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	if grant == nil {
+		if orgSlug != "" {
+			H.logger(c, clientOperation, orgSlug, "", "warn", utils.ErrorOrgNotFound, session.UserSlug)
+
+			return utils.RespondWithError(c, 404, utils.ErrorNotFound, nil, nil)
+		} else if contactSlug != "" {
+			H.logger(
+				c, clientOperation, contactSlug, "", "warn", utils.ErrorEmergencyNotFound,
+				session.UserSlug,
+			)
+
+			return utils.RespondWithError(c, 404, utils.ErrorNotFound, nil, nil)
+		} else if fromHeader {
+			H.logger(
+				c, clientOperation, vaultSlug, "", "warn", utils.ErrorVaultNotShared, session.UserSlug,
+			)
+
+			return utils.RespondWithError(c, 404, utils.ErrorNotFound, nil, nil)
+		}
+
+		return c.Next()
+	}
+
+	if grant.OwnerSlug == session.UserSlug {
+		return c.Next()
+	}
+
+	if !grant.allows(clientOperation) {
+		H.logger(
+			c, clientOperation, grant.Role, vaultSlug, "warn", utils.ErrorVaultRole, session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 403, utils.ErrorVaultPermission, nil, nil)
+	}
+
+	c.SetUserContext(context.WithValue(c.UserContext(), vaultOwnerContextKey{}, grant.OwnerSlug))
+
+	// Audit trail - every read of a vault through emergency access is recorded
+	if contactSlug != "" {
+		H.logger(
+			c, clientOperation, grant.OwnerSlug, vaultSlug, "info", utils.InfoEmergencyAccess,
+			session.UserSlug,
+		)
+	}
+
+	// Entry slugs alone don't say which vault they're in, so make sure the entry really is in
+	// the vault the user has a role in, and not elsewhere among the owner's vaults
+	if fromHeader && !grant.Full {
+		// Secret slugs aren't in the search documents, so vaults does that check instead
+		if strings.HasPrefix(c.Route().Path, "/api/secrets") {
+			c.SetUserContext(context.WithValue(c.UserContext(), vaultScopeContextKey{}, vaultSlug))
+
+			return c.Next()
+		}
+
+		docs, statusCode, errString := H.vaultsSearchDocuments(c, grant.OwnerSlug)
+
+		if errString != "" {
+			H.logger(
+				c, clientOperation, errString, vaultSlug, "error", utils.ErrorVaultsSearchEntries,
+				session.UserSlug,
+			)
+
+			return respondVaultsJSONError(c, statusCode)
+		}
+
+		entrySlug := c.Params("slug")
+		found := false
+
+		for _, doc := range docs {
+			if doc.EntrySlug == entrySlug && doc.VaultSlug == vaultSlug {
+				found = true
+
+				break
+			}
+		}
+
+		if !found {
+			H.logger(
+				c, clientOperation, entrySlug, vaultSlug, "warn", utils.ErrorVaultNotShared,
+				session.UserSlug,
+			)
+
+			return utils.RespondWithError(c, 404, utils.ErrorNotFound, nil, nil)
+		}
+	}
+
+	return c.Next()
+}
+
+// Returns the vault a request is on, and whether it came from the Vault-Slug header
+func requestVaultSlug(c *fiber.Ctx, clientOperation string) (string, bool) {
+	if c.Route().Path == "/api/vaults/:slug" {
+		return c.Params("slug"), false
+	}
+
+	if clientOperation == utils.CreateEntry {
+		body := struct {
+			VaultSlug string `json:"vault_slug"`
+		}{}
+
+		json.Unmarshal(c.Body(), &body)
+
+		return body.VaultSlug, false
+	}
+
+	if vaultSlug := c.Get(vaultSlugHeader); vaultSlug != "" {
+		return vaultSlug, true
+	}
+
+	return "", false
+}
+
+// requireVaultRole loads the session user's membership of the shared vault in the :slug param
+// and checks it grants at least the required role. On failure it responds itself and returns a
+// nil member, so handlers return the error it gives.
+func (H Handler) requireVaultRole(
+	c *fiber.Ctx, clientOperation string, session *models.ClientSession, required string,
+) (*models.VaultMember, error) {
+	vaultSlug := c.Params("slug")
+
+	if !utils.SlugRegexp.MatchString(vaultSlug) {
+		H.logger(c, clientOperation, vaultSlug, "", "warn", utils.ErrorParams, session.UserSlug)
+
+		return nil, utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	member, err := H.findVaultMember(vaultSlug, session.UserSlug)
+
+	if err != nil {
+		H.logger(
+			c, clientOperation, err.Error(), vaultSlug, "error", utils.ErrorFailedDB, session.UserSlug,
+		)
+
+		return nil, utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	} else if member == nil {
+		H.logger(
+			c, clientOperation, vaultSlug, "", "warn", utils.ErrorVaultNotShared, session.UserSlug,
+		)
+
+		return nil, utils.RespondWithError(c, 404, utils.ErrorNotFound, nil, nil)
+	} else if !utils.VaultRoleAtLeast(member.Role, required) {
+		H.logger(
+			c, clientOperation, member.Role, vaultSlug, "warn", utils.ErrorVaultRole, session.UserSlug,
+		)
+
+		return nil, utils.RespondWithError(c, 403, utils.ErrorVaultPermission, nil, nil)
+	}
+
+	return member, nil
+}
+
+// findInvitation returns the session user's pending invitation in the :slug param. Responds
+// itself and returns nil if there's no such invitation, so one sent to someone else looks the
+// same as one that doesn't exist.
+func (H Handler) findInvitation(
+	c *fiber.Ctx, clientOperation string, session *models.ClientSession,
+) (*models.VaultInvitation, error) {
+	slug := c.Params("slug")
+	var invitation models.VaultInvitation
+
+	if !utils.InvitationSlugRegexp.MatchString(slug) {
+		H.logger(
+			c, clientOperation, slug, "", "warn", utils.ErrorInvitationNotFound, session.UserSlug,
+		)
+
+		return nil, utils.RespondWithError(c, 404, utils.ErrorNotFound, nil, nil)
+	}
+
+	result := H.DBs.ApiGateway.Where(
+		"slug = ? AND LOWER(email_address) = LOWER(?) AND expires_at > ?", slug,
+		session.User.EmailAddress, time.Now().UTC(),
+	).Limit(1).Find(&invitation)
+
+	if result.Error != nil {
+		H.logger(
+			c, clientOperation, result.Error.Error(), "", "error", utils.ErrorFailedDB,
+			session.UserSlug,
+		)
+
+		return nil, utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	} else if result.RowsAffected == 0 {
+		H.logger(
+			c, clientOperation, slug, "", "warn", utils.ErrorInvitationNotFound, session.UserSlug,
+		)
+
+		return nil, utils.RespondWithError(c, 404, utils.ErrorNotFound, nil, nil)
+	}
+
+	return &invitation, nil
+}
@@ -0,0 +1,109 @@
+package controllers
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/models"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+var tagFieldError = "Must be 1 to " + strconv.Itoa(utils.TagMaxLength) +
+	" letters, digits, spaces, '-' or '_', starting with a letter or digit"
+
+type TagRequestBody struct {
+	Tag string `json:"tag"`
+}
+
+func (H Handler) VaultsAddTag(c *fiber.Ctx) error {
+	if header := c.Get("Client-Operation"); header != utils.AddTag {
+		H.logger(c, utils.AddTag, header, "", "warn", utils.ErrorClientOperation, "")
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	var session *models.ClientSession
+	var ok bool
+
+	if session, ok = c.UserContext().Value(sessionContextKey{}).(*models.ClientSession); !ok {
+		H.logger(c, utils.AddTag, "", "", "error", "Failed session.User context", "")
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	reqBody := TagRequestBody{}
+
+	if err := c.BodyParser(&reqBody); err != nil {
+		H.logger(c, utils.AddTag, err.Error(), "", "error", utils.ErrorParse, session.UserSlug)
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	slug := c.Params("slug")
+
+	if reqBody.Tag, ok = utils.NormalizeTag(reqBody.Tag); !ok {
+		H.logger(c, utils.AddTag, reqBody.Tag, slug, "warn", utils.ErrorTag, session.UserSlug)
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, map[string][]string{
+			"tag": {tagFieldError},
+		}, nil)
+	}
+
+	// Current tags come from the search documents, which also confirms the entry is the user's
+	docs, statusCode, errString := H.vaultsSearchDocuments(c, vaultsUserSlug(c))
+
+	if errString != "" {
+		H.logger(
+			c, utils.AddTag, errString, "", "error", utils.ErrorVaultsSearchEntries, session.UserSlug,
+		)
+
+		return respondVaultsJSONError(c, statusCode)
+	}
+
+	var entry *utils.SearchDocument
+
+	for i := range docs {
+		if docs[i].EntrySlug == slug {
+			entry = &docs[i]
+			break
+		}
+	}
+
+	if entry == nil {
+		H.logger(c, utils.AddTag, reqBody.Tag, slug, "warn", utils.ErrorTag, session.UserSlug)
+
+		return utils.RespondWithError(c, 404, utils.ErrorNotFound, nil, nil)
+	}
+
+	if entry.HasTag(reqBody.Tag) {
+		return c.SendStatus(204)
+	}
+
+	if len(entry.Tags) >= utils.TagsPerEntryMax {
+		H.logger(c, utils.AddTag, reqBody.Tag, slug, "warn", utils.ErrorTagLimit, session.UserSlug)
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, map[string][]string{
+			"tag": {"Entries can have at most " + strconv.Itoa(utils.TagsPerEntryMax) + " tags"},
+		}, nil)
+	}
+
+	if userTags := utils.UserTags(docs);
+	userTags[reqBody.Tag] == 0 && len(userTags) >= utils.TagsPerUserMax {
+		H.logger(c, utils.AddTag, reqBody.Tag, slug, "warn", utils.ErrorTagLimit, session.UserSlug)
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, map[string][]string{
+			"tag": {"You can have at most " + strconv.Itoa(utils.TagsPerUserMax) + " different tags"},
+		}, nil)
+	}
+
+	if statusCode, errString = H.vaultsJSON(
+		c, fiber.MethodPost, "/api/entries/" + slug + "/tags", utils.AddTag, nil, &reqBody, nil,
+	); errString != "" {
+		H.logger(c, utils.AddTag, errString, "", "error", utils.ErrorVaultsAddTag, session.UserSlug)
+
+		return respondVaultsJSONError(c, statusCode)
+	}
+
+	return c.SendStatus(204)
+}
@@ -0,0 +1,316 @@
+package controllers
+
+import (
+	"strconv"
+
+	"github.com/goccy/go-json"
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/models"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+const batchMaxOperations int = 100
+
+type batchOpRoute struct {
+	method string
+	path   string
+	// Whether vaults can run the op inside its transactional /api/batch endpoint
+	atomic bool
+}
+
+// Creates return new slugs that later ops can't reference yet, so vaults only runs updates,
+// moves and deletes of existing items in a single transaction.
+var batchOpRoutes = map[string]batchOpRoute{
+	utils.CreateVault:  {fiber.MethodPost, "/api/vaults", false},
+	utils.CreateEntry:  {fiber.MethodPost, "/api/entries", false},
+	utils.CreateSecret: {fiber.MethodPost, "/api/secrets", false},
+	utils.UpdateVault:  {fiber.MethodPatch, "/api/vaults/", true},
+	utils.UpdateEntry:  {fiber.MethodPatch, "/api/entries/", true},
+	utils.UpdateSecret: {fiber.MethodPatch, "/api/secrets/", true},
+	utils.MoveSecret:   {fiber.MethodPatch, "/api/secrets/", true},
+	utils.DeleteVault:  {fiber.MethodDelete, "/api/vaults/", true},
+	utils.DeleteEntry:  {fiber.MethodDelete, "/api/entries/", true},
+	utils.DeleteSecret: {fiber.MethodDelete, "/api/secrets/", true},
+}
+
+type batchOperation struct {
+	Op      string          `json:"op"`
+	Slug    string          `json:"slug,omitempty"`
+	IfMatch string          `json:"if_match,omitempty"`
+	Body    json.RawMessage `json:"body,omitempty"`
+}
+
+type BatchRequestBody struct {
+	Atomic     bool             `json:"atomic"`
+	Operations []batchOperation `json:"operations"`
+}
+
+type BatchResult struct {
+	Index  int    `json:"index"`
+	Op     string `json:"op"`
+	Status int    `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+type BatchResponseBody struct {
+	Atomic  bool          `json:"atomic"`
+	Results []BatchResult `json:"results"`
+}
+
+// VaultsBatch runs an ordered list of vault ops behind a single AuthorizeRequest password check.
+// An atomic batch is all-or-nothing, and every op in it must be one vaults can run
+// transactionally. Otherwise ops run one by one and each gets its own result.
+func (H Handler) VaultsBatch(c *fiber.Ctx) error {
+	// Batched creates and updates carry secret strings
+	c.Locals(redactRequestBodyKey{}, true)
+
+	if header := c.Get("Client-Operation"); header != utils.Batch {
+		H.logger(c, utils.Batch, header, "", "warn", utils.ErrorClientOperation, "")
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	var session *models.ClientSession
+	var ok bool
+
+	if session, ok = c.UserContext().Value(sessionContextKey{}).(*models.ClientSession); !ok {
+		H.logger(c, utils.Batch, "", "", "error", "Failed session.User context", "")
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	reqBody := BatchRequestBody{}
+
+	if err := c.BodyParser(&reqBody); err != nil {
+		H.logger(c, utils.Batch, err.Error(), "", "error", utils.ErrorParse, session.UserSlug)
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	// Batched creates belong to the organization or shared vault owner, as single ones do
+	ownerSlug, _ := c.UserContext().Value(vaultOwnerContextKey{}).(string)
+	atomic, fieldErrors := validateBatch(&reqBody, ownerSlug)
+
+	if fieldErrors != nil {
+		H.logger(
+			c, utils.Batch, strconv.Itoa(len(reqBody.Operations)), "", "warn", utils.ErrorBatch,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, fieldErrors, nil)
+	}
+
+	headers := map[string]string{
+		"User-Slug": vaultsUserSlug(c),
+		H.Conf.PASSWORD_HEADER_KEY: c.Get(H.Conf.PASSWORD_HEADER_KEY)[:64],
+		trashRetentionHeader: strconv.Itoa(H.Conf.TRASH_RETENTION_DAYS),
+	}
+
+	respBody := BatchResponseBody{
+		Atomic:  atomic,
+		Results: make([]BatchResult, 0, len(reqBody.Operations)),
+	}
+
+	if atomic {
+		if statusCode, errString := H.vaultsJSON(
+			c, fiber.MethodPost, "/api/batch", utils.Batch, headers, &reqBody, nil,
+		); errString != "" {
+			H.logger(c, utils.Batch, errString, "", "error", utils.ErrorVaultsBatch, session.UserSlug)
+
+			return respondVaultsJSONError(c, statusCode)
+		}
+
+		for i, op := range reqBody.Operations {
+			respBody.Results = append(respBody.Results, BatchResult{Index: i, Op: op.Op, Status: 204})
+		}
+
+		return c.Status(200).JSON(&respBody)
+	}
+
+	failed := 0
+
+	for i, op := range reqBody.Operations {
+		route := batchOpRoutes[op.Op]
+		result := BatchResult{Index: i, Op: op.Op, Status: 204}
+		opHeaders := headers
+		var body interface{}
+
+		if op.IfMatch != "" {
+			opHeaders = map[string]string{fiber.HeaderIfMatch: op.IfMatch}
+
+			for key, value := range headers {
+				opHeaders[key] = value
+			}
+		}
+
+		if len(op.Body) > 0 {
+			body = op.Body
+		}
+
+		if statusCode, errString := H.vaultsJSON(
+			c, route.method, route.path + op.Slug, op.Op, opHeaders, body, nil,
+		); errString != "" {
+			H.logger(
+				c, utils.Batch, errString, strconv.Itoa(i) + ":" + op.Op, "error", utils.ErrorVaultsBatch,
+				session.UserSlug,
+			)
+
+			failed++
+			result.Status, result.Error = vaultsJSONError(statusCode)
+		}
+
+		respBody.Results = append(respBody.Results, result)
+	}
+
+	if failed > 0 {
+		return c.Status(207).JSON(&respBody)
+	}
+
+	return c.Status(200).JSON(&respBody)
+}
+
+// validateBatch checks every op and replaces its body with the typed request body of the op's
+// single endpoint, so vaults only gets the fields that endpoint would forward. ownerSlug, if
+// set, overrides the user_slug of creates.
+func validateBatch(reqBody *BatchRequestBody, ownerSlug string) (
+	atomic bool, fieldErrors map[string][]string,
+) {
+	fieldErrors = map[string][]string{}
+
+	if n := len(reqBody.Operations); n < 1 || n > batchMaxOperations {
+		fieldErrors["operations"] = append(
+			fieldErrors["operations"],
+			"Must contain 1 to " + strconv.Itoa(batchMaxOperations) + " operations",
+		)
+
+		return false, fieldErrors
+	}
+
+	transactional := true
+
+	for i, op := range reqBody.Operations {
+		prefix := "operations[" + strconv.Itoa(i) + "]."
+		route, ok := batchOpRoutes[op.Op]
+
+		if !ok {
+			fieldErrors[prefix + "op"] = append(fieldErrors[prefix + "op"], "Unsupported operation")
+
+			continue
+		}
+
+		transactional = transactional && route.atomic
+
+		if route.path[len(route.path) - 1] == '/' {
+			if !utils.SlugRegexp.Match([]byte(op.Slug)) {
+				fieldErrors[prefix + "slug"] = append(fieldErrors[prefix + "slug"], "Invalid slug")
+			}
+
+			// Same optimistic concurrency as the single-item update and delete endpoints
+			if !utils.EntityTagsRegexp.Match([]byte(op.IfMatch)) {
+				fieldErrors[prefix + "if_match"] = append(
+					fieldErrors[prefix + "if_match"], "Must be the item's current ETag",
+				)
+			}
+		} else if op.Slug != "" {
+			fieldErrors[prefix + "slug"] = append(fieldErrors[prefix + "slug"], "Must be empty")
+		}
+
+		var body interface{}
+
+		switch op.Op {
+		case utils.CreateVault:
+			vault := CreateVaultRequestBody{}
+			unmarshalBatchBody(fieldErrors, prefix, op.Body, &vault)
+
+			if ownerSlug != "" {
+				vault.UserSlug = ownerSlug
+			}
+
+			body = &vault
+		case utils.CreateEntry:
+			entry := CreateEntryRequestBody{}
+			unmarshalBatchBody(fieldErrors, prefix, op.Body, &entry)
+
+			if ownerSlug != "" {
+				entry.UserSlug = ownerSlug
+			}
+
+			validateURLMatch(fieldErrors, prefix + "body.", entry.URLMatch)
+			validateBatchEntry(fieldErrors, prefix, entry.EntryType, entry.Secrets)
+			body = &entry
+		case utils.CreateSecret:
+			secret := CreateSecretRequestBody{}
+			unmarshalBatchBody(fieldErrors, prefix, op.Body, &secret)
+
+			if ownerSlug != "" {
+				secret.UserSlug = ownerSlug
+			}
+
+			validateSecretKind(fieldErrors, prefix + "body.", secret.SecretKind, secret.SecretString)
+			body = &secret
+		case utils.UpdateVault:
+			vault := UpdateVaultRequestBody{}
+			unmarshalBatchBody(fieldErrors, prefix, op.Body, &vault)
+			body = &vault
+		case utils.UpdateEntry:
+			entry := UpdateEntryRequestBody{}
+			unmarshalBatchBody(fieldErrors, prefix, op.Body, &entry)
+			validateURLMatch(fieldErrors, prefix + "body.", entry.URLMatch)
+			validateBatchEntry(fieldErrors, prefix, entry.EntryType, entry.Secrets)
+			body = &entry
+		case utils.UpdateSecret:
+			secret := UpdateSecretRequestBody{}
+			unmarshalBatchBody(fieldErrors, prefix, op.Body, &secret)
+			validateSecretKind(fieldErrors, prefix + "body.", secret.Kind, secret.String)
+			body = &secret
+		case utils.MoveSecret:
+			move := MoveSecretRequestBody{}
+			unmarshalBatchBody(fieldErrors, prefix, op.Body, &move)
+			body = &move
+		}
+
+		// Deletes send no body, like the single delete endpoints
+		reqBody.Operations[i].Body = nil
+
+		if body != nil {
+			if encoded, err := json.Marshal(body); err == nil {
+				reqBody.Operations[i].Body = encoded
+			}
+		}
+	}
+
+	if reqBody.Atomic && !transactional && len(fieldErrors) == 0 {
+		fieldErrors["atomic"] = append(
+			fieldErrors["atomic"], "Create operations can't run in an atomic batch",
+		)
+	}
+
+	if len(fieldErrors) > 0 {
+		return false, fieldErrors
+	}
+
+	return reqBody.Atomic, nil
+}
+
+func unmarshalBatchBody(
+	fieldErrors map[string][]string, prefix string, raw json.RawMessage, body interface{},
+) {
+	if err := json.Unmarshal(raw, body); err != nil {
+		fieldErrors[prefix + "body"] = append(fieldErrors[prefix + "body"], "Invalid body")
+	}
+}
+
+// Typed entries get their schema-defined secret kinds written into secrets, which the op body
+// is then built from, the same as a single create_entry or update_entry would forward them
+func validateBatchEntry(
+	fieldErrors map[string][]string, prefix, entryType string, secrets []reqBodySecret,
+) {
+	validateEntryType(fieldErrors, prefix + "body.", entryType, secrets)
+
+	for j, secret := range secrets {
+		validateSecretKind(
+			fieldErrors, prefix + "body.secrets[" + strconv.Itoa(j) + "].", secret.Kind, secret.String,
+		)
+	}
+}
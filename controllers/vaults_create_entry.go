@@ -1,6 +1,9 @@
 package controllers
 
 import (
+	"strconv"
+	"strings"
+
 	"github.com/gofiber/fiber/v2"
 
 	"github.com/liobrdev/simplepasswords_api_gateway/utils"
@@ -10,12 +13,15 @@ type reqBodySecret struct {
 	Label  	 string	`json:"secret_label"`
 	String 	 string	`json:"secret_string"`
 	Priority uint8 	`json:"secret_priority"`
+	Kind		 string `json:"secret_kind,omitempty"`
 }
 
 type CreateEntryRequestBody struct {
 	UserSlug   string          `json:"user_slug"`
 	VaultSlug  string          `json:"vault_slug"`
 	EntryTitle string          `json:"entry_title"`
+	EntryType  string          `json:"entry_type,omitempty"`
+	URLMatch   string          `json:"url_match,omitempty"`
 	Secrets    []reqBodySecret `json:"secrets"`
 }
 
@@ -34,12 +40,65 @@ func (H Handler) VaultsCreateEntry(c *fiber.Ctx) error {
 		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
 	}
 
-	agent := fiber.Post("http://" + H.Conf.VAULTS_HOST + ":" + H.Conf.VAULTS_PORT + "/api/entries")
-	agent.Set("Authorization", "Token " + H.Conf.VAULTS_ACCESS_TOKEN)
+	// A member adding to a shared vault adds the entry for the vault's owner
+	if ownerSlug, ok := c.UserContext().Value(vaultOwnerContextKey{}).(string); ok {
+		reqBody.UserSlug = ownerSlug
+	}
+
+	fieldErrors := map[string][]string{}
+
+	if !validateURLMatch(fieldErrors, "", reqBody.URLMatch) {
+		H.logger(
+			c, utils.CreateEntry, reqBody.URLMatch, "", "warn", utils.ErrorURLMatch, reqBody.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, fieldErrors, nil)
+	}
+
+	if !validateEntryType(fieldErrors, "", reqBody.EntryType, reqBody.Secrets) {
+		H.logger(
+			c, utils.CreateEntry, reqBody.EntryType, "", "warn", utils.ErrorEntryType,
+			reqBody.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, fieldErrors, nil)
+	}
+
+	for i, secret := range reqBody.Secrets {
+		validateSecretKind(fieldErrors, "secrets[" + strconv.Itoa(i) + "].", secret.Kind, secret.String)
+	}
+
+	if len(fieldErrors) > 0 {
+		H.logger(c, utils.CreateEntry, "", "", "warn", utils.ErrorSecretKind, reqBody.UserSlug)
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, fieldErrors, nil)
+	}
+
+	upstream, err := H.Vaults.Acquire()
+
+	if err != nil {
+		H.logger(
+			c, utils.CreateEntry, err.Error(), "", "error", utils.ErrorVaultsUpstream, reqBody.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 503, utils.ErrorServer, nil, nil)
+	}
+
+	defer upstream.Release()
+
+	agent := fiber.Post(upstream.URL + "/api/entries")
 	agent.Set("Client-Operation", utils.CreateEntry)
 	agent.Set(H.Conf.PASSWORD_HEADER_KEY, c.Get(H.Conf.PASSWORD_HEADER_KEY)[:64])
 	agent.JSON(&reqBody)
 
+	if err = H.setVaultsToken(c, agent, utils.CreateEntry); err != nil {
+		H.logger(
+			c, utils.CreateEntry, err.Error(), "", "error", utils.ErrorVaultsToken, reqBody.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
 	_, _, errString := checkVaultsResponse(agent)
 
 	if errString != "" {
@@ -52,3 +111,79 @@ func (H Handler) VaultsCreateEntry(c *fiber.Ctx) error {
 
 	return c.SendStatus(204)
 }
+
+// Checks secrets against the schema of entryType, if any, adding to fieldErrors. Secrets filling
+// a schema field with a fixed kind (a login's totp) are given that kind, so validateSecretKind
+// should run afterwards. Returns false if there were errors.
+func validateEntryType(
+	fieldErrors map[string][]string, prefix, entryType string, secrets []reqBodySecret,
+) bool {
+	if entryType == "" {
+		return true
+	}
+
+	if _, ok := utils.EntryTypes[entryType]; !ok {
+		fieldErrors[prefix + "entry_type"] = append(
+			fieldErrors[prefix + "entry_type"],
+			"Must be one of " + strings.Join(utils.EntryTypeNames, ", "),
+		)
+
+		return false
+	}
+
+	values := make([]utils.EntryFieldValue, len(secrets))
+
+	for i, secret := range secrets {
+		values[i] = utils.EntryFieldValue{Label: secret.Label, Value: secret.String}
+
+		if field, ok := utils.EntryTypeField(entryType, secret.Label); ok && field.Kind != "" {
+			secrets[i].Kind = field.Kind
+		}
+	}
+
+	errs, missing := utils.ValidateEntryFields(entryType, values)
+
+	for i, message := range errs {
+		key := prefix + "secrets[" + strconv.Itoa(i) + "].secret_string"
+		fieldErrors[key] = append(fieldErrors[key], message)
+	}
+
+	for _, name := range missing {
+		fieldErrors[prefix + "secrets"] = append(
+			fieldErrors[prefix + "secrets"], "Missing required " + name + " field",
+		)
+	}
+
+	return len(errs) == 0 && len(missing) == 0
+}
+
+// TOTP seeds are checked here so a typo'd seed fails at save time, not at the next login.
+func validateSecretKind(fieldErrors map[string][]string, prefix, kind, secretString string) {
+	switch kind {
+	case "", utils.SecretKindText:
+	case utils.SecretKindTOTP:
+		if _, err := utils.ParseTOTP(secretString); err != nil {
+			fieldErrors[prefix + "secret_string"] = append(
+				fieldErrors[prefix + "secret_string"], "Must be an otpauth://totp/ URI or base32 seed",
+			)
+		}
+	default:
+		fieldErrors[prefix + "secret_kind"] = append(
+			fieldErrors[prefix + "secret_kind"], "Must be one of text, totp",
+		)
+	}
+}
+
+// An entry's url_match rule, if given, must be one of the modes utils.MatchDocuments knows.
+// Returns false if it isn't.
+func validateURLMatch(fieldErrors map[string][]string, prefix, urlMatch string) bool {
+	if urlMatch == "" || utils.ValidURLMatch(urlMatch) {
+		return true
+	}
+
+	fieldErrors[prefix + "url_match"] = append(
+		fieldErrors[prefix + "url_match"], "Must be one of " + strings.Join(utils.URLMatchModes, ", "),
+	)
+
+	return false
+}
@@ -13,6 +13,7 @@ type CreateSecretRequestBody struct {
 	SecretLabel		 string `json:"secret_label"`
 	SecretString	 string `json:"secret_string"`
 	SecretPriority uint8	`json:"secret_priority"`
+	SecretKind		 string `json:"secret_kind,omitempty"`
 }
 
 func (H Handler) VaultsCreateSecret(c *fiber.Ctx) error {
@@ -30,12 +31,45 @@ func (H Handler) VaultsCreateSecret(c *fiber.Ctx) error {
 		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
 	}
 
-	agent := fiber.Post("http://" + H.Conf.VAULTS_HOST + ":" + H.Conf.VAULTS_PORT + "/api/secrets")
-	agent.Set("Authorization", "Token " + H.Conf.VAULTS_ACCESS_TOKEN)
+	// A secret added for an organization belongs to it
+	if ownerSlug, ok := c.UserContext().Value(vaultOwnerContextKey{}).(string); ok {
+		reqBody.UserSlug = ownerSlug
+	}
+
+	fieldErrors := map[string][]string{}
+	validateSecretKind(fieldErrors, "", reqBody.SecretKind, reqBody.SecretString)
+
+	if len(fieldErrors) > 0 {
+		H.logger(c, utils.CreateSecret, "", "", "warn", utils.ErrorSecretKind, reqBody.UserSlug)
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, fieldErrors, nil)
+	}
+
+	upstream, err := H.Vaults.Acquire()
+
+	if err != nil {
+		H.logger(
+			c, utils.CreateSecret, err.Error(), "", "error", utils.ErrorVaultsUpstream, reqBody.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 503, utils.ErrorServer, nil, nil)
+	}
+
+	defer upstream.Release()
+
+	agent := fiber.Post(upstream.URL + "/api/secrets")
 	agent.Set("Client-Operation", utils.CreateSecret)
 	agent.Set(H.Conf.PASSWORD_HEADER_KEY, c.Get(H.Conf.PASSWORD_HEADER_KEY)[:64])
 	agent.JSON(&reqBody)
 
+	if err = H.setVaultsToken(c, agent, utils.CreateSecret); err != nil {
+		H.logger(
+			c, utils.CreateSecret, err.Error(), "", "error", utils.ErrorVaultsToken, reqBody.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
 	_, _, errString := checkVaultsResponse(agent)
 
 	if errString != "" {
@@ -26,11 +26,35 @@ func (H Handler) VaultsCreateVault(c *fiber.Ctx) error {
 		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
 	}
 
-	agent := fiber.Post("http://" + H.Conf.VAULTS_HOST + ":" + H.Conf.VAULTS_PORT + "/api/vaults")
-	agent.Set("Authorization", "Token " + H.Conf.VAULTS_ACCESS_TOKEN)
+	// A vault created for an organization belongs to it
+	if ownerSlug, ok := c.UserContext().Value(vaultOwnerContextKey{}).(string); ok {
+		reqBody.UserSlug = ownerSlug
+	}
+
+	upstream, err := H.Vaults.Acquire()
+
+	if err != nil {
+		H.logger(
+			c, utils.CreateVault, err.Error(), "", "error", utils.ErrorVaultsUpstream, reqBody.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 503, utils.ErrorServer, nil, nil)
+	}
+
+	defer upstream.Release()
+
+	agent := fiber.Post(upstream.URL + "/api/vaults")
 	agent.Set("Client-Operation", utils.CreateVault)
 	agent.JSON(&reqBody)
 
+	if err = H.setVaultsToken(c, agent, utils.CreateVault); err != nil {
+		H.logger(
+			c, utils.CreateVault, err.Error(), "", "error", utils.ErrorVaultsToken, reqBody.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
 	_, _, errString := checkVaultsResponse(agent)
 
 	if errString != "" {
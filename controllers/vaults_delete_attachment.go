@@ -0,0 +1,51 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/models"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+func (H Handler) VaultsDeleteAttachment(c *fiber.Ctx) error {
+	if header := c.Get("Client-Operation"); header != utils.DeleteAttachment {
+		H.logger(c, utils.DeleteAttachment, header, "", "warn", utils.ErrorClientOperation, "")
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	var session *models.ClientSession
+	var ok bool
+
+	if session, ok = c.UserContext().Value(sessionContextKey{}).(*models.ClientSession); !ok {
+		H.logger(c, utils.DeleteAttachment, "", "", "error", "Failed session.User context", "")
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	slug := c.Params("slug")
+	attachmentSlug := c.Params("attachment_slug")
+
+	if !utils.SlugRegexp.Match([]byte(slug)) || !utils.SlugRegexp.Match([]byte(attachmentSlug)) {
+		H.logger(
+			c, utils.DeleteAttachment, slug, attachmentSlug, "warn", utils.ErrorParams,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	if statusCode, errString := H.vaultsJSON(
+		c, fiber.MethodDelete, "/api/entries/" + slug + "/attachments/" + attachmentSlug,
+		utils.DeleteAttachment, map[string]string{"User-Slug": vaultsUserSlug(c)}, nil, nil,
+	); errString != "" {
+		H.logger(
+			c, utils.DeleteAttachment, errString, attachmentSlug, "error",
+			utils.ErrorVaultsDeleteAttachment, session.UserSlug,
+		)
+
+		return respondVaultsJSONError(c, statusCode)
+	}
+
+	return c.SendStatus(204)
+}
@@ -1,6 +1,8 @@
 package controllers
 
 import (
+	"strconv"
+
 	"github.com/gofiber/fiber/v2"
 
 	"github.com/liobrdev/simplepasswords_api_gateway/utils"
@@ -14,16 +16,47 @@ func (H Handler) VaultsDeleteEntry(c *fiber.Ctx) error {
 	}
 
 	slug := c.Params("slug")
+	ifMatch, ok := ifMatchHeader(c)
+
+	if !ok {
+		H.logger(c, utils.DeleteEntry, ifMatch, slug, "warn", utils.ErrorIfMatch, "")
+
+		return respondPreconditionRequired(c)
+	}
+
+	upstream, err := H.Vaults.Acquire()
+
+	if err != nil {
+		H.logger(c, utils.DeleteEntry, err.Error(), "", "error", utils.ErrorVaultsUpstream, "")
+
+		return utils.RespondWithError(c, 503, utils.ErrorServer, nil, nil)
+	}
 
-	agent := fiber.Delete(
-		"http://" + H.Conf.VAULTS_HOST + ":" + H.Conf.VAULTS_PORT + "/api/entries/" + slug,
-	)
+	defer upstream.Release()
 
-	agent.Set("Authorization", "Token " + H.Conf.VAULTS_ACCESS_TOKEN)
+	agent := fiber.Delete(upstream.URL + "/api/entries/" + slug)
 	agent.Set("Client-Operation", utils.DeleteEntry)
+	agent.Set(fiber.HeaderIfMatch, ifMatch)
 	agent.Set("Content-Type", "application/json")
+	agent.Set(trashRetentionHeader, strconv.Itoa(H.Conf.TRASH_RETENTION_DAYS))
 
-	_, _, errString := checkVaultsResponse(agent)
+	if err = H.setVaultsToken(c, agent, utils.DeleteEntry); err != nil {
+		H.logger(c, utils.DeleteEntry, err.Error(), "", "error", utils.ErrorVaultsToken, "")
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	resp := fiber.AcquireResponse()
+	defer fiber.ReleaseResponse(resp)
+	agent.SetResponse(resp)
+
+	statusCode, _, errString := checkVaultsResponse(agent)
+
+	if statusCode == 412 {
+		H.logger(c, utils.DeleteEntry, ifMatch, slug, "warn", utils.ErrorStaleIfMatch, "")
+
+		return respondPreconditionFailed(c, resp)
+	}
 
 	if errString != "" {
 		H.logger(c, utils.DeleteEntry, errString, "", "error", utils.ErrorVaultsDeleteEntry, "")
@@ -0,0 +1,77 @@
+package controllers
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/models"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+// Streams the decrypted file straight through. It's always served as a download with sniffing
+// off, so a file can never be rendered in the app's origin.
+func (H Handler) VaultsDownloadAttachment(c *fiber.Ctx) error {
+	if header := c.Get("Client-Operation"); header != utils.DownloadAttachment {
+		H.logger(c, utils.DownloadAttachment, header, "", "warn", utils.ErrorClientOperation, "")
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	var session *models.ClientSession
+	var ok bool
+
+	if session, ok = c.UserContext().Value(sessionContextKey{}).(*models.ClientSession); !ok {
+		H.logger(c, utils.DownloadAttachment, "", "", "error", "Failed session.User context", "")
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	slug := c.Params("slug")
+	attachmentSlug := c.Params("attachment_slug")
+
+	if !utils.SlugRegexp.Match([]byte(slug)) || !utils.SlugRegexp.Match([]byte(attachmentSlug)) {
+		H.logger(
+			c, utils.DownloadAttachment, slug, attachmentSlug, "warn", utils.ErrorParams,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	upstream, err := H.Vaults.Acquire()
+
+	if err != nil {
+		H.logger(
+			c, utils.DownloadAttachment, err.Error(), "", "error", utils.ErrorVaultsUpstream,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 503, utils.ErrorServer, nil, nil)
+	}
+
+	if errString := H.streamVaultsResponse(
+		c, upstream, fiber.MethodGet, "/api/entries/" + slug + "/attachments/" + attachmentSlug,
+		utils.DownloadAttachment, map[string]string{
+			"User-Slug": vaultsUserSlug(c),
+			H.Conf.PASSWORD_HEADER_KEY: c.Get(H.Conf.PASSWORD_HEADER_KEY)[:64],
+		},
+	); errString != "" {
+		H.logger(
+			c, utils.DownloadAttachment, errString, attachmentSlug, "error",
+			utils.ErrorVaultsDownloadAttachment, session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	if disposition := c.GetRespHeader(fiber.HeaderContentDisposition);
+	!strings.HasPrefix(disposition, "attachment") {
+		c.Set(fiber.HeaderContentDisposition, "attachment")
+	}
+
+	c.Set(fiber.HeaderXContentTypeOptions, "nosniff")
+	c.Set(fiber.HeaderCacheControl, "no-store")
+
+	return nil
+}
@@ -0,0 +1,40 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/models"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+func (H Handler) VaultsEmptyTrash(c *fiber.Ctx) error {
+	if header := c.Get("Client-Operation"); header != utils.EmptyTrash {
+		H.logger(c, utils.EmptyTrash, header, "", "warn", utils.ErrorClientOperation, "")
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	var session *models.ClientSession
+	var ok bool
+
+	if session, ok = c.UserContext().Value(sessionContextKey{}).(*models.ClientSession); !ok {
+		H.logger(c, utils.EmptyTrash, "", "", "error", "Failed session.User context", "")
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	if statusCode, errString := H.vaultsJSON(
+		c, fiber.MethodDelete, "/api/trash", utils.EmptyTrash, map[string]string{
+			"User-Slug": vaultsUserSlug(c),
+			H.Conf.PASSWORD_HEADER_KEY: c.Get(H.Conf.PASSWORD_HEADER_KEY)[:64],
+		}, nil, nil,
+	); errString != "" {
+		H.logger(
+			c, utils.EmptyTrash, errString, "", "error", utils.ErrorVaultsEmptyTrash, session.UserSlug,
+		)
+
+		return respondVaultsJSONError(c, statusCode)
+	}
+
+	return c.SendStatus(204)
+}
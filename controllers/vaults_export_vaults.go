@@ -0,0 +1,276 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"net/url"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"github.com/goccy/go-json"
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/models"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+const (
+	ExportFormatEncryptedJSON string = "encrypted_json"
+	ExportFormatCSV           string = "csv"
+)
+
+const exportMinPassphrase int = 12
+
+type ExportVaultsRequestBody struct {
+	Format           string `json:"format"`
+	Passphrase       string `json:"passphrase"`
+	ConfirmPlaintext bool   `json:"confirm_plaintext"`
+}
+
+type exportVault struct {
+	VaultSlug  string        `json:"vault_slug"`
+	VaultTitle string        `json:"vault_title"`
+	Entries    []exportEntry `json:"entries"`
+}
+
+type exportEntry struct {
+	EntrySlug  string          `json:"entry_slug"`
+	EntryTitle string          `json:"entry_title"`
+	EntryType  string          `json:"entry_type,omitempty"`
+	UpdatedAt  *time.Time      `json:"updated_at,omitempty"`
+	Secrets    []exportSecret  `json:"secrets"`
+}
+
+type exportSecret struct {
+	reqBodySecret
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+type exportVaultsPage struct {
+	Vaults     []exportVault `json:"vaults"`
+	NextCursor string        `json:"next_cursor"`
+}
+
+type exportArchive struct {
+	ExportedAt time.Time     `json:"exported_at"`
+	Vaults     []exportVault `json:"vaults"`
+}
+
+func (H Handler) VaultsExportVaults(c *fiber.Ctx) error {
+	// The body holds the passphrase the export is encrypted with
+	c.Locals(redactRequestBodyKey{}, true)
+
+	if header := c.Get("Client-Operation"); header != utils.ExportVaults {
+		H.logger(c, utils.ExportVaults, header, "", "warn", utils.ErrorClientOperation, "")
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	var session *models.ClientSession
+	var ok bool
+
+	if session, ok = c.UserContext().Value(sessionContextKey{}).(*models.ClientSession); !ok {
+		H.logger(c, utils.ExportVaults, "", "", "error", "Failed session.User context", "")
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	reqBody := ExportVaultsRequestBody{}
+
+	if err := c.BodyParser(&reqBody); err != nil {
+		H.logger(c, utils.ExportVaults, err.Error(), "", "error", utils.ErrorParse, session.UserSlug)
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	fieldErrors := map[string][]string{}
+
+	switch reqBody.Format {
+	case ExportFormatEncryptedJSON:
+		if utf8.RuneCountInString(reqBody.Passphrase) < exportMinPassphrase {
+			fieldErrors["passphrase"] = append(
+				fieldErrors["passphrase"],
+				"Must be at least " + strconv.Itoa(exportMinPassphrase) + " characters",
+			)
+		}
+	case ExportFormatCSV:
+		if !reqBody.ConfirmPlaintext {
+			fieldErrors["confirm_plaintext"] = append(
+				fieldErrors["confirm_plaintext"], "Must confirm an unencrypted export",
+			)
+		}
+	default:
+		fieldErrors["format"] = append(fieldErrors["format"], "Must be one of encrypted_json, csv")
+	}
+
+	if len(fieldErrors) > 0 {
+		H.logger(
+			c, utils.ExportVaults, reqBody.Format, "", "warn", utils.ErrorExportVaults,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, fieldErrors, nil)
+	}
+
+	vaults, statusCode, errString := H.vaultsExportData(c, vaultsUserSlug(c))
+
+	if errString != "" {
+		// Audit trail - a failed export is recorded like a rejected one
+		H.logger(
+			c, utils.ExportVaults, errString, "", "warn", utils.ErrorVaultsExportVaults,
+			session.UserSlug,
+		)
+
+		return respondVaultsJSONError(c, statusCode)
+	}
+
+	now := time.Now().UTC()
+	var export []byte
+	var err error
+
+	if reqBody.Format == ExportFormatCSV {
+		export, err = exportCSV(vaults)
+	} else {
+		export, err = exportEncryptedJSON(vaults, now, []byte(reqBody.Passphrase))
+	}
+
+	if err != nil {
+		H.logger(
+			c, utils.ExportVaults, err.Error(), reqBody.Format, "error", utils.ErrorExportVaults,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	// Audit trail - every export is recorded, whatever the format
+	H.logger(
+		c, utils.ExportVaults, reqBody.Format, strconv.Itoa(len(vaults)), "info",
+		utils.InfoExportVaults, session.UserSlug,
+	)
+
+	if H.Conf.ENVIRONMENT != "testing" {
+		if err = H.sendExportNoticeEmail(&session.User, reqBody.Format, now); err != nil {
+			H.logger(
+				c, utils.ExportVaults, err.Error(), "", "error", "Failed send export notice email",
+				session.UserSlug,
+			)
+		}
+	}
+
+	filename := "simplepasswords-export-" + now.Format("20060102-150405")
+
+	if reqBody.Format == ExportFormatCSV {
+		c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+		c.Attachment(filename + ".csv")
+	} else {
+		c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		c.Attachment(filename + ".json")
+	}
+
+	c.Set(fiber.HeaderCacheControl, "no-store")
+
+	return c.Status(200).Send(export)
+}
+
+// Walks every vault, entry and secret the user owns. Also feeds the vault health report.
+func (H Handler) vaultsExportData(c *fiber.Ctx, userSlug string) (
+	vaults []exportVault, statusCode int, errString string,
+) {
+	userHeader := map[string]string{"User-Slug": userSlug}
+	params := url.Values{"limit": {strconv.Itoa(listVaultsMaxLimit)}}
+
+	for {
+		page := exportVaultsPage{}
+
+		if statusCode, errString = H.vaultsJSON(
+			c, fiber.MethodGet, "/api/vaults?" + params.Encode(), utils.ListVaults, userHeader, nil,
+			&page,
+		); errString != "" {
+			return nil, statusCode, errString
+		}
+
+		vaults = append(vaults, page.Vaults...)
+
+		if page.NextCursor == "" {
+			break
+		}
+
+		params.Set("cursor", page.NextCursor)
+	}
+
+	passwordHeader := map[string]string{
+		"User-Slug": userSlug,
+		H.Conf.PASSWORD_HEADER_KEY: c.Get(H.Conf.PASSWORD_HEADER_KEY)[:64],
+	}
+
+	for i := range vaults {
+		if statusCode, errString = H.vaultsJSON(
+			c, fiber.MethodGet, "/api/vaults/" + vaults[i].VaultSlug, utils.RetrieveVault, userHeader,
+			nil, &vaults[i],
+		); errString != "" {
+			return nil, statusCode, errString
+		}
+
+		for j := range vaults[i].Entries {
+			if statusCode, errString = H.vaultsJSON(
+				c, fiber.MethodGet, "/api/entries/" + vaults[i].Entries[j].EntrySlug,
+				utils.RetrieveEntry, passwordHeader, nil, &vaults[i].Entries[j],
+			); errString != "" {
+				return nil, statusCode, errString
+			}
+		}
+	}
+
+	return vaults, 200, ""
+}
+
+func exportEncryptedJSON(vaults []exportVault, now time.Time, passphrase []byte) ([]byte, error) {
+	plaintext, err := json.Marshal(&exportArchive{ExportedAt: now, Vaults: vaults})
+
+	if err != nil {
+		return nil, err
+	}
+
+	archive, err := utils.EncryptWithPassphrase(plaintext, passphrase)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(archive)
+}
+
+func exportCSV(vaults []exportVault) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	w.Write([]string{"vault_title", "entry_title", "secret_label", "secret_string", "secret_priority"})
+
+	for _, vault := range vaults {
+		for _, entry := range vault.Entries {
+			for _, secret := range entry.Secrets {
+				w.Write([]string{
+					vault.VaultTitle, entry.EntryTitle, secret.Label, secret.String,
+					strconv.Itoa(int(secret.Priority)),
+				})
+			}
+		}
+	}
+
+	w.Flush()
+
+	return buf.Bytes(), w.Error()
+}
+
+func (H Handler) sendExportNoticeEmail(user *models.User, format string, at time.Time) error {
+	return H.sendEmail(
+		"Your vaults were exported", H.Conf.SUPPORT_EMAIL, []string{user.EmailAddress},
+		"email_export_notice.html", map[string]string{
+			"Name": user.Name,
+			"Format": format,
+			"Time": at.Format(time.RFC1123),
+		},
+	)
+}
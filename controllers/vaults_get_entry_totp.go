@@ -0,0 +1,100 @@
+package controllers
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/models"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+type GetEntryTOTPResponseBody struct {
+	SecretLabel      string `json:"secret_label"`
+	Code             string `json:"code"`
+	SecondsRemaining int    `json:"seconds_remaining"`
+	Period           int    `json:"period"`
+}
+
+// The seed never leaves the gateway, only the current code. With several TOTP secrets on one
+// entry, ?secret_label= picks one, otherwise the highest priority secret wins.
+func (H Handler) VaultsGetEntryTOTP(c *fiber.Ctx) error {
+	if header := c.Get("Client-Operation"); header != utils.GetEntryTOTP {
+		H.logger(c, utils.GetEntryTOTP, header, "", "warn", utils.ErrorClientOperation, "")
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	var session *models.ClientSession
+	var ok bool
+
+	if session, ok = c.UserContext().Value(sessionContextKey{}).(*models.ClientSession); !ok {
+		H.logger(c, utils.GetEntryTOTP, "", "", "error", "Failed session.User context", "")
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	slug := c.Params("slug")
+
+	if !utils.SlugRegexp.Match([]byte(slug)) {
+		H.logger(c, utils.GetEntryTOTP, slug, "", "warn", utils.ErrorParams, session.UserSlug)
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	entry := exportEntry{}
+
+	if statusCode, errString := H.vaultsJSON(
+		c, fiber.MethodGet, "/api/entries/" + slug, utils.RetrieveEntry, map[string]string{
+			"User-Slug": vaultsUserSlug(c),
+			H.Conf.PASSWORD_HEADER_KEY: c.Get(H.Conf.PASSWORD_HEADER_KEY)[:64],
+		}, nil, &entry,
+	); errString != "" {
+		H.logger(
+			c, utils.GetEntryTOTP, errString, slug, "error", utils.ErrorVaultsEntryTOTP,
+			session.UserSlug,
+		)
+
+		return respondVaultsJSONError(c, statusCode)
+	}
+
+	label := c.Query("secret_label")
+	var secret *reqBodySecret
+
+	for i := range entry.Secrets {
+		if entry.Secrets[i].Kind != utils.SecretKindTOTP ||
+		(label != "" && entry.Secrets[i].Label != label) {
+			continue
+		}
+
+		if secret == nil || entry.Secrets[i].Priority < secret.Priority {
+			secret = &entry.Secrets[i].reqBodySecret
+		}
+	}
+
+	if secret == nil {
+		H.logger(c, utils.GetEntryTOTP, slug, label, "warn", utils.ErrorEntryTOTP, session.UserSlug)
+
+		return utils.RespondWithError(c, 404, utils.ErrorNoTOTPSecret, nil, nil)
+	}
+
+	params, err := utils.ParseTOTP(secret.String)
+
+	if err != nil {
+		H.logger(
+			c, utils.GetEntryTOTP, err.Error(), slug, "error", utils.ErrorEntryTOTP, session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	code, secondsRemaining := params.Code(time.Now())
+	c.Set(fiber.HeaderCacheControl, "no-store")
+
+	return c.Status(200).JSON(&GetEntryTOTPResponseBody{
+		SecretLabel:      secret.Label,
+		Code:             code,
+		SecondsRemaining: secondsRemaining,
+		Period:           params.Period,
+	})
+}
@@ -0,0 +1,85 @@
+package controllers
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/models"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+func (H Handler) VaultsHealthReport(c *fiber.Ctx) error {
+	if header := c.Get("Client-Operation"); header != utils.VaultHealthReport {
+		H.logger(c, utils.VaultHealthReport, header, "", "warn", utils.ErrorClientOperation, "")
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	var session *models.ClientSession
+	var ok bool
+
+	if session, ok = c.UserContext().Value(sessionContextKey{}).(*models.ClientSession); !ok {
+		H.logger(c, utils.VaultHealthReport, "", "", "error", "Failed session.User context", "")
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	vaults, statusCode, errString := H.vaultsExportData(c, vaultsUserSlug(c))
+
+	if errString != "" {
+		H.logger(
+			c, utils.VaultHealthReport, errString, "", "error", utils.ErrorVaultsHealthReport,
+			session.UserSlug,
+		)
+
+		return respondVaultsJSONError(c, statusCode)
+	}
+
+	secrets, totalEntries := healthSecrets(vaults)
+	report, err := utils.BuildHealthReport(
+		secrets, totalEntries, time.Now().UTC(), utils.HealthStaleAfter,
+	)
+
+	if err != nil {
+		H.logger(
+			c, utils.VaultHealthReport, err.Error(), "", "error", utils.ErrorHealthReport,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	c.Set(fiber.HeaderCacheControl, "no-store")
+
+	return c.Status(200).JSON(report)
+}
+
+func healthSecrets(vaults []exportVault) (secrets []utils.HealthSecret, totalEntries int) {
+	for _, vault := range vaults {
+		for _, entry := range vault.Entries {
+			totalEntries++
+
+			for _, secret := range entry.Secrets {
+				if secret.String == "" || !utils.IsPasswordLabel(secret.Label) {
+					continue
+				}
+
+				healthSecret := utils.HealthSecret{
+					EntrySlug: entry.EntrySlug,
+					Label:     secret.Label,
+					String:    secret.String,
+				}
+
+				// Not the entry's, which changes whenever any of its secrets or its title does
+				if secret.UpdatedAt != nil {
+					healthSecret.UpdatedAt = *secret.UpdatedAt
+				}
+
+				secrets = append(secrets, healthSecret)
+			}
+		}
+	}
+
+	return
+}
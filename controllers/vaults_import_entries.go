@@ -0,0 +1,348 @@
+package controllers
+
+import (
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/importers"
+	"github.com/liobrdev/simplepasswords_api_gateway/models"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+const importMaxFileBytes int64 = 4 << 20
+const importBatchSize int = 10
+const importMaxSecrets int = 255
+
+type ImportEntriesPreview struct {
+	Row          int      `json:"row"`
+	EntryTitle   string   `json:"entry_title"`
+	SecretLabels []string `json:"secret_labels"`
+}
+
+type ImportEntriesDuplicate struct {
+	Row        int    `json:"row"`
+	EntryTitle string `json:"entry_title"`
+	Reason     string `json:"reason"`
+}
+
+type ImportEntriesResponseBody struct {
+	DryRun     bool                     `json:"dry_run"`
+	TotalRows  int                      `json:"total_rows"`
+	Created    int                      `json:"created"`
+	Entries    []ImportEntriesPreview   `json:"entries"`
+	Duplicates []ImportEntriesDuplicate `json:"duplicates"`
+	Errors     []importers.RowError     `json:"errors"`
+}
+
+type importRow struct {
+	row     int
+	reqBody CreateEntryRequestBody
+}
+
+func (H Handler) VaultsImportEntries(c *fiber.Ctx) error {
+	// The uploaded file holds the passwords being imported in plain text
+	c.Locals(redactRequestBodyKey{}, true)
+
+	if header := c.Get("Client-Operation"); header != utils.ImportEntries {
+		H.logger(c, utils.ImportEntries, header, "", "warn", utils.ErrorClientOperation, "")
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	var session *models.ClientSession
+	var ok bool
+
+	if session, ok = c.UserContext().Value(sessionContextKey{}).(*models.ClientSession); !ok {
+		H.logger(c, utils.ImportEntries, "", "", "error", "Failed session.User context", "")
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	format := c.FormValue("format")
+	vaultSlug := c.FormValue("vault_slug")
+	dryRun := c.FormValue("dry_run") == "true"
+	fieldErrors := map[string][]string{}
+
+	validFormat := false
+
+	for _, f := range importers.Formats {
+		if format == f {
+			validFormat = true
+			break
+		}
+	}
+
+	if !validFormat {
+		fieldErrors["format"] = append(
+			fieldErrors["format"], "Must be one of " + strings.Join(importers.Formats, ", "),
+		)
+	}
+
+	if !utils.SlugRegexp.Match([]byte(vaultSlug)) {
+		fieldErrors["vault_slug"] = append(fieldErrors["vault_slug"], "Invalid vault slug.")
+	}
+
+	fileHeader, err := c.FormFile("file")
+
+	if err != nil {
+		fieldErrors["file"] = append(fieldErrors["file"], "Missing file.")
+	} else if fileHeader.Size > importMaxFileBytes {
+		fieldErrors["file"] = append(fieldErrors["file"], "File too large.")
+	}
+
+	if len(fieldErrors) > 0 {
+		H.logger(
+			c, utils.ImportEntries, format, vaultSlug, "warn", utils.ErrorImportEntries,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, fieldErrors, nil)
+	}
+
+	var data []byte
+
+	if file, err := fileHeader.Open(); err != nil {
+		H.logger(c, utils.ImportEntries, err.Error(), "", "error", utils.ErrorParse, session.UserSlug)
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	} else {
+		data, err = io.ReadAll(io.LimitReader(file, importMaxFileBytes))
+		file.Close()
+
+		if err != nil {
+			H.logger(
+				c, utils.ImportEntries, err.Error(), "", "error", utils.ErrorParse, session.UserSlug,
+			)
+
+			return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+		}
+	}
+
+	entries, rowErrors, err := importers.Parse(format, data)
+
+	if err != nil {
+		H.logger(
+			c, utils.ImportEntries, err.Error(), format, "warn", utils.ErrorImportEntries,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(
+			c, 400, utils.ErrorBadRequest, map[string][]string{"file": {"Failed to parse file."}}, nil,
+		)
+	}
+
+	vaultSlugs, statusCode, errString := H.vaultsUserVaultSlugs(c, vaultsUserSlug(c))
+
+	if errString != "" {
+		H.logger(
+			c, utils.ImportEntries, errString, "", "error", utils.ErrorVaultsListVaults,
+			session.UserSlug,
+		)
+
+		return respondVaultsJSONError(c, statusCode)
+	}
+
+	if !vaultSlugs[vaultSlug] {
+		H.logger(
+			c, utils.ImportEntries, format, vaultSlug, "warn", utils.ErrorImportEntries,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, map[string][]string{
+			"vault_slug": {"Invalid vault slug."},
+		}, nil)
+	}
+
+	docs, statusCode, errString := H.vaultsSearchDocuments(c, vaultsUserSlug(c))
+
+	if errString != "" {
+		H.logger(
+			c, utils.ImportEntries, errString, "", "error", utils.ErrorVaultsSearchEntries,
+			session.UserSlug,
+		)
+
+		return respondVaultsJSONError(c, statusCode)
+	}
+
+	respBody := ImportEntriesResponseBody{
+		DryRun:     dryRun,
+		TotalRows:  len(entries) + len(rowErrors),
+		Entries:    []ImportEntriesPreview{},
+		Duplicates: []ImportEntriesDuplicate{},
+		Errors:     rowErrors,
+	}
+
+	if respBody.Errors == nil {
+		respBody.Errors = []importers.RowError{}
+	}
+
+	existing := make(map[string]bool, len(docs))
+
+	for _, doc := range docs {
+		existing[importDuplicateKey(doc.EntryTitle, doc.EntryURL)] = true
+	}
+
+	seen := map[string]bool{}
+	var rows []importRow
+
+	for _, entry := range entries {
+		key := importDuplicateKey(entry.Title, entry.URL)
+
+		if existing[key] {
+			respBody.Duplicates = append(respBody.Duplicates, ImportEntriesDuplicate{
+				Row: entry.Row, EntryTitle: entry.Title, Reason: "existing_entry",
+			})
+		} else if seen[key] {
+			respBody.Duplicates = append(respBody.Duplicates, ImportEntriesDuplicate{
+				Row: entry.Row, EntryTitle: entry.Title, Reason: "duplicate_in_file",
+			})
+		} else if secrets := importSecrets(&entry); len(secrets) > importMaxSecrets {
+			respBody.Errors = append(respBody.Errors, importers.RowError{
+				Row: entry.Row, Error: "Too many fields.",
+			})
+		} else {
+			seen[key] = true
+			rows = append(rows, importRow{row: entry.Row, reqBody: CreateEntryRequestBody{
+				UserSlug:   vaultsUserSlug(c),
+				VaultSlug:  vaultSlug,
+				EntryTitle: entry.Title,
+				Secrets:    secrets,
+			}})
+		}
+	}
+
+	if dryRun {
+		for _, r := range rows {
+			respBody.Entries = append(respBody.Entries, importPreview(&r))
+		}
+
+		return c.Status(200).JSON(&respBody)
+	}
+
+	requestID := c.GetRespHeader(fiber.HeaderXRequestID)
+	password := c.Get(H.Conf.PASSWORD_HEADER_KEY)[:64]
+	results := make([]string, len(rows))
+
+	for start := 0; start < len(rows); start += importBatchSize {
+		end := min(start + importBatchSize, len(rows))
+
+		var wg sync.WaitGroup
+
+		for i := start; i < end; i++ {
+			wg.Add(1)
+
+			go func(i int) {
+				defer wg.Done()
+				results[i] = H.vaultsImportEntry(&rows[i].reqBody, password, requestID)
+			}(i)
+		}
+
+		wg.Wait()
+	}
+
+	var failed []string
+
+	for i, result := range results {
+		if result == "" {
+			respBody.Created++
+			respBody.Entries = append(respBody.Entries, importPreview(&rows[i]))
+		} else {
+			respBody.Errors = append(respBody.Errors, importers.RowError{
+				Row: rows[i].row, Error: "Failed to create entry.",
+			})
+
+			failed = append(failed, strconv.Itoa(rows[i].row) + ": " + result)
+		}
+	}
+
+	if len(failed) > 0 {
+		H.logger(
+			c, utils.ImportEntries, strings.Join(failed, "\n"), "", "error",
+			utils.ErrorVaultsCreateEntry, session.UserSlug,
+		)
+	}
+
+	return c.Status(200).JSON(&respBody)
+}
+
+// Runs outside the request goroutine, so it must not touch the fiber.Ctx.
+func (H Handler) vaultsImportEntry(reqBody *CreateEntryRequestBody, password, requestID string) (
+	errString string,
+) {
+	token, err := newVaultsToken(H.Conf, utils.CreateEntry, reqBody.UserSlug, requestID)
+
+	if err != nil {
+		return err.Error() + ";;"
+	}
+
+	upstream, err := H.Vaults.Acquire()
+
+	if err != nil {
+		return err.Error() + ";;"
+	}
+
+	defer upstream.Release()
+
+	agent := fiber.Post(upstream.URL + "/api/entries")
+	agent.Set("Authorization", "Bearer " + token)
+	agent.Set("Client-Operation", utils.CreateEntry)
+	agent.Set(fiber.HeaderXRequestID, requestID)
+	agent.Set(H.Conf.PASSWORD_HEADER_KEY, password)
+	agent.JSON(reqBody)
+
+	_, _, errString = checkVaultsResponse(agent)
+
+	return errString
+}
+
+func importSecrets(entry *importers.Entry) (secrets []reqBodySecret) {
+	add := func(label, value string) {
+		if value != "" {
+			secrets = append(secrets, reqBodySecret{
+				Label:    label,
+				String:   value,
+				Priority: uint8(len(secrets)),
+			})
+		}
+	}
+
+	add("Username", entry.Username)
+	add("Password", entry.Password)
+	add("URL", entry.URL)
+	add("Notes", entry.Notes)
+
+	for _, field := range entry.Fields {
+		if len(secrets) > importMaxSecrets {
+			break
+		}
+
+		add(field.Label, field.Value)
+
+		// Bitwarden exports carry the 2FA seed, which is worth keeping as a usable TOTP secret
+		if strings.EqualFold(field.Label, "TOTP") {
+			if _, err := utils.ParseTOTP(field.Value); err == nil {
+				secrets[len(secrets) - 1].Kind = utils.SecretKindTOTP
+			}
+		}
+	}
+
+	return
+}
+
+func importPreview(r *importRow) ImportEntriesPreview {
+	preview := ImportEntriesPreview{Row: r.row, EntryTitle: r.reqBody.EntryTitle}
+
+	for _, secret := range r.reqBody.Secrets {
+		preview.SecretLabels = append(preview.SecretLabels, secret.Label)
+	}
+
+	return preview
+}
+
+func importDuplicateKey(title, url string) string {
+	return strings.ToLower(strings.TrimSpace(title)) + "|" + importers.Host(url)
+}
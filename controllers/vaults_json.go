@@ -0,0 +1,92 @@
+package controllers
+
+import (
+	"strconv"
+
+	"github.com/goccy/go-json"
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+// Sends a request to vaults on behalf of the session user and decodes the JSON response into
+// respBody (if not nil). On failure returns vaults' own status code (or 500/503 if it didn't
+// answer) for vaultsJSONError, and an error string to log.
+func (H Handler) vaultsJSON(
+	c *fiber.Ctx, method, path, clientOperation string, headers map[string]string,
+	reqBody, respBody interface{},
+) (statusCode int, errString string) {
+	upstream, err := H.Vaults.Acquire()
+
+	if err != nil {
+		return 503, err.Error() + ";;"
+	}
+
+	defer upstream.Release()
+
+	agent := fiber.AcquireAgent()
+	agent.Request().Header.SetMethod(method)
+	agent.Request().SetRequestURI(upstream.URL + path)
+
+	if err = agent.Parse(); err != nil {
+		fiber.ReleaseAgent(agent)
+
+		return 500, err.Error() + ";;"
+	}
+
+	agent.Set("Content-Type", "application/json")
+	agent.Set("Client-Operation", clientOperation)
+
+	for key, value := range headers {
+		agent.Set(key, value)
+	}
+
+	if reqBody != nil {
+		agent.JSON(reqBody)
+	}
+
+	if err = H.setVaultsToken(c, agent, clientOperation); err != nil {
+		fiber.ReleaseAgent(agent)
+
+		return 500, err.Error() + ";;"
+	}
+
+	statusCode, body, errString := checkVaultsResponse(agent)
+
+	if statusCode < 200 || statusCode > 299 {
+		return statusCode, strconv.Itoa(statusCode) + ";;" + errString
+	} else if errString != "" {
+		return 500, errString
+	}
+
+	if respBody != nil {
+		if err = json.Unmarshal([]byte(body), respBody); err != nil {
+			return 500, err.Error() + ";;"
+		}
+	}
+
+	return statusCode, ""
+}
+
+// Responds to a failed vaultsJSON request, with the status and detail of vaultsJSONError
+func respondVaultsJSONError(c *fiber.Ctx, statusCode int) error {
+	status, detail := vaultsJSONError(statusCode)
+
+	return utils.RespondWithError(c, status, detail, nil, nil)
+}
+
+// vaultsJSONError is what a client is told of a failed vaultsJSON request. A missing item or
+// stale If-Match reaches the client as vaults reported it; anything else is the gateway's own
+// error.
+func vaultsJSONError(statusCode int) (int, string) {
+	switch statusCode {
+	case 404:
+		return 404, utils.ErrorNotFound
+	case 412:
+		return 412, utils.ErrorPreconditionFailed
+	case 503:
+		return 503, utils.ErrorServer
+	default:
+		return 500, utils.ErrorServer
+	}
+}
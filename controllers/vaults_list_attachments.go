@@ -0,0 +1,59 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/models"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+// Lists attachment metadata only, so unlike downloading it isn't privileged
+func (H Handler) VaultsListAttachments(c *fiber.Ctx) error {
+	if header := c.Get("Client-Operation"); header != utils.ListAttachments {
+		H.logger(c, utils.ListAttachments, header, "", "warn", utils.ErrorClientOperation, "")
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	var session *models.ClientSession
+	var ok bool
+
+	if session, ok = c.UserContext().Value(sessionContextKey{}).(*models.ClientSession); !ok {
+		H.logger(c, utils.ListAttachments, "", "", "error", "Failed session.User context", "")
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	slug := c.Params("slug")
+
+	if !utils.SlugRegexp.Match([]byte(slug)) {
+		H.logger(c, utils.ListAttachments, slug, "", "warn", utils.ErrorParams, session.UserSlug)
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	upstream, err := H.Vaults.Acquire()
+
+	if err != nil {
+		H.logger(
+			c, utils.ListAttachments, err.Error(), "", "error", utils.ErrorVaultsUpstream,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 503, utils.ErrorServer, nil, nil)
+	}
+
+	if errString := H.streamVaultsResponse(
+		c, upstream, fiber.MethodGet, "/api/entries/" + slug + "/attachments",
+		utils.ListAttachments, map[string]string{"User-Slug": vaultsUserSlug(c)},
+	); errString != "" {
+		H.logger(
+			c, utils.ListAttachments, errString, slug, "error", utils.ErrorVaultsListAttachments,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	return nil
+}
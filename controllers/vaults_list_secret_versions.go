@@ -0,0 +1,97 @@
+package controllers
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/models"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+type secretVersion struct {
+	Version   int       `json:"version"`
+	Label     string    `json:"secret_label"`
+	String    string    `json:"secret_string"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type ListSecretVersionsResponseBody struct {
+	SecretSlug string          `json:"secret_slug"`
+	Versions   []secretVersion `json:"versions"`
+}
+
+func (H Handler) VaultsListSecretVersions(c *fiber.Ctx) error {
+	if header := c.Get("Client-Operation"); header != utils.ListSecretVersions {
+		H.logger(c, utils.ListSecretVersions, header, "", "warn", utils.ErrorClientOperation, "")
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	var session *models.ClientSession
+	var ok bool
+
+	if session, ok = c.UserContext().Value(sessionContextKey{}).(*models.ClientSession); !ok {
+		H.logger(c, utils.ListSecretVersions, "", "", "error", "Failed session.User context", "")
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	slug := c.Params("slug")
+
+	if !utils.SlugRegexp.Match([]byte(slug)) {
+		H.logger(c, utils.ListSecretVersions, slug, "", "warn", utils.ErrorParams, session.UserSlug)
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	versions, statusCode, errString := H.vaultsSecretVersions(c, vaultsUserSlug(c), slug)
+
+	if errString != "" {
+		H.logger(
+			c, utils.ListSecretVersions, errString, slug, "error", utils.ErrorVaultsListSecretVersions,
+			session.UserSlug,
+		)
+
+		return respondVaultsJSONError(c, statusCode)
+	}
+
+	c.Set(fiber.HeaderCacheControl, "no-store")
+
+	return c.Status(200).JSON(&ListSecretVersionsResponseBody{SecretSlug: slug, Versions: versions})
+}
+
+// Fetches a secret's history, newest first, trimmed to SECRET_VERSIONS_RETAIN. The limit is sent
+// along so vaults can prune, but it is applied here too so older versions are never exposed.
+func (H Handler) vaultsSecretVersions(c *fiber.Ctx, userSlug, secretSlug string) (
+	versions []secretVersion, statusCode int, errString string,
+) {
+	respBody := ListSecretVersionsResponseBody{}
+	retain := H.Conf.SECRET_VERSIONS_RETAIN
+
+	if statusCode, errString = H.vaultsJSON(
+		c, fiber.MethodGet, "/api/secrets/" + secretSlug + "/versions?retain=" + strconv.Itoa(retain),
+		utils.ListSecretVersions, map[string]string{
+			"User-Slug": userSlug,
+			H.Conf.PASSWORD_HEADER_KEY: c.Get(H.Conf.PASSWORD_HEADER_KEY)[:64],
+		}, nil, &respBody,
+	); errString != "" {
+		return nil, statusCode, errString
+	}
+
+	versions = respBody.Versions
+
+	sort.Slice(versions, func(i, j int) bool { return versions[i].Version > versions[j].Version })
+
+	if len(versions) > retain {
+		versions = versions[:retain]
+	}
+
+	if versions == nil {
+		versions = []secretVersion{}
+	}
+
+	return versions, 200, ""
+}
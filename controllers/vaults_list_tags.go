@@ -0,0 +1,48 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/models"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+func (H Handler) VaultsListTags(c *fiber.Ctx) error {
+	if header := c.Get("Client-Operation"); header != utils.ListTags {
+		H.logger(c, utils.ListTags, header, "", "warn", utils.ErrorClientOperation, "")
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	var session *models.ClientSession
+	var ok bool
+
+	if session, ok = c.UserContext().Value(sessionContextKey{}).(*models.ClientSession); !ok {
+		H.logger(c, utils.ListTags, "", "", "error", "Failed session.User context", "")
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	upstream, err := H.Vaults.Acquire()
+
+	if err != nil {
+		H.logger(
+			c, utils.ListTags, err.Error(), "", "error", utils.ErrorVaultsUpstream, session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 503, utils.ErrorServer, nil, nil)
+	}
+
+	if errString := H.streamVaultsResponse(
+		c, upstream, fiber.MethodGet, "/api/tags", utils.ListTags,
+		map[string]string{"User-Slug": vaultsUserSlug(c)},
+	); errString != "" {
+		H.logger(
+			c, utils.ListTags, errString, "", "error", utils.ErrorVaultsListTags, session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	return nil
+}
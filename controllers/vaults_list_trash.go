@@ -0,0 +1,78 @@
+package controllers
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/models"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+// Deletes are soft - vaults moves the item to the trash and purges it once this many days pass
+const trashRetentionHeader string = "Trash-Retention-Days"
+
+const (
+	TrashItemVault  string = "vault"
+	TrashItemEntry  string = "entry"
+	TrashItemSecret string = "secret"
+)
+
+type trashItem struct {
+	ItemType  string    `json:"item_type"`
+	ItemSlug  string    `json:"item_slug"`
+	Title     string    `json:"title"`
+	DeletedAt time.Time `json:"deleted_at"`
+	PurgeAt   time.Time `json:"purge_at"`
+}
+
+type ListTrashResponseBody struct {
+	RetentionDays int         `json:"retention_days"`
+	Items         []trashItem `json:"items"`
+}
+
+func (H Handler) VaultsListTrash(c *fiber.Ctx) error {
+	if header := c.Get("Client-Operation"); header != utils.ListTrash {
+		H.logger(c, utils.ListTrash, header, "", "warn", utils.ErrorClientOperation, "")
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	var session *models.ClientSession
+	var ok bool
+
+	if session, ok = c.UserContext().Value(sessionContextKey{}).(*models.ClientSession); !ok {
+		H.logger(c, utils.ListTrash, "", "", "error", "Failed session.User context", "")
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	respBody := ListTrashResponseBody{}
+
+	if statusCode, errString := H.vaultsJSON(
+		c, fiber.MethodGet, "/api/trash", utils.ListTrash,
+		map[string]string{"User-Slug": vaultsUserSlug(c)}, nil, &respBody,
+	); errString != "" {
+		H.logger(
+			c, utils.ListTrash, errString, "", "error", utils.ErrorVaultsListTrash, session.UserSlug,
+		)
+
+		return respondVaultsJSONError(c, statusCode)
+	}
+
+	respBody.RetentionDays = H.Conf.TRASH_RETENTION_DAYS
+
+	if respBody.Items == nil {
+		respBody.Items = []trashItem{}
+	}
+
+	for i := range respBody.Items {
+		if respBody.Items[i].PurgeAt.IsZero() {
+			respBody.Items[i].PurgeAt = respBody.Items[i].DeletedAt.AddDate(
+				0, 0, H.Conf.TRASH_RETENTION_DAYS,
+			)
+		}
+	}
+
+	return c.Status(200).JSON(&respBody)
+}
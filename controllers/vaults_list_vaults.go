@@ -1,12 +1,33 @@
 package controllers
 
 import (
+	"net/url"
+	"strconv"
+	"unicode/utf8"
+
 	"github.com/gofiber/fiber/v2"
 
 	"github.com/liobrdev/simplepasswords_api_gateway/models"
 	"github.com/liobrdev/simplepasswords_api_gateway/utils"
 )
 
+const listVaultsDefaultLimit int = 50
+const listVaultsMaxLimit int = 100
+const listVaultsMaxTitleFilter int = 255
+
+var listVaultsSortFields = []string{
+	"title", "-title", "created", "-created", "updated", "-updated",
+}
+
+type ListVaultsQuery struct {
+	Limit    string `query:"limit"`
+	Cursor   string `query:"cursor"`
+	Sort     string `query:"sort"`
+	Title    string `query:"title"`
+	Tag      string `query:"tag"`
+	Favorite string `query:"favorite"`
+}
+
 func (H Handler) VaultsListVaults(c *fiber.Ctx) error {
 	if header := c.Get("Client-Operation"); header != utils.ListVaults {
 		H.logger(c, utils.ListVaults, header, "", "warn", utils.ErrorClientOperation, "")
@@ -23,15 +44,39 @@ func (H Handler) VaultsListVaults(c *fiber.Ctx) error {
 		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
 	}
 
-	agent := fiber.Get("http://" + H.Conf.VAULTS_HOST + ":" + H.Conf.VAULTS_PORT + "/api/vaults")
-	agent.Set("Content-Type", "application/json")
-	agent.Set("Client-Operation", utils.ListVaults)
-	agent.Set("Authorization", "Token " + H.Conf.VAULTS_ACCESS_TOKEN)
-	agent.Set("User-Slug", session.UserSlug)
+	query := ListVaultsQuery{}
 
-	_, body, errString := checkVaultsResponse(agent)
+	if err := c.QueryParser(&query); err != nil {
+		H.logger(c, utils.ListVaults, err.Error(), "", "warn", utils.ErrorParams, session.UserSlug)
 
-	if errString != "" {
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	params, fieldErrors := validateListVaultsQuery(&query)
+
+	if fieldErrors != nil {
+		H.logger(
+			c, utils.ListVaults, string(c.Request().URI().QueryString()), "", "warn", utils.ErrorParams,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, fieldErrors, nil)
+	}
+
+	upstream, err := H.Vaults.Acquire()
+
+	if err != nil {
+		H.logger(
+			c, utils.ListVaults, err.Error(), "", "error", utils.ErrorVaultsUpstream, session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 503, utils.ErrorServer, nil, nil)
+	}
+
+	if errString := H.streamVaultsResponse(
+		c, upstream, fiber.MethodGet, "/api/vaults?" + params.Encode(), utils.ListVaults,
+		map[string]string{"User-Slug": vaultsUserSlug(c)},
+	); errString != "" {
 		H.logger(
 			c, utils.ListVaults, errString, "", "error", utils.ErrorVaultsListVaults, session.UserSlug,
 		)
@@ -39,5 +84,100 @@ func (H Handler) VaultsListVaults(c *fiber.Ctx) error {
 		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
 	}
 
-	return c.Status(200).Send([]byte(body))
+	return nil
+}
+
+func validateListVaultsQuery(query *ListVaultsQuery) (url.Values, map[string][]string) {
+	params := url.Values{}
+	fieldErrors := map[string][]string{}
+
+	limit := listVaultsDefaultLimit
+
+	if query.Limit != "" {
+		if n, err := strconv.Atoi(query.Limit); err != nil || n < 1 || n > listVaultsMaxLimit {
+			fieldErrors["limit"] = append(
+				fieldErrors["limit"], "Must be an integer from 1 to " + strconv.Itoa(listVaultsMaxLimit),
+			)
+		} else {
+			limit = n
+		}
+	}
+
+	params.Set("limit", strconv.Itoa(limit))
+
+	if query.Cursor != "" {
+		if !utils.CursorRegexp.Match([]byte(query.Cursor)) {
+			fieldErrors["cursor"] = append(fieldErrors["cursor"], "Invalid cursor.")
+		} else {
+			params.Set("cursor", query.Cursor)
+		}
+	}
+
+	if query.Sort != "" {
+		valid := false
+
+		for _, field := range listVaultsSortFields {
+			if query.Sort == field {
+				valid = true
+				break
+			}
+		}
+
+		if !valid {
+			fieldErrors["sort"] = append(
+				fieldErrors["sort"], "Must be one of title, created, updated, optionally prefixed by '-'",
+			)
+		} else {
+			params.Set("sort", query.Sort)
+		}
+	}
+
+	if query.Title != "" {
+		if utf8.RuneCountInString(query.Title) > listVaultsMaxTitleFilter ||
+		utils.ControlCharRegexp.Match([]byte(query.Title)) {
+			fieldErrors["title"] = append(fieldErrors["title"], "Invalid title filter.")
+		} else {
+			params.Set("title", query.Title)
+		}
+	}
+
+	tag, favorite := validateTagFilters(fieldErrors, query.Tag, query.Favorite)
+
+	if tag != "" {
+		params.Set("tag", tag)
+	}
+
+	if favorite {
+		params.Set("favorite", "true")
+	}
+
+	if len(fieldErrors) > 0 {
+		return nil, fieldErrors
+	}
+
+	return params, nil
+}
+
+// Validates the tag and favorite filters shared by list endpoints, adding to fieldErrors
+func validateTagFilters(
+	fieldErrors map[string][]string, tagQuery, favoriteQuery string,
+) (tag string, favorite bool) {
+	if tagQuery != "" {
+		var ok bool
+
+		if tag, ok = utils.NormalizeTag(tagQuery); !ok {
+			fieldErrors["tag"] = append(fieldErrors["tag"], "Invalid tag filter.")
+			tag = ""
+		}
+	}
+
+	if favoriteQuery != "" {
+		if favoriteQuery != "true" && favoriteQuery != "false" {
+			fieldErrors["favorite"] = append(fieldErrors["favorite"], "Must be true or false")
+		} else {
+			favorite = favoriteQuery == "true"
+		}
+	}
+
+	return
 }
@@ -0,0 +1,57 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/models"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+type MatchURLResponseBody struct {
+	Matches []utils.URLMatchResult `json:"matches"`
+}
+
+// VaultsMatchURL tells an autofill client which entries belong to a site. Only slugs and titles
+// are returned; the client retrieves the chosen entry separately.
+func (H Handler) VaultsMatchURL(c *fiber.Ctx) error {
+	if header := c.Get("Client-Operation"); header != utils.MatchURL {
+		H.logger(c, utils.MatchURL, header, "", "warn", utils.ErrorClientOperation, "")
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	var session *models.ClientSession
+	var ok bool
+
+	if session, ok = c.UserContext().Value(sessionContextKey{}).(*models.ClientSession); !ok {
+		H.logger(c, utils.MatchURL, "", "", "error", "Failed session.User context", "")
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	rawURL := c.Query("url")
+	target, ok := utils.NormalizeURL(rawURL)
+
+	if !ok || len(rawURL) > utils.URLMatchMaxLength {
+		H.logger(
+			c, utils.MatchURL, string(c.Request().URI().QueryString()), "", "warn", utils.ErrorParams,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, map[string][]string{
+			"url": {"Must be an http or https URL"},
+		}, nil)
+	}
+
+	docs, statusCode, errString := H.vaultsSearchDocuments(c, vaultsUserSlug(c))
+
+	if errString != "" {
+		H.logger(
+			c, utils.MatchURL, errString, "", "error", utils.ErrorVaultsMatchURL, session.UserSlug,
+		)
+
+		return respondVaultsJSONError(c, statusCode)
+	}
+
+	return c.Status(200).JSON(&MatchURLResponseBody{Matches: utils.MatchDocuments(docs, target)})
+}
@@ -0,0 +1,158 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/models"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+type MoveEntryRequestBody struct {
+	VaultSlug string `json:"vault_slug"`
+}
+
+func (H Handler) VaultsMoveEntry(c *fiber.Ctx) error {
+	if clientOperation := c.Get("Client-Operation"); clientOperation != utils.MoveEntry {
+		H.logger(c, utils.MoveEntry, clientOperation, "", "warn", utils.ErrorClientOperation, "")
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	reqBody := MoveEntryRequestBody{}
+
+	if err := c.BodyParser(&reqBody); err != nil {
+		H.logger(c, utils.MoveEntry, err.Error(), "", "error", utils.ErrorParse, "")
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	slug := c.Params("slug")
+	ifMatch, ok := ifMatchHeader(c)
+
+	if !ok {
+		H.logger(c, utils.MoveEntry, ifMatch, slug, "warn", utils.ErrorIfMatch, "")
+
+		return respondPreconditionRequired(c)
+	}
+
+	var session *models.ClientSession
+
+	if session, ok = c.UserContext().Value(sessionContextKey{}).(*models.ClientSession); !ok {
+		H.logger(c, utils.MoveEntry, "", "", "error", "Failed session.User context", "")
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	if !utils.SlugRegexp.Match([]byte(reqBody.VaultSlug)) {
+		H.logger(
+			c, utils.MoveEntry, reqBody.VaultSlug, slug, "warn", utils.ErrorMoveEntry, session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, map[string][]string{
+			"vault_slug": {"Invalid vault slug."},
+		}, nil)
+	}
+
+	// Vaults scopes each request to the token's user, but the move touches two vaults, so both
+	// the entry's current vault and the target are checked against the session user first
+	vaultSlugs, statusCode, errString := H.vaultsUserVaultSlugs(c, vaultsUserSlug(c))
+
+	if errString != "" {
+		H.logger(
+			c, utils.MoveEntry, errString, "", "error", utils.ErrorVaultsListVaults, session.UserSlug,
+		)
+
+		return respondVaultsJSONError(c, statusCode)
+	}
+
+	docs, statusCode, errString := H.vaultsSearchDocuments(c, vaultsUserSlug(c))
+
+	if errString != "" {
+		H.logger(
+			c, utils.MoveEntry, errString, "", "error", utils.ErrorVaultsSearchEntries, session.UserSlug,
+		)
+
+		return respondVaultsJSONError(c, statusCode)
+	}
+
+	sourceVaultSlug := ""
+
+	for _, doc := range docs {
+		if doc.EntrySlug == slug {
+			sourceVaultSlug = doc.VaultSlug
+
+			break
+		}
+	}
+
+	if sourceVaultSlug == "" || !vaultSlugs[sourceVaultSlug] || !vaultSlugs[reqBody.VaultSlug] {
+		H.logger(
+			c, utils.MoveEntry, reqBody.VaultSlug, slug, "warn", utils.ErrorMoveEntry, session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 404, utils.ErrorNotFound, nil, nil)
+	}
+
+	if sourceVaultSlug == reqBody.VaultSlug {
+		H.logger(
+			c, utils.MoveEntry, reqBody.VaultSlug, slug, "warn", utils.ErrorMoveEntry, session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, map[string][]string{
+			"vault_slug": {"Entry is already in this vault."},
+		}, nil)
+	}
+
+	upstream, err := H.Vaults.Acquire()
+
+	if err != nil {
+		H.logger(
+			c, utils.MoveEntry, err.Error(), "", "error", utils.ErrorVaultsUpstream, session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 503, utils.ErrorServer, nil, nil)
+	}
+
+	defer upstream.Release()
+
+	agent := fiber.Patch(upstream.URL + "/api/entries/" + slug)
+	agent.Set("Client-Operation", utils.MoveEntry)
+	agent.Set(fiber.HeaderIfMatch, ifMatch)
+	agent.JSON(&reqBody)
+
+	if err = H.setVaultsToken(c, agent, utils.MoveEntry); err != nil {
+		H.logger(c, utils.MoveEntry, err.Error(), "", "error", utils.ErrorVaultsToken, session.UserSlug)
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	resp := fiber.AcquireResponse()
+	defer fiber.ReleaseResponse(resp)
+	agent.SetResponse(resp)
+
+	statusCode, _, errString = checkVaultsResponse(agent)
+
+	if statusCode == 412 {
+		H.logger(c, utils.MoveEntry, ifMatch, slug, "warn", utils.ErrorStaleIfMatch, session.UserSlug)
+
+		return respondPreconditionFailed(c, resp)
+	}
+
+	if statusCode == 404 {
+		H.logger(
+			c, utils.MoveEntry, reqBody.VaultSlug, slug, "warn", utils.ErrorMoveEntry, session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 404, utils.ErrorNotFound, nil, nil)
+	}
+
+	if errString != "" {
+		H.logger(
+			c, utils.MoveEntry, errString, "", "error", utils.ErrorVaultsMoveEntry, session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	return c.SendStatus(204)
+}
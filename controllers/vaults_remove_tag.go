@@ -0,0 +1,55 @@
+package controllers
+
+import (
+	"net/url"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/models"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+func (H Handler) VaultsRemoveTag(c *fiber.Ctx) error {
+	if header := c.Get("Client-Operation"); header != utils.RemoveTag {
+		H.logger(c, utils.RemoveTag, header, "", "warn", utils.ErrorClientOperation, "")
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	var session *models.ClientSession
+	var ok bool
+
+	if session, ok = c.UserContext().Value(sessionContextKey{}).(*models.ClientSession); !ok {
+		H.logger(c, utils.RemoveTag, "", "", "error", "Failed session.User context", "")
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	slug := c.Params("slug")
+	tag, err := url.PathUnescape(c.Params("tag"))
+
+	if err == nil {
+		tag, ok = utils.NormalizeTag(tag)
+	}
+
+	if err != nil || !ok {
+		H.logger(c, utils.RemoveTag, c.Params("tag"), slug, "warn", utils.ErrorTag, session.UserSlug)
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, map[string][]string{
+			"tag": {tagFieldError},
+		}, nil)
+	}
+
+	if statusCode, errString := H.vaultsJSON(
+		c, fiber.MethodDelete, "/api/entries/" + slug + "/tags/" + url.PathEscape(tag),
+		utils.RemoveTag, nil, nil, nil,
+	); errString != "" {
+		H.logger(
+			c, utils.RemoveTag, errString, "", "error", utils.ErrorVaultsRemoveTag, session.UserSlug,
+		)
+
+		return respondVaultsJSONError(c, statusCode)
+	}
+
+	return c.SendStatus(204)
+}
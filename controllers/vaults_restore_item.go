@@ -0,0 +1,64 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/models"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+func (H Handler) VaultsRestoreItem(c *fiber.Ctx) error {
+	if header := c.Get("Client-Operation"); header != utils.RestoreItem {
+		H.logger(c, utils.RestoreItem, header, "", "warn", utils.ErrorClientOperation, "")
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	var session *models.ClientSession
+	var ok bool
+
+	if session, ok = c.UserContext().Value(sessionContextKey{}).(*models.ClientSession); !ok {
+		H.logger(c, utils.RestoreItem, "", "", "error", "Failed session.User context", "")
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	itemType := c.Params("type")
+	slug := c.Params("slug")
+	fieldErrors := map[string][]string{}
+
+	if itemType != TrashItemVault && itemType != TrashItemEntry && itemType != TrashItemSecret {
+		fieldErrors["item_type"] = append(
+			fieldErrors["item_type"], "Must be one of vault, entry, secret",
+		)
+	}
+
+	if !utils.SlugRegexp.Match([]byte(slug)) {
+		fieldErrors["item_slug"] = append(fieldErrors["item_slug"], "Invalid slug")
+	}
+
+	if len(fieldErrors) > 0 {
+		H.logger(c, utils.RestoreItem, itemType, slug, "warn", utils.ErrorParams, session.UserSlug)
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, fieldErrors, nil)
+	}
+
+	// Unlike updates, a restore needs no If-Match: a trashed item can't be edited, so there is no
+	// newer version for the restore to overwrite.
+	if statusCode, errString := H.vaultsJSON(
+		c, fiber.MethodPost, "/api/trash/" + itemType + "/" + slug + "/restore", utils.RestoreItem,
+		map[string]string{
+			"User-Slug": vaultsUserSlug(c),
+			H.Conf.PASSWORD_HEADER_KEY: c.Get(H.Conf.PASSWORD_HEADER_KEY)[:64],
+		}, nil, nil,
+	); errString != "" {
+		H.logger(
+			c, utils.RestoreItem, errString, itemType + ":" + slug, "error", utils.ErrorVaultsRestoreItem,
+			session.UserSlug,
+		)
+
+		return respondVaultsJSONError(c, statusCode)
+	}
+
+	return c.SendStatus(204)
+}
@@ -0,0 +1,107 @@
+package controllers
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/models"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+func (H Handler) VaultsRestoreSecretVersion(c *fiber.Ctx) error {
+	if header := c.Get("Client-Operation"); header != utils.RestoreSecretVersion {
+		H.logger(c, utils.RestoreSecretVersion, header, "", "warn", utils.ErrorClientOperation, "")
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	var session *models.ClientSession
+	var ok bool
+
+	if session, ok = c.UserContext().Value(sessionContextKey{}).(*models.ClientSession); !ok {
+		H.logger(c, utils.RestoreSecretVersion, "", "", "error", "Failed session.User context", "")
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	slug := c.Params("slug")
+	version, err := strconv.Atoi(c.Params("version"))
+
+	if !utils.SlugRegexp.Match([]byte(slug)) || err != nil || version < 1 {
+		H.logger(
+			c, utils.RestoreSecretVersion, slug, c.Params("version"), "warn", utils.ErrorParams,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, map[string][]string{
+			"version": {"Must be a positive integer"},
+		}, nil)
+	}
+
+	// Restoring overwrites the secret's current value, so it must name that value's version
+	// like any other update.
+	ifMatch, ok := ifMatchHeader(c)
+
+	if !ok {
+		H.logger(
+			c, utils.RestoreSecretVersion, ifMatch, slug, "warn", utils.ErrorIfMatch, session.UserSlug,
+		)
+
+		return respondPreconditionRequired(c)
+	}
+
+	versions, statusCode, errString := H.vaultsSecretVersions(c, vaultsUserSlug(c), slug)
+
+	if errString != "" {
+		H.logger(
+			c, utils.RestoreSecretVersion, errString, slug, "error",
+			utils.ErrorVaultsRestoreSecretVersion, session.UserSlug,
+		)
+
+		return respondVaultsJSONError(c, statusCode)
+	}
+
+	retained := false
+
+	for _, v := range versions {
+		if v.Version == version {
+			retained = true
+			break
+		}
+	}
+
+	if !retained {
+		H.logger(
+			c, utils.RestoreSecretVersion, slug, c.Params("version"), "warn",
+			utils.ErrorRestoreSecretVersion, session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 404, utils.ErrorSecretVersion, nil, nil)
+	}
+
+	if statusCode, errString = H.vaultsJSON(
+		c, fiber.MethodPost, "/api/secrets/" + slug + "/versions/" + strconv.Itoa(version) + "/restore",
+		utils.RestoreSecretVersion, map[string]string{
+			"User-Slug": vaultsUserSlug(c),
+			fiber.HeaderIfMatch: ifMatch,
+			H.Conf.PASSWORD_HEADER_KEY: c.Get(H.Conf.PASSWORD_HEADER_KEY)[:64],
+		}, nil, nil,
+	); statusCode == 412 {
+		H.logger(
+			c, utils.RestoreSecretVersion, ifMatch, slug, "warn", utils.ErrorStaleIfMatch,
+			session.UserSlug,
+		)
+
+		return respondVaultsJSONError(c, statusCode)
+	} else if errString != "" {
+		H.logger(
+			c, utils.RestoreSecretVersion, errString, slug, "error",
+			utils.ErrorVaultsRestoreSecretVersion, session.UserSlug,
+		)
+
+		return respondVaultsJSONError(c, statusCode)
+	}
+
+	return c.SendStatus(204)
+}
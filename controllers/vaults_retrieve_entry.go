@@ -15,16 +15,44 @@ func (H Handler) VaultsRetrieveEntry(c *fiber.Ctx) error {
 
 	slug := c.Params("slug")
 
-	agent := fiber.Get(
-		"http://" + H.Conf.VAULTS_HOST + ":" + H.Conf.VAULTS_PORT + "/api/entries/" + slug,
-	)
+	upstream, err := H.Vaults.Acquire()
 
-	agent.Set("Authorization", "Token " + H.Conf.VAULTS_ACCESS_TOKEN)
+	if err != nil {
+		H.logger(c, utils.RetrieveEntry, err.Error(), "", "error", utils.ErrorVaultsUpstream, "")
+
+		return utils.RespondWithError(c, 503, utils.ErrorServer, nil, nil)
+	}
+
+	defer upstream.Release()
+
+	agent := fiber.Get(upstream.URL + "/api/entries/" + slug)
 	agent.Set("Client-Operation", utils.RetrieveEntry)
 	agent.Set("Content-Type", "application/json")
 	agent.Set(H.Conf.PASSWORD_HEADER_KEY, c.Get(H.Conf.PASSWORD_HEADER_KEY)[:64])
 
-	_, body, errString := checkVaultsResponse(agent)
+	if ifNoneMatch := c.Get(fiber.HeaderIfNoneMatch); ifNoneMatch != "" {
+		agent.Set(fiber.HeaderIfNoneMatch, ifNoneMatch)
+	}
+
+	if err = H.setVaultsToken(c, agent, utils.RetrieveEntry); err != nil {
+		H.logger(c, utils.RetrieveEntry, err.Error(), "", "error", utils.ErrorVaultsToken, "")
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	resp := fiber.AcquireResponse()
+	defer fiber.ReleaseResponse(resp)
+	agent.SetResponse(resp)
+
+	statusCode, body, errString := checkVaultsResponse(agent)
+
+	if etag := resp.Header.Peek(fiber.HeaderETag); len(etag) > 0 {
+		c.Set(fiber.HeaderETag, string(etag))
+	}
+
+	if statusCode == 304 {
+		return c.SendStatus(304)
+	}
 
 	if errString != "" {
 		H.logger(c, utils.RetrieveEntry, errString, "", "error", utils.ErrorVaultsRetrieveEntry, "")
@@ -15,21 +15,21 @@ func (H Handler) VaultsRetrieveVault(c *fiber.Ctx) error {
 
 	slug := c.Params("slug")
 
-	agent := fiber.Get(
-		"http://" + H.Conf.VAULTS_HOST + ":" + H.Conf.VAULTS_PORT + "/api/vaults/" + slug,
-	)
+	upstream, err := H.Vaults.Acquire()
 
-	agent.Set("Authorization", "Token " + H.Conf.VAULTS_ACCESS_TOKEN)
-	agent.Set("Client-Operation", utils.RetrieveVault)
-	agent.Set("Content-Type", "application/json")
+	if err != nil {
+		H.logger(c, utils.RetrieveVault, err.Error(), "", "error", utils.ErrorVaultsUpstream, "")
 
-	_, body, errString := checkVaultsResponse(agent)
+		return utils.RespondWithError(c, 503, utils.ErrorServer, nil, nil)
+	}
 
-	if errString != "" {
+	if errString := H.streamVaultsResponse(
+		c, upstream, fiber.MethodGet, "/api/vaults/" + slug, utils.RetrieveVault, nil,
+	); errString != "" {
 		H.logger(c, utils.RetrieveVault, errString, "", "error", utils.ErrorVaultsRetrieveVault, "")
 
 		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
 	}
 
-	return c.Status(200).Send([]byte(body))
+	return nil
 }
@@ -0,0 +1,20 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+// Fetches searchable metadata (titles, URLs and secret labels, never values) for all of the
+// user's entries. On failure returns the status code to respond with and an error string to log.
+func (H Handler) vaultsSearchDocuments(c *fiber.Ctx, userSlug string) (
+	docs []utils.SearchDocument, statusCode int, errString string,
+) {
+	statusCode, errString = H.vaultsJSON(
+		c, fiber.MethodGet, "/api/search", utils.SearchEntries,
+		map[string]string{"User-Slug": userSlug}, nil, &docs,
+	)
+
+	return
+}
@@ -0,0 +1,120 @@
+package controllers
+
+import (
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/models"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+const searchEntriesDefaultLimit int = 20
+const searchEntriesMaxLimit int = 50
+const searchEntriesMaxQuery int = 100
+
+type SearchEntriesQuery struct {
+	Q        string `query:"q"`
+	Match    string `query:"match"`
+	Limit    string `query:"limit"`
+	Tag      string `query:"tag"`
+	Favorite string `query:"favorite"`
+}
+
+type SearchEntriesResponseBody struct {
+	Results []utils.SearchResult `json:"results"`
+}
+
+func (H Handler) VaultsSearchEntries(c *fiber.Ctx) error {
+	if header := c.Get("Client-Operation"); header != utils.SearchEntries {
+		H.logger(c, utils.SearchEntries, header, "", "warn", utils.ErrorClientOperation, "")
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	var session *models.ClientSession
+	var ok bool
+
+	if session, ok = c.UserContext().Value(sessionContextKey{}).(*models.ClientSession); !ok {
+		H.logger(c, utils.SearchEntries, "", "", "error", "Failed session.User context", "")
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	query := SearchEntriesQuery{}
+
+	if err := c.QueryParser(&query); err != nil {
+		H.logger(c, utils.SearchEntries, err.Error(), "", "warn", utils.ErrorParams, session.UserSlug)
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	limit, tag, favorite, fieldErrors := validateSearchEntriesQuery(&query)
+
+	if fieldErrors != nil {
+		H.logger(
+			c, utils.SearchEntries, string(c.Request().URI().QueryString()), "", "warn",
+			utils.ErrorParams, session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, fieldErrors, nil)
+	}
+
+	docs, statusCode, errString := H.vaultsSearchDocuments(c, vaultsUserSlug(c))
+
+	if errString != "" {
+		H.logger(
+			c, utils.SearchEntries, errString, "", "error", utils.ErrorVaultsSearchEntries,
+			session.UserSlug,
+		)
+
+		return respondVaultsJSONError(c, statusCode)
+	}
+
+	return c.Status(200).JSON(&SearchEntriesResponseBody{
+		Results: utils.SearchDocuments(
+			utils.FilterDocuments(docs, tag, favorite), query.Q, query.Match, limit,
+		),
+	})
+}
+
+func validateSearchEntriesQuery(query *SearchEntriesQuery) (
+	int, string, bool, map[string][]string,
+) {
+	fieldErrors := map[string][]string{}
+
+	if n := utf8.RuneCountInString(query.Q); n < 1 || n > searchEntriesMaxQuery ||
+	utils.ControlCharRegexp.Match([]byte(query.Q)) {
+		fieldErrors["q"] = append(
+			fieldErrors["q"], "Must be 1 to " + strconv.Itoa(searchEntriesMaxQuery) + " characters",
+		)
+	}
+
+	if query.Match == "" {
+		query.Match = utils.SearchMatchFuzzy
+	} else if query.Match != utils.SearchMatchFuzzy && query.Match != utils.SearchMatchPrefix {
+		fieldErrors["match"] = append(fieldErrors["match"], "Must be one of prefix, fuzzy")
+	}
+
+	limit := searchEntriesDefaultLimit
+
+	if query.Limit != "" {
+		if n, err := strconv.Atoi(query.Limit); err != nil || n < 1 || n > searchEntriesMaxLimit {
+			fieldErrors["limit"] = append(
+				fieldErrors["limit"],
+				"Must be an integer from 1 to " + strconv.Itoa(searchEntriesMaxLimit),
+			)
+		} else {
+			limit = n
+		}
+	}
+
+	tag, favorite := validateTagFilters(fieldErrors, query.Tag, query.Favorite)
+
+	if len(fieldErrors) > 0 {
+		return 0, "", false, fieldErrors
+	}
+
+	return limit, tag, favorite, nil
+}
@@ -0,0 +1,61 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/models"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+type SetFavoriteRequestBody struct {
+	Favorite *bool `json:"favorite"`
+}
+
+func (H Handler) VaultsSetFavorite(c *fiber.Ctx) error {
+	if header := c.Get("Client-Operation"); header != utils.SetFavorite {
+		H.logger(c, utils.SetFavorite, header, "", "warn", utils.ErrorClientOperation, "")
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	var session *models.ClientSession
+	var ok bool
+
+	if session, ok = c.UserContext().Value(sessionContextKey{}).(*models.ClientSession); !ok {
+		H.logger(c, utils.SetFavorite, "", "", "error", "Failed session.User context", "")
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	reqBody := SetFavoriteRequestBody{}
+
+	if err := c.BodyParser(&reqBody); err != nil {
+		H.logger(c, utils.SetFavorite, err.Error(), "", "error", utils.ErrorParse, session.UserSlug)
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	slug := c.Params("slug")
+
+	if reqBody.Favorite == nil {
+		H.logger(c, utils.SetFavorite, "", slug, "warn", utils.ErrorSetFavorite, session.UserSlug)
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, map[string][]string{
+			"favorite": {"Must be true or false"},
+		}, nil)
+	}
+
+	if statusCode, errString := H.vaultsJSON(
+		c, fiber.MethodPut, "/api/entries/" + slug + "/favorite", utils.SetFavorite, nil, &reqBody,
+		nil,
+	); errString != "" {
+		H.logger(
+			c, utils.SetFavorite, errString, "", "error", utils.ErrorVaultsSetFavorite,
+			session.UserSlug,
+		)
+
+		return respondVaultsJSONError(c, statusCode)
+	}
+
+	return c.SendStatus(204)
+}
@@ -1,18 +1,31 @@
 package controllers
 
 import (
+	"strconv"
+
 	"github.com/gofiber/fiber/v2"
 
 	"github.com/liobrdev/simplepasswords_api_gateway/utils"
 )
 
+// Secrets, if given with an entry_type, replace the entry's fields and are checked against
+// the type's schema
 type UpdateEntryRequestBody struct {
-	Title string `json:"entry_title"`
+	Title     string          `json:"entry_title"`
+	EntryType string          `json:"entry_type,omitempty"`
+	URLMatch  string          `json:"url_match,omitempty"`
+	Secrets   []reqBodySecret `json:"secrets,omitempty"`
 }
 
 func (H Handler) VaultsUpdateEntry(c *fiber.Ctx) error {
-	if header := c.Get("Client-Operation"); header != utils.UpdateEntry {
-		H.logger(c, utils.UpdateEntry, header, "", "warn", utils.ErrorClientOperation, "")
+	clientOperation := c.Get("Client-Operation")
+
+	if clientOperation == utils.MoveEntry {
+		return c.Next()
+	}
+
+	if clientOperation != utils.UpdateEntry {
+		H.logger(c, utils.UpdateEntry, clientOperation, "", "warn", utils.ErrorClientOperation, "")
 
 		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
 	}
@@ -26,16 +39,70 @@ func (H Handler) VaultsUpdateEntry(c *fiber.Ctx) error {
 	}
 
 	slug := c.Params("slug")
+	ifMatch, ok := ifMatchHeader(c)
+
+	if !ok {
+		H.logger(c, utils.UpdateEntry, ifMatch, slug, "warn", utils.ErrorIfMatch, "")
+
+		return respondPreconditionRequired(c)
+	}
+
+	fieldErrors := map[string][]string{}
+
+	if !validateURLMatch(fieldErrors, "", reqBody.URLMatch) {
+		H.logger(c, utils.UpdateEntry, reqBody.URLMatch, slug, "warn", utils.ErrorURLMatch, "")
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, fieldErrors, nil)
+	}
+
+	if !validateEntryType(fieldErrors, "", reqBody.EntryType, reqBody.Secrets) {
+		H.logger(c, utils.UpdateEntry, reqBody.EntryType, slug, "warn", utils.ErrorEntryType, "")
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, fieldErrors, nil)
+	}
+
+	for i, secret := range reqBody.Secrets {
+		validateSecretKind(fieldErrors, "secrets[" + strconv.Itoa(i) + "].", secret.Kind, secret.String)
+	}
 
-	agent := fiber.Patch(
-		"http://" + H.Conf.VAULTS_HOST + ":" + H.Conf.VAULTS_PORT + "/api/entries/" + slug,
-	)
+	if len(fieldErrors) > 0 {
+		H.logger(c, utils.UpdateEntry, "", slug, "warn", utils.ErrorSecretKind, "")
 
-	agent.Set("Authorization", "Token " + H.Conf.VAULTS_ACCESS_TOKEN)
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, fieldErrors, nil)
+	}
+
+	upstream, err := H.Vaults.Acquire()
+
+	if err != nil {
+		H.logger(c, utils.UpdateEntry, err.Error(), "", "error", utils.ErrorVaultsUpstream, "")
+
+		return utils.RespondWithError(c, 503, utils.ErrorServer, nil, nil)
+	}
+
+	defer upstream.Release()
+
+	agent := fiber.Patch(upstream.URL + "/api/entries/" + slug)
 	agent.Set("Client-Operation", utils.UpdateEntry)
+	agent.Set(fiber.HeaderIfMatch, ifMatch)
 	agent.JSON(&reqBody)
 
-	_, _, errString := checkVaultsResponse(agent)
+	if err = H.setVaultsToken(c, agent, utils.UpdateEntry); err != nil {
+		H.logger(c, utils.UpdateEntry, err.Error(), "", "error", utils.ErrorVaultsToken, "")
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	resp := fiber.AcquireResponse()
+	defer fiber.ReleaseResponse(resp)
+	agent.SetResponse(resp)
+
+	statusCode, _, errString := checkVaultsResponse(agent)
+
+	if statusCode == 412 {
+		H.logger(c, utils.UpdateEntry, ifMatch, slug, "warn", utils.ErrorStaleIfMatch, "")
+
+		return respondPreconditionFailed(c, resp)
+	}
 
 	if errString != "" {
 		H.logger(c, utils.UpdateEntry, errString, "", "error", utils.ErrorVaultsUpdateEntry, "")
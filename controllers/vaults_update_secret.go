@@ -9,6 +9,7 @@ import (
 type UpdateSecretRequestBody struct {
 	Label		 	string `json:"secret_label"`
 	String	 	string `json:"secret_string"`
+	Kind		 	string `json:"secret_kind,omitempty"`
 }
 
 func (H Handler) VaultsUpdateSecret(c *fiber.Ctx) error {
@@ -33,17 +34,56 @@ func (H Handler) VaultsUpdateSecret(c *fiber.Ctx) error {
 	}
 
 	slug := c.Params("slug")
+	fieldErrors := map[string][]string{}
+	validateSecretKind(fieldErrors, "", reqBody.Kind, reqBody.String)
 
-	agent := fiber.Patch(
-		"http://" + H.Conf.VAULTS_HOST + ":" + H.Conf.VAULTS_PORT + "/api/secrets/" + slug,
-	)
+	if len(fieldErrors) > 0 {
+		H.logger(c, utils.UpdateSecret, "", slug, "warn", utils.ErrorSecretKind, "")
 
-	agent.Set("Authorization", "Token " + H.Conf.VAULTS_ACCESS_TOKEN)
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, fieldErrors, nil)
+	}
+
+	ifMatch, ok := ifMatchHeader(c)
+
+	if !ok {
+		H.logger(c, utils.UpdateSecret, ifMatch, slug, "warn", utils.ErrorIfMatch, "")
+
+		return respondPreconditionRequired(c)
+	}
+
+	upstream, err := H.Vaults.Acquire()
+
+	if err != nil {
+		H.logger(c, utils.UpdateSecret, err.Error(), "", "error", utils.ErrorVaultsUpstream, "")
+
+		return utils.RespondWithError(c, 503, utils.ErrorServer, nil, nil)
+	}
+
+	defer upstream.Release()
+
+	agent := fiber.Patch(upstream.URL + "/api/secrets/" + slug)
 	agent.Set("Client-Operation", utils.UpdateSecret)
+	agent.Set(fiber.HeaderIfMatch, ifMatch)
 	agent.Set(H.Conf.PASSWORD_HEADER_KEY, c.Get(H.Conf.PASSWORD_HEADER_KEY)[:64])
 	agent.JSON(&reqBody)
 
-	_, _, errString := checkVaultsResponse(agent)
+	if err = H.setVaultsToken(c, agent, utils.UpdateSecret); err != nil {
+		H.logger(c, utils.UpdateSecret, err.Error(), "", "error", utils.ErrorVaultsToken, "")
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	resp := fiber.AcquireResponse()
+	defer fiber.ReleaseResponse(resp)
+	agent.SetResponse(resp)
+
+	statusCode, _, errString := checkVaultsResponse(agent)
+
+	if statusCode == 412 {
+		H.logger(c, utils.UpdateSecret, ifMatch, slug, "warn", utils.ErrorStaleIfMatch, "")
+
+		return respondPreconditionFailed(c, resp)
+	}
 
 	if errString != "" {
 		H.logger(c, utils.UpdateSecret, errString, "", "error", utils.ErrorVaultsUpdateSecret, "")
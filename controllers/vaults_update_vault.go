@@ -26,16 +26,46 @@ func (H Handler) VaultsUpdateVault(c *fiber.Ctx) error {
 	}
 
 	slug := c.Params("slug")
+	ifMatch, ok := ifMatchHeader(c)
 
-	agent := fiber.Patch(
-		"http://" + H.Conf.VAULTS_HOST + ":" + H.Conf.VAULTS_PORT + "/api/vaults/" + slug,
-	)
+	if !ok {
+		H.logger(c, utils.UpdateVault, ifMatch, slug, "warn", utils.ErrorIfMatch, "")
 
-	agent.Set("Authorization", "Token " + H.Conf.VAULTS_ACCESS_TOKEN)
+		return respondPreconditionRequired(c)
+	}
+
+	upstream, err := H.Vaults.Acquire()
+
+	if err != nil {
+		H.logger(c, utils.UpdateVault, err.Error(), "", "error", utils.ErrorVaultsUpstream, "")
+
+		return utils.RespondWithError(c, 503, utils.ErrorServer, nil, nil)
+	}
+
+	defer upstream.Release()
+
+	agent := fiber.Patch(upstream.URL + "/api/vaults/" + slug)
 	agent.Set("Client-Operation", utils.UpdateVault)
+	agent.Set(fiber.HeaderIfMatch, ifMatch)
 	agent.JSON(&reqBody)
 
-	_, _, errString := checkVaultsResponse(agent)
+	if err = H.setVaultsToken(c, agent, utils.UpdateVault); err != nil {
+		H.logger(c, utils.UpdateVault, err.Error(), "", "error", utils.ErrorVaultsToken, "")
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	resp := fiber.AcquireResponse()
+	defer fiber.ReleaseResponse(resp)
+	agent.SetResponse(resp)
+
+	statusCode, _, errString := checkVaultsResponse(agent)
+
+	if statusCode == 412 {
+		H.logger(c, utils.UpdateVault, ifMatch, slug, "warn", utils.ErrorStaleIfMatch, "")
+
+		return respondPreconditionFailed(c, resp)
+	}
 
 	if errString != "" {
 		H.logger(c, utils.UpdateVault, errString, "", "error", utils.ErrorVaultsUpdateVault, "")
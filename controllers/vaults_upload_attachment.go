@@ -0,0 +1,198 @@
+package controllers
+
+import (
+	"bytes"
+	"io"
+	"net/url"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/goccy/go-json"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/models"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+type attachmentUsage struct {
+	UsedBytes int64 `json:"used_bytes"`
+}
+
+type Attachment struct {
+	AttachmentSlug string `json:"attachment_slug"`
+	EntrySlug      string `json:"entry_slug"`
+	FileName       string `json:"file_name"`
+	ContentType    string `json:"content_type"`
+	Size           int64  `json:"size"`
+	CreatedAt      string `json:"created_at"`
+}
+
+// VaultsUploadAttachment takes a multipart upload with a single "file" part and streams it to
+// the vaults service, which encrypts and stores it. The stored content type is sniffed from the
+// file itself, never taken from the client.
+func (H Handler) VaultsUploadAttachment(c *fiber.Ctx) error {
+	if header := c.Get("Client-Operation"); header != utils.UploadAttachment {
+		H.logger(c, utils.UploadAttachment, header, "", "warn", utils.ErrorClientOperation, "")
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	var session *models.ClientSession
+	var ok bool
+
+	if session, ok = c.UserContext().Value(sessionContextKey{}).(*models.ClientSession); !ok {
+		H.logger(c, utils.UploadAttachment, "", "", "error", "Failed session.User context", "")
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	slug := c.Params("slug")
+
+	if !utils.SlugRegexp.Match([]byte(slug)) {
+		H.logger(c, utils.UploadAttachment, slug, "", "warn", utils.ErrorParams, session.UserSlug)
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, nil, nil)
+	}
+
+	fileHeader, err := c.FormFile("file")
+
+	if err != nil {
+		H.logger(
+			c, utils.UploadAttachment, err.Error(), slug, "warn", utils.ErrorAttachment,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, map[string][]string{
+			"file": {"Required"},
+		}, nil)
+	}
+
+	if fileHeader.Size > utils.AttachmentMaxSize {
+		H.logger(
+			c, utils.UploadAttachment, strconv.FormatInt(fileHeader.Size, 10), slug, "warn",
+			utils.ErrorAttachmentSize, session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 413, utils.ErrorAttachmentTooLarge, nil, nil)
+	} else if fileHeader.Size == 0 {
+		H.logger(c, utils.UploadAttachment, "0", slug, "warn", utils.ErrorAttachment, session.UserSlug)
+
+		return utils.RespondWithError(c, 400, utils.ErrorBadRequest, map[string][]string{
+			"file": {"Must not be empty"},
+		}, nil)
+	}
+
+	file, err := fileHeader.Open()
+
+	if err != nil {
+		H.logger(
+			c, utils.UploadAttachment, err.Error(), slug, "error", utils.ErrorAttachment,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	defer file.Close()
+
+	head := make([]byte, utils.AttachmentSniffLength)
+	n, err := io.ReadFull(file, head)
+
+	if err != nil && err != io.ErrUnexpectedEOF {
+		H.logger(
+			c, utils.UploadAttachment, err.Error(), slug, "error", utils.ErrorAttachment,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	head = head[:n]
+	contentType, allowed := utils.SniffAttachmentType(head)
+
+	if !allowed {
+		H.logger(
+			c, utils.UploadAttachment, contentType, slug, "warn", utils.ErrorAttachmentType,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 415, utils.ErrorAttachmentUnsupported, nil, nil)
+	}
+
+	usage := attachmentUsage{}
+
+	if statusCode, errString := H.vaultsJSON(
+		c, fiber.MethodGet, "/api/attachments/usage", utils.UploadAttachment,
+		map[string]string{"User-Slug": vaultsUserSlug(c)}, nil, &usage,
+	); errString != "" {
+		H.logger(
+			c, utils.UploadAttachment, errString, slug, "error", utils.ErrorVaultsUploadAttachment,
+			session.UserSlug,
+		)
+
+		return respondVaultsJSONError(c, statusCode)
+	}
+
+	if usage.UsedBytes + fileHeader.Size > utils.AttachmentsQuota {
+		H.logger(
+			c, utils.UploadAttachment, strconv.FormatInt(usage.UsedBytes, 10), slug, "warn",
+			utils.ErrorAttachmentQuota, session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 413, utils.ErrorAttachmentQuotaExceeded, nil, nil)
+	}
+
+	upstream, err := H.Vaults.Acquire()
+
+	if err != nil {
+		H.logger(
+			c, utils.UploadAttachment, err.Error(), "", "error", utils.ErrorVaultsUpstream,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 503, utils.ErrorServer, nil, nil)
+	}
+
+	defer upstream.Release()
+
+	agent := fiber.Post(upstream.URL + "/api/entries/" + slug + "/attachments")
+	agent.Set("Client-Operation", utils.UploadAttachment)
+	agent.Set(fiber.HeaderContentType, contentType)
+	agent.Set("Attachment-Name", url.PathEscape(utils.SanitizeAttachmentName(fileHeader.Filename)))
+	agent.Set(H.Conf.PASSWORD_HEADER_KEY, c.Get(H.Conf.PASSWORD_HEADER_KEY)[:64])
+	agent.Request().SetBodyStream(
+		io.MultiReader(bytes.NewReader(head), file), int(fileHeader.Size),
+	)
+
+	if err = H.setVaultsToken(c, agent, utils.UploadAttachment); err != nil {
+		H.logger(
+			c, utils.UploadAttachment, err.Error(), "", "error", utils.ErrorVaultsToken,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	_, body, errString := checkVaultsResponse(agent)
+
+	if errString != "" {
+		H.logger(
+			c, utils.UploadAttachment, errString, slug, "error", utils.ErrorVaultsUploadAttachment,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	attachment := Attachment{}
+
+	if err = json.Unmarshal([]byte(body), &attachment); err != nil {
+		H.logger(
+			c, utils.UploadAttachment, err.Error(), slug, "error", utils.ErrorVaultsUploadAttachment,
+			session.UserSlug,
+		)
+
+		return utils.RespondWithError(c, 500, utils.ErrorServer, nil, nil)
+	}
+
+	return c.Status(201).JSON(&attachment)
+}
@@ -0,0 +1,51 @@
+package controllers
+
+import (
+	"net/url"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+type userVaultsPage struct {
+	Vaults []struct {
+		VaultSlug string `json:"vault_slug"`
+	} `json:"vaults"`
+	NextCursor string `json:"next_cursor"`
+}
+
+// Collects the slugs of every vault the user owns, for checks that a vault named in a request
+// body belongs to the session user. On failure returns the status code to respond with and an
+// error string to log.
+func (H Handler) vaultsUserVaultSlugs(c *fiber.Ctx, userSlug string) (
+	slugs map[string]bool, statusCode int, errString string,
+) {
+	userHeader := map[string]string{"User-Slug": userSlug}
+	params := url.Values{"limit": {strconv.Itoa(listVaultsMaxLimit)}}
+	slugs = map[string]bool{}
+
+	for {
+		page := userVaultsPage{}
+
+		if statusCode, errString = H.vaultsJSON(
+			c, fiber.MethodGet, "/api/vaults?" + params.Encode(), utils.ListVaults, userHeader, nil,
+			&page,
+		); errString != "" {
+			return nil, statusCode, errString
+		}
+
+		for _, vault := range page.Vaults {
+			slugs[vault.VaultSlug] = true
+		}
+
+		if page.NextCursor == "" {
+			break
+		}
+
+		params.Set("cursor", page.NextCursor)
+	}
+
+	return slugs, 200, ""
+}
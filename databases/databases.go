@@ -3,6 +3,7 @@ package databases
 import (
 	"fmt"
 
+	"github.com/redis/go-redis/v9"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 
@@ -12,6 +13,9 @@ import (
 type Databases struct {
 	ApiGateway *gorm.DB
 	Logger     *gorm.DB
+	// Always set by Init, even while Redis is down - callers fall back to ApiGateway when a
+	// Redis command fails. Only test setups leave it nil.
+	Redis      *redis.Client
 }
 
 func Init(conf *config.AppConfig) *Databases {
@@ -41,7 +45,12 @@ func Init(conf *config.AppConfig) *Databases {
 		conf.LOGGER_DB_NAME,
 	), &gormConfig)
 
-	return &Databases{dbApiGateway, dbLogger}
+	rdb := redis.NewClient(&redis.Options{
+		Addr:     conf.REDIS_HOST + ":" + conf.REDIS_PORT,
+		Password: conf.REDIS_PASSWORD,
+	})
+
+	return &Databases{dbApiGateway, dbLogger, rdb}
 }
 
 func openDbSession(dsn string, gormConfig *gorm.Config) (db *gorm.DB) {
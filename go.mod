@@ -5,6 +5,7 @@ go 1.22
 require (
 	github.com/goccy/go-json v0.9.11
 	github.com/gofiber/fiber/v2 v2.52.4
+	github.com/redis/go-redis/v9 v9.5.1
 	github.com/spf13/viper v1.13.0
 	github.com/stretchr/testify v1.8.0
 	github.com/twilio/twilio-go v1.22.3
@@ -16,7 +17,9 @@ require (
 
 require (
 	github.com/andybalholm/brotli v1.0.5 // indirect
+	github.com/cespare/xxhash/v2 v2.2.0 // indirect
 	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/dgryski/go-rendezvous v0.0.0-20200823014737-9f7001d12a5f // indirect
 	github.com/fsnotify/fsnotify v1.5.4 // indirect
 	github.com/golang/mock v1.6.0 // indirect
 	github.com/google/uuid v1.5.0 // indirect
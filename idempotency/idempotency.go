@@ -0,0 +1,216 @@
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/databases"
+	"github.com/liobrdev/simplepasswords_api_gateway/models"
+)
+
+const (
+	// How long a completed response is replayed for
+	TTL = time.Duration(24) * time.Hour
+	// How long a reservation blocks its key if the request never completes or releases it
+	PendingTTL = time.Duration(5) * time.Minute
+
+	redisKeyPrefix = "idempotency:"
+)
+
+var (
+	ErrInProgress = errors.New("request with this idempotency key is still in progress")
+	ErrMismatch   = errors.New("idempotency key reused with a different request")
+	ErrNotBegun   = errors.New("idempotency key has no reservation to complete")
+)
+
+type Response struct {
+	StatusCode  int    `json:"status_code"`
+	ContentType string `json:"content_type"`
+	Body        []byte `json:"body"`
+}
+
+type entry struct {
+	Fingerprint []byte    `json:"fingerprint"`
+	Completed   bool      `json:"completed"`
+	Response    *Response `json:"response,omitempty"`
+}
+
+// Store keeps idempotency keys with their request fingerprints and responses. Redis is used
+// when available; any Redis error falls back to the api_gateway database, so a Redis outage
+// degrades to slower lookups rather than duplicated creates.
+type Store struct {
+	redis *redis.Client
+	db    *gorm.DB
+}
+
+func New(dbs *databases.Databases) *Store {
+	return &Store{redis: dbs.Redis, db: dbs.ApiGateway}
+}
+
+// Key scopes a client-chosen Idempotency-Key to one user and one client operation
+func Key(userSlug, clientOperation, idempotencyKey string) string {
+	sum := sha256.Sum256([]byte(userSlug + "\x00" + clientOperation + "\x00" + idempotencyKey))
+
+	return hex.EncodeToString(sum[:])
+}
+
+func Fingerprint(method, path string, body []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte(method + "\x00" + path + "\x00"))
+	h.Write(body)
+
+	return h.Sum(nil)
+}
+
+// Begin reserves key for a request with the given fingerprint. A non-nil Response is a
+// completed response to replay. A nil Response with a nil error means the caller now holds
+// the reservation and must either Complete or Release it.
+func (s *Store) Begin(
+	ctx context.Context, key, userSlug string, fingerprint []byte,
+) (*Response, error) {
+	if s.redis != nil {
+		if resp, err := s.beginRedis(ctx, key, fingerprint); err == nil ||
+		errors.Is(err, ErrInProgress) || errors.Is(err, ErrMismatch) {
+			return resp, err
+		}
+	}
+
+	return s.beginDB(key, userSlug, fingerprint)
+}
+
+func (s *Store) Complete(ctx context.Context, key string, fingerprint []byte, resp *Response) error {
+	if s.redis != nil {
+		data, err := json.Marshal(&entry{Fingerprint: fingerprint, Completed: true, Response: resp})
+
+		if err != nil {
+			return err
+		}
+
+		if err = s.redis.Set(ctx, redisKeyPrefix + key, data, TTL).Err(); err == nil {
+			return nil
+		}
+	}
+
+	// A reservation made in Redis has no row here, so if Redis has failed since Begin there is
+	// nothing to complete, and a retry would run the request again
+	result := s.db.Model(&models.IdempotencyRecord{}).Where("key = ?", key).Updates(
+		map[string]interface{}{
+			"completed":    true,
+			"status_code":  resp.StatusCode,
+			"content_type": resp.ContentType,
+			"body":         resp.Body,
+			"expires_at":   time.Now().UTC().Add(TTL),
+		},
+	)
+
+	if result.Error != nil {
+		return result.Error
+	} else if result.RowsAffected == 0 {
+		return ErrNotBegun
+	}
+
+	return nil
+}
+
+func (s *Store) Release(ctx context.Context, key string) error {
+	if s.redis != nil {
+		if err := s.redis.Del(ctx, redisKeyPrefix + key).Err(); err == nil {
+			return nil
+		}
+	}
+
+	return s.db.Where("key = ?", key).Delete(&models.IdempotencyRecord{}).Error
+}
+
+func (s *Store) beginRedis(ctx context.Context, key string, fingerprint []byte) (*Response, error) {
+	data, err := json.Marshal(&entry{Fingerprint: fingerprint})
+
+	if err != nil {
+		return nil, err
+	}
+
+	// A second attempt covers the stored entry expiring between SETNX and GET
+	for attempt := 0; attempt < 2; attempt++ {
+		var ok bool
+
+		if ok, err = s.redis.SetNX(ctx, redisKeyPrefix + key, data, PendingTTL).Result(); err != nil {
+			return nil, err
+		} else if ok {
+			return nil, nil
+		}
+
+		var stored []byte
+
+		if stored, err = s.redis.Get(ctx, redisKeyPrefix + key).Bytes(); err == redis.Nil {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		var existing entry
+
+		if err = json.Unmarshal(stored, &existing); err != nil {
+			return nil, err
+		}
+
+		return compare(existing.Fingerprint, fingerprint, existing.Completed, existing.Response)
+	}
+
+	return nil, ErrInProgress
+}
+
+func (s *Store) beginDB(key, userSlug string, fingerprint []byte) (*Response, error) {
+	now := time.Now().UTC()
+
+	if result := s.db.Where("expires_at < ?", now).Delete(&models.IdempotencyRecord{});
+	result.Error != nil {
+		return nil, result.Error
+	}
+
+	var existing models.IdempotencyRecord
+
+	if result := s.db.Where("key = ?", key).Limit(1).Find(&existing); result.Error != nil {
+		return nil, result.Error
+	} else if result.RowsAffected == 0 {
+		record := models.IdempotencyRecord{
+			Key:         key,
+			UserSlug:    userSlug,
+			Fingerprint: fingerprint,
+			CreatedAt:   now,
+			ExpiresAt:   now.Add(PendingTTL),
+		}
+
+		// Losing an insert race to a concurrent request leaves the key reserved by it
+		if result = s.db.Omit("User").Create(&record); result.Error != nil {
+			return nil, ErrInProgress
+		}
+
+		return nil, nil
+	}
+
+	return compare(existing.Fingerprint, fingerprint, existing.Completed, &Response{
+		StatusCode:  existing.StatusCode,
+		ContentType: existing.ContentType,
+		Body:        existing.Body,
+	})
+}
+
+func compare(stored, fingerprint []byte, completed bool, resp *Response) (*Response, error) {
+	if !bytes.Equal(stored, fingerprint) {
+		return nil, ErrMismatch
+	}
+
+	if !completed || resp == nil {
+		return nil, ErrInProgress
+	}
+
+	return resp, nil
+}
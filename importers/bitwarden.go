@@ -0,0 +1,75 @@
+package importers
+
+import "github.com/goccy/go-json"
+
+type bitwardenExport struct {
+	Encrypted bool            `json:"encrypted"`
+	Items     []bitwardenItem `json:"items"`
+}
+
+type bitwardenItem struct {
+	Type   int              `json:"type"`
+	Name   string           `json:"name"`
+	Notes  string           `json:"notes"`
+	Login  *bitwardenLogin  `json:"login"`
+	Fields []bitwardenField `json:"fields"`
+}
+
+type bitwardenLogin struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	TOTP     string `json:"totp"`
+	URIs     []struct {
+		URI string `json:"uri"`
+	} `json:"uris"`
+}
+
+type bitwardenField struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+func parseBitwardenJSON(data []byte) ([]Entry, []RowError, error) {
+	var export bitwardenExport
+
+	if err := json.Unmarshal(data, &export); err != nil {
+		return nil, nil, err
+	}
+
+	if export.Encrypted {
+		return nil, nil, errEncryptedExport
+	}
+
+	var entries []Entry
+
+	for i, item := range export.Items {
+		entry := Entry{Row: i + 1, Title: item.Name, Notes: item.Notes}
+
+		if item.Login != nil {
+			entry.Username = item.Login.Username
+			entry.Password = item.Login.Password
+
+			for j, uri := range item.Login.URIs {
+				if j == 0 {
+					entry.URL = uri.URI
+				} else if uri.URI != "" {
+					entry.Fields = append(entry.Fields, Field{Label: "URL", Value: uri.URI})
+				}
+			}
+
+			if item.Login.TOTP != "" {
+				entry.Fields = append(entry.Fields, Field{Label: "TOTP", Value: item.Login.TOTP})
+			}
+		}
+
+		for _, field := range item.Fields {
+			if field.Value != "" {
+				entry.Fields = append(entry.Fields, Field{Label: field.Name, Value: field.Value})
+			}
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil, nil
+}
@@ -0,0 +1,124 @@
+package importers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"io"
+	"strings"
+)
+
+// Header aliases cover Chrome, Firefox, 1Password and Bitwarden CSV exports.
+var csvColumnAliases = map[string]string{
+	"name":           "title",
+	"title":          "title",
+	"url":            "url",
+	"website":        "url",
+	"login_uri":      "url",
+	"username":       "username",
+	"login_username": "username",
+	"password":       "password",
+	"login_password": "password",
+	"note":           "notes",
+	"notes":          "notes",
+}
+
+// Bookkeeping columns that are not worth keeping as secrets.
+var csvIgnoredColumns = map[string]bool{
+	"httprealm":           true,
+	"formactionorigin":    true,
+	"guid":                true,
+	"timecreated":         true,
+	"timelastused":        true,
+	"timepasswordchanged": true,
+	"favorite":            true,
+	"favorite status":     true,
+	"archived status":     true,
+	"folder":              true,
+	"type":                true,
+	"reprompt":            true,
+	"tags":                true,
+}
+
+func parseCSV(data []byte) ([]Entry, []RowError, error) {
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\xEF\xBB\xBF"))))
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	columns := make([]string, len(header))
+	found := false
+
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+
+		if column, ok := csvColumnAliases[name]; ok {
+			columns[i] = column
+			found = true
+		} else if !csvIgnoredColumns[name] && name != "" {
+			columns[i] = "field:" + strings.TrimSpace(header[i])
+		}
+	}
+
+	if !found {
+		return nil, nil, errors.New("unrecognized CSV header")
+	}
+
+	var entries []Entry
+	var rowErrors []RowError
+
+	for row := 1; ; row++ {
+		record, err := reader.Read()
+
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			rowErrors = append(rowErrors, RowError{Row: row, Error: err.Error()})
+
+			if len(rowErrors) > MaxRows {
+				return nil, nil, ErrTooManyRows
+			}
+
+			continue
+		}
+
+		if len(entries) > MaxRows {
+			return nil, nil, ErrTooManyRows
+		}
+
+		entry := Entry{Row: row}
+
+		for i, value := range record {
+			if i >= len(columns) || value == "" {
+				continue
+			}
+
+			switch column := columns[i]; column {
+			case "title":
+				entry.Title = value
+			case "url":
+				entry.URL = value
+			case "username":
+				entry.Username = value
+			case "password":
+				entry.Password = value
+			case "notes":
+				entry.Notes = value
+			case "":
+			default:
+				entry.Fields = append(entry.Fields, Field{
+					Label: strings.TrimPrefix(column, "field:"),
+					Value: value,
+				})
+			}
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, rowErrors, nil
+}
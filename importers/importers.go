@@ -0,0 +1,113 @@
+package importers
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+)
+
+const (
+	ChromeCSV       string = "chrome_csv"
+	FirefoxCSV      string = "firefox_csv"
+	BitwardenJSON   string = "bitwarden_json"
+	OnePassword1PUX string = "onepassword_1pux"
+	OnePasswordCSV  string = "onepassword_csv"
+	KeePassXML      string = "keepass_xml"
+)
+
+const MaxRows int = 5000
+
+var Formats = []string{
+	ChromeCSV, FirefoxCSV, BitwardenJSON, OnePassword1PUX, OnePasswordCSV, KeePassXML,
+}
+
+var ErrUnknownFormat = errors.New("unknown import format")
+var ErrTooManyRows = errors.New("too many rows in import file")
+
+type Field struct {
+	Label string
+	Value string
+}
+
+// Entry is one login parsed from an export, before it is mapped onto a vaults entry. Row is the
+// 1-based position of the item in the source file, used for per-row error reporting.
+type Entry struct {
+	Row      int
+	Title    string
+	URL      string
+	Username string
+	Password string
+	Notes    string
+	Fields   []Field
+}
+
+type RowError struct {
+	Row   int    `json:"row"`
+	Error string `json:"error"`
+}
+
+func Parse(format string, data []byte) ([]Entry, []RowError, error) {
+	var entries []Entry
+	var rowErrors []RowError
+	var err error
+
+	switch format {
+	case ChromeCSV, FirefoxCSV, OnePasswordCSV:
+		entries, rowErrors, err = parseCSV(data)
+	case BitwardenJSON:
+		entries, rowErrors, err = parseBitwardenJSON(data)
+	case OnePassword1PUX:
+		entries, rowErrors, err = parseOnePassword1PUX(data)
+	case KeePassXML:
+		entries, rowErrors, err = parseKeePassXML(data)
+	default:
+		return nil, nil, ErrUnknownFormat
+	}
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(entries) + len(rowErrors) > MaxRows {
+		return nil, nil, ErrTooManyRows
+	}
+
+	valid := make([]Entry, 0, len(entries))
+
+	for _, entry := range entries {
+		entry.Title = strings.TrimSpace(entry.Title)
+		entry.URL = strings.TrimSpace(entry.URL)
+
+		if entry.Title == "" {
+			entry.Title = Host(entry.URL)
+		}
+
+		if entry.Title == "" {
+			rowErrors = append(rowErrors, RowError{Row: entry.Row, Error: "Missing title and URL."})
+		} else if entry.Username == "" && entry.Password == "" && entry.Notes == "" &&
+		len(entry.Fields) == 0 {
+			rowErrors = append(rowErrors, RowError{Row: entry.Row, Error: "Nothing to import."})
+		} else {
+			valid = append(valid, entry)
+		}
+	}
+
+	return valid, rowErrors, nil
+}
+
+// Host returns the lowercased host of rawURL, tolerating URLs saved without a scheme.
+func Host(rawURL string) string {
+	if rawURL == "" {
+		return ""
+	}
+
+	if !strings.Contains(rawURL, "://") {
+		rawURL = "https://" + rawURL
+	}
+
+	if u, err := url.Parse(rawURL); err == nil {
+		return strings.ToLower(u.Hostname())
+	}
+
+	return ""
+}
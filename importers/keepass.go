@@ -0,0 +1,83 @@
+package importers
+
+import (
+	"bytes"
+	"encoding/xml"
+)
+
+type keePassFile struct {
+	Root struct {
+		Groups []keePassGroup `xml:"Group"`
+	} `xml:"Root"`
+}
+
+type keePassGroup struct {
+	Name    string         `xml:"Name"`
+	Entries []keePassEntry `xml:"Entry"`
+	Groups  []keePassGroup `xml:"Group"`
+}
+
+// History is not mapped, so older revisions nested inside an entry are skipped.
+type keePassEntry struct {
+	Strings []struct {
+		Key   string `xml:"Key"`
+		Value string `xml:"Value"`
+	} `xml:"String"`
+}
+
+func parseKeePassXML(data []byte) ([]Entry, []RowError, error) {
+	var file keePassFile
+
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.Strict = true
+
+	if err := decoder.Decode(&file); err != nil {
+		return nil, nil, err
+	}
+
+	var entries []Entry
+	row := 0
+
+	var walk func(groups []keePassGroup)
+
+	walk = func(groups []keePassGroup) {
+		for _, group := range groups {
+			// KeePass keeps deleted entries in the recycle bin group
+			if group.Name == "Recycle Bin" {
+				continue
+			}
+
+			for _, keePass := range group.Entries {
+				row++
+				entry := Entry{Row: row}
+
+				for _, s := range keePass.Strings {
+					switch s.Key {
+					case "Title":
+						entry.Title = s.Value
+					case "URL":
+						entry.URL = s.Value
+					case "UserName":
+						entry.Username = s.Value
+					case "Password":
+						entry.Password = s.Value
+					case "Notes":
+						entry.Notes = s.Value
+					default:
+						if s.Value != "" {
+							entry.Fields = append(entry.Fields, Field{Label: s.Key, Value: s.Value})
+						}
+					}
+				}
+
+				entries = append(entries, entry)
+			}
+
+			walk(group.Groups)
+		}
+	}
+
+	walk(file.Root.Groups)
+
+	return entries, nil, nil
+}
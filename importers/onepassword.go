@@ -0,0 +1,140 @@
+package importers
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"io"
+
+	"github.com/goccy/go-json"
+)
+
+const maxOnePasswordDataBytes int64 = 64 << 20
+
+var errEncryptedExport = errors.New("encrypted exports are not supported")
+var errMissing1PUXData = errors.New("export.data not found in 1PUX archive")
+
+type onePasswordExport struct {
+	Accounts []struct {
+		Vaults []struct {
+			Items []onePasswordItem `json:"items"`
+		} `json:"vaults"`
+	} `json:"accounts"`
+}
+
+type onePasswordItem struct {
+	State    string `json:"state"`
+	Overview struct {
+		Title string `json:"title"`
+		URL   string `json:"url"`
+	} `json:"overview"`
+	Details struct {
+		LoginFields []struct {
+			Designation string `json:"designation"`
+			Name        string `json:"name"`
+			Value       string `json:"value"`
+		} `json:"loginFields"`
+		NotesPlain string `json:"notesPlain"`
+		Sections   []struct {
+			Fields []struct {
+				Title string                       `json:"title"`
+				Value map[string]json.RawMessage `json:"value"`
+			} `json:"fields"`
+		} `json:"sections"`
+		Password string `json:"password"`
+	} `json:"details"`
+}
+
+func parseOnePassword1PUX(data []byte) ([]Entry, []RowError, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var exportData []byte
+
+	for _, file := range archive.File {
+		if file.Name != "export.data" {
+			continue
+		}
+
+		reader, err := file.Open()
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		exportData, err = io.ReadAll(io.LimitReader(reader, maxOnePasswordDataBytes))
+		reader.Close()
+
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if exportData == nil {
+		return nil, nil, errMissing1PUXData
+	}
+
+	var export onePasswordExport
+
+	if err = json.Unmarshal(exportData, &export); err != nil {
+		return nil, nil, err
+	}
+
+	var entries []Entry
+	row := 0
+
+	for _, account := range export.Accounts {
+		for _, vault := range account.Vaults {
+			for _, item := range vault.Items {
+				row++
+
+				if item.State == "archived" {
+					continue
+				}
+
+				entries = append(entries, onePasswordEntry(row, &item))
+			}
+		}
+	}
+
+	return entries, nil, nil
+}
+
+func onePasswordEntry(row int, item *onePasswordItem) Entry {
+	entry := Entry{
+		Row:      row,
+		Title:    item.Overview.Title,
+		URL:      item.Overview.URL,
+		Notes:    item.Details.NotesPlain,
+		Password: item.Details.Password,
+	}
+
+	for _, field := range item.Details.LoginFields {
+		switch field.Designation {
+		case "username":
+			entry.Username = field.Value
+		case "password":
+			entry.Password = field.Value
+		}
+	}
+
+	for _, section := range item.Details.Sections {
+		for _, field := range section.Fields {
+			// Section values are keyed by type, e.g. {"concealed": "..."} or {"string": "..."}
+			for _, raw := range field.Value {
+				var value string
+
+				if json.Unmarshal(raw, &value) == nil && value != "" {
+					entry.Fields = append(entry.Fields, Field{Label: field.Title, Value: value})
+				}
+
+				break
+			}
+		}
+	}
+
+	return entry
+}
@@ -3,13 +3,17 @@ package main
 import (
 	"log"
 
+	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/healthcheck"
 
 	"github.com/liobrdev/simplepasswords_api_gateway/app"
+	"github.com/liobrdev/simplepasswords_api_gateway/breached"
 	"github.com/liobrdev/simplepasswords_api_gateway/config"
+	"github.com/liobrdev/simplepasswords_api_gateway/controllers"
 	"github.com/liobrdev/simplepasswords_api_gateway/databases"
 	"github.com/liobrdev/simplepasswords_api_gateway/models"
 	"github.com/liobrdev/simplepasswords_api_gateway/routes"
+	"github.com/liobrdev/simplepasswords_api_gateway/upstreams"
 )
 
 func main() {
@@ -21,6 +25,8 @@ func main() {
 
 	app := app.CreateApp(&conf)
 	dbs := databases.Init(&conf)
+	vaults := upstreams.Init(&conf)
+	breachedData := breached.Init(&conf)
 
 	if err := dbs.ApiGateway.AutoMigrate(
 		&models.User{},
@@ -28,6 +34,20 @@ func main() {
 		&models.MFAToken{},
 		&models.EmailVerificationToken{},
 		&models.PhoneVerificationToken{},
+		&models.IdempotencyRecord{},
+		&models.Share{},
+		&models.UserKeyPair{},
+		&models.SharedVault{},
+		&models.VaultMember{},
+		&models.VaultInvitation{},
+		&models.Organization{},
+		&models.OrgMember{},
+		&models.OrgGroup{},
+		&models.OrgGroupMember{},
+		&models.OrgVaultGrant{},
+		&models.OrgInvitation{},
+		&models.EmergencyContact{},
+		&models.EmergencyContactVault{},
 	); err != nil {
 		log.Fatalln("Failed api_gateway database auto-migrate:", err.Error())
 	}
@@ -36,8 +56,26 @@ func main() {
 		log.Fatalln("Failed logger database auto-migrate:", err.Error())
 	}
 
-	app.Use(healthcheck.New())
-	routes.Register(app, dbs, &conf)
+	// With prefork, the parent process serves no requests, so it runs the background jobs once for
+	// all of its children
+	if fiber.IsChild() {
+		go vaults.RunStatusSync()
+	} else {
+		go vaults.RunHealthChecks()
+		go controllers.RunTrashPurge(&conf, vaults)
+		go controllers.RunEmergencyAccessNotices(&conf, dbs)
+	}
+
+	app.Use(healthcheck.New(healthcheck.Config{
+		// Body is kept by SendStatus, so readiness also reports each vaults upstream
+		ReadinessProbe: func(c *fiber.Ctx) bool {
+			c.JSON(vaults.Status())
+
+			return vaults.Ready()
+		},
+	}))
+
+	routes.Register(app, dbs, vaults, breachedData, &conf)
 
 	log.Fatal(app.Listen(conf.API_GATEWAY_HOST + ":" + conf.API_GATEWAY_PORT))
 }
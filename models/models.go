@@ -56,6 +56,154 @@ type MFAToken struct {
 	ExpiresAt time.Time `gorm:"not null"`
 }
 
+// Key is a digest of the user slug, client operation and Idempotency-Key header, so keys
+// chosen by different users or for different operations never collide
+type IdempotencyRecord struct {
+	Key         string    `gorm:"primaryKey;size:64;not null"`
+	UserSlug    string    `gorm:"not null"`
+	User        User      `gorm:"foreignKey:UserSlug;constraint:OnDelete:CASCADE"`
+	Fingerprint []byte    `gorm:"not null"`
+	Completed   bool      `gorm:"default:false;not null"`
+	StatusCode  int       `gorm:"not null"`
+	ContentType string    `gorm:"not null"`
+	Body        []byte
+	CreatedAt   time.Time `gorm:"autoCreateTime:false;not null"`
+	ExpiresAt   time.Time `gorm:"index;not null"`
+}
+
+// Ciphertext is encrypted by the client with a key that only travels in the share link's URL
+// fragment, which browsers never send, so the gateway can't read what it stores
+type Share struct {
+	Slug       string    `json:"share_slug" gorm:"primaryKey;size:32;not null"`
+	UserSlug   string    `json:"-" gorm:"index;not null"`
+	User       User      `json:"-" gorm:"foreignKey:UserSlug;constraint:OnDelete:CASCADE"`
+	Label      string    `json:"label"`
+	Ciphertext string    `json:"-" gorm:"not null"`
+	MaxViews   int       `json:"max_views" gorm:"not null"`
+	ViewsLeft  int       `json:"views_left" gorm:"not null"`
+	CreatedAt  time.Time `json:"created_at" gorm:"autoCreateTime:false;not null"`
+	ExpiresAt  time.Time `json:"expires_at" gorm:"index;not null"`
+}
+
+// The private key is encrypted client-side before upload, so only the public key is usable
+// here. Other members' clients seal vault keys to it.
+type UserKeyPair struct {
+	UserSlug            string    `gorm:"primaryKey;not null"`
+	User                User      `gorm:"foreignKey:UserSlug;constraint:OnDelete:CASCADE"`
+	PublicKey           []byte    `gorm:"not null"`
+	EncryptedPrivateKey []byte    `gorm:"not null"`
+	UpdatedAt           time.Time `gorm:"autoUpdateTime:nano;not null"`
+}
+
+// A SharedVault is a vault in the vaults service that has members besides its owner. Requests
+// by members are made to the vaults service on the owner's behalf. RekeyRequired is set when a
+// member leaves, until a manager rotates the vault key.
+type SharedVault struct {
+	VaultSlug     string    `gorm:"primaryKey;size:16;not null"`
+	OwnerSlug     string    `gorm:"index;not null"`
+	Owner         User      `gorm:"foreignKey:OwnerSlug;constraint:OnDelete:CASCADE"`
+	RekeyRequired bool      `gorm:"default:false;not null"`
+	CreatedAt     time.Time `gorm:"autoCreateTime:false;not null"`
+}
+
+// WrappedKey is the vault key sealed to the member's public key, empty until a manager's client
+// has wrapped it. The owner is a member too, with the manage role.
+type VaultMember struct {
+	VaultSlug   string      `gorm:"primaryKey;size:16;not null"`
+	SharedVault SharedVault `gorm:"foreignKey:VaultSlug;constraint:OnDelete:CASCADE"`
+	UserSlug    string      `gorm:"primaryKey;not null"`
+	User        User        `gorm:"foreignKey:UserSlug;constraint:OnDelete:CASCADE"`
+	Role        string      `gorm:"not null"`
+	WrappedKey  []byte
+	CreatedAt   time.Time   `gorm:"autoCreateTime:false;not null"`
+}
+
+type VaultInvitation struct {
+	Slug         string      `gorm:"primaryKey;size:32;not null"`
+	VaultSlug    string      `gorm:"index;not null"`
+	SharedVault  SharedVault `gorm:"foreignKey:VaultSlug;constraint:OnDelete:CASCADE"`
+	InviterSlug  string      `gorm:"not null"`
+	Inviter      User        `gorm:"foreignKey:InviterSlug;constraint:OnDelete:CASCADE"`
+	EmailAddress string      `gorm:"index;not null"`
+	Role         string      `gorm:"not null"`
+	CreatedAt    time.Time   `gorm:"autoCreateTime:false;not null"`
+	ExpiresAt    time.Time   `gorm:"index;not null"`
+}
+
+// An Organization owns vaults in the vaults service under its own slug, in place of a user's.
+// What each member may do there is decided by their role and their groups' grants.
+type Organization struct {
+	Slug      string    `gorm:"primaryKey;size:16;not null"`
+	Name      string    `gorm:"not null"`
+	CreatedAt time.Time `gorm:"autoCreateTime:false;not null"`
+}
+
+type OrgMember struct {
+	OrgSlug      string       `gorm:"primaryKey;size:16;not null"`
+	Organization Organization `gorm:"foreignKey:OrgSlug;constraint:OnDelete:CASCADE"`
+	UserSlug     string       `gorm:"primaryKey;not null"`
+	User         User         `gorm:"foreignKey:UserSlug;constraint:OnDelete:CASCADE"`
+	Role         string       `gorm:"not null"`
+	CreatedAt    time.Time    `gorm:"autoCreateTime:false;not null"`
+}
+
+type OrgGroup struct {
+	Slug         string       `gorm:"primaryKey;size:16;not null"`
+	OrgSlug      string       `gorm:"index;not null"`
+	Organization Organization `gorm:"foreignKey:OrgSlug;constraint:OnDelete:CASCADE"`
+	Name         string       `gorm:"not null"`
+	CreatedAt    time.Time    `gorm:"autoCreateTime:false;not null"`
+}
+
+type OrgGroupMember struct {
+	GroupSlug string   `gorm:"primaryKey;size:16;not null"`
+	OrgGroup  OrgGroup `gorm:"foreignKey:GroupSlug;constraint:OnDelete:CASCADE"`
+	UserSlug  string   `gorm:"primaryKey;not null"`
+	User      User     `gorm:"foreignKey:UserSlug;constraint:OnDelete:CASCADE"`
+}
+
+// Role is a vault role, as for shared vaults. A member's role in an org vault is the greatest
+// granted to any of their groups.
+type OrgVaultGrant struct {
+	GroupSlug string   `gorm:"primaryKey;size:16;not null"`
+	OrgGroup  OrgGroup `gorm:"foreignKey:GroupSlug;constraint:OnDelete:CASCADE"`
+	VaultSlug string   `gorm:"primaryKey;size:16;not null"`
+	Role      string   `gorm:"not null"`
+}
+
+type OrgInvitation struct {
+	Slug         string       `gorm:"primaryKey;size:32;not null"`
+	OrgSlug      string       `gorm:"index;not null"`
+	Organization Organization `gorm:"foreignKey:OrgSlug;constraint:OnDelete:CASCADE"`
+	InviterSlug  string       `gorm:"not null"`
+	Inviter      User         `gorm:"foreignKey:InviterSlug;constraint:OnDelete:CASCADE"`
+	EmailAddress string       `gorm:"index;not null"`
+	Role         string       `gorm:"not null"`
+	CreatedAt    time.Time    `gorm:"autoCreateTime:false;not null"`
+	ExpiresAt    time.Time    `gorm:"index;not null"`
+}
+
+// An EmergencyContact can ask for read-only access to the grantor's selected vaults, and gets
+// it WaitingDays after RequestedAt unless the grantor rejects the request first. RemindedAt and
+// OpenedAt record the notices sent for the current request.
+type EmergencyContact struct {
+	Slug         string     `gorm:"primaryKey;size:16;not null"`
+	GrantorSlug  string     `gorm:"index;not null"`
+	Grantor      User       `gorm:"foreignKey:GrantorSlug;constraint:OnDelete:CASCADE"`
+	EmailAddress string     `gorm:"index;not null"`
+	WaitingDays  int        `gorm:"not null"`
+	RequestedAt  *time.Time
+	RemindedAt   *time.Time
+	OpenedAt     *time.Time
+	CreatedAt    time.Time  `gorm:"autoCreateTime:false;not null"`
+}
+
+type EmergencyContactVault struct {
+	ContactSlug      string           `gorm:"primaryKey;size:16;not null"`
+	EmergencyContact EmergencyContact `gorm:"foreignKey:ContactSlug;constraint:OnDelete:CASCADE"`
+	VaultSlug        string           `gorm:"primaryKey;size:16;not null"`
+}
+
 func (ClientSession) TableName() string {
 	return "client_sessions"
 }
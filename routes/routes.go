@@ -2,14 +2,30 @@ package routes
 
 import (
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
 
+	"github.com/liobrdev/simplepasswords_api_gateway/breached"
 	"github.com/liobrdev/simplepasswords_api_gateway/config"
 	"github.com/liobrdev/simplepasswords_api_gateway/controllers"
 	"github.com/liobrdev/simplepasswords_api_gateway/databases"
+	"github.com/liobrdev/simplepasswords_api_gateway/idempotency"
+	"github.com/liobrdev/simplepasswords_api_gateway/upstreams"
 )
 
-func Register(app *fiber.App, dbs *databases.Databases, conf *config.AppConfig) {
-	H := controllers.Handler{DBs: dbs, Conf: conf}
+func Register(
+	app *fiber.App, dbs *databases.Databases, vaults *upstreams.Pool,
+	breachedData *breached.Dataset, conf *config.AppConfig,
+) {
+	H := controllers.Handler{
+		DBs:         dbs,
+		Conf:        conf,
+		Vaults:      vaults,
+		Breached:    breachedData,
+		Idempotency: idempotency.New(dbs),
+	}
+	app.Use(requestid.New())
+	app.Use(H.LimitRequestBody)
+
 	api := app.Group("/api")
 
 	authApi := api.Group("/auth")
@@ -17,6 +33,10 @@ func Register(app *fiber.App, dbs *databases.Databases, conf *config.AppConfig)
 	authApi.Post("/first_factor", H.AuthFirstFactor)
 	authApi.Post("/second_factor", H.AuthSecondFactor)
 
+	// Share recipients don't have accounts
+	sharesApi := api.Group("/shares")
+	sharesApi.Post("/:slug/open", H.OpenShare)
+
 	app.Use(H.AuthorizeRequest)
 
 	if H.Conf.ENVIRONMENT == "testing" {
@@ -35,20 +55,108 @@ func Register(app *fiber.App, dbs *databases.Databases, conf *config.AppConfig)
 	app.Use(H.CheckUserIsVerified)
 
 	vaultsApi := api.Group("/vaults")
-	vaultsApi.Post("/", H.VaultsCreateVault)
-	vaultsApi.Get("/", H.VaultsListVaults)
-	vaultsApi.Get("/:slug", H.VaultsRetrieveVault)
-	vaultsApi.Patch("/:slug", H.VaultsUpdateVault)
-	vaultsApi.Delete("/:slug", H.VaultsDeleteVault)
+	vaultsApi.Post("/", H.CheckVaultAccess, H.CheckIdempotencyKey, H.VaultsCreateVault)
+	vaultsApi.Get("/", H.CheckVaultAccess, H.VaultsListVaults)
+	vaultsApi.Get("/:slug", H.CheckVaultAccess, H.VaultsRetrieveVault)
+	vaultsApi.Patch("/:slug", H.CheckVaultAccess, H.VaultsUpdateVault)
+	vaultsApi.Delete("/:slug", H.CheckVaultAccess, H.VaultsDeleteVault)
+
+	vaultsApi.Post("/:slug/invitations", H.InviteVaultMember)
+	vaultsApi.Delete("/:slug/invitations/:invitation_slug", H.RevokeInvitation)
+	vaultsApi.Get("/:slug/members", H.ListVaultMembers)
+	vaultsApi.Patch("/:slug/members/:user_slug", H.UpdateVaultMember)
+	vaultsApi.Delete("/:slug/members/:user_slug", H.RemoveVaultMember)
+	vaultsApi.Get("/:slug/key", H.RetrieveVaultKey)
+	vaultsApi.Put("/:slug/keys", H.SetVaultKeys)
+
+	usersApi.Put("/key_pair", H.SetKeyPair)
+	usersApi.Get("/key_pair", H.RetrieveKeyPair)
+
+	invitationsApi := api.Group("/invitations")
+	invitationsApi.Get("/", H.ListInvitations)
+	invitationsApi.Post("/:slug/accept", H.AcceptInvitation)
+	invitationsApi.Post("/:slug/decline", H.DeclineInvitation)
+
+	api.Get("/shared_vaults", H.ListSharedVaults)
+
+	orgsApi := api.Group("/orgs")
+	orgsApi.Post("/", H.CreateOrg)
+	orgsApi.Get("/", H.ListOrgs)
+	orgsApi.Post("/:slug/invitations", H.InviteOrgMember)
+	orgsApi.Delete("/:slug/invitations/:invitation_slug", H.RevokeOrgInvitation)
+	orgsApi.Get("/:slug/members", H.ListOrgMembers)
+	orgsApi.Patch("/:slug/members/:user_slug", H.UpdateOrgMember)
+	orgsApi.Delete("/:slug/members/:user_slug", H.RemoveOrgMember)
+	orgsApi.Post("/:slug/groups", H.CreateOrgGroup)
+	orgsApi.Delete("/:slug/groups/:group_slug", H.DeleteOrgGroup)
+	orgsApi.Put("/:slug/groups/:group_slug/members/:user_slug", H.AddOrgGroupMember)
+	orgsApi.Delete("/:slug/groups/:group_slug/members/:user_slug", H.RemoveOrgGroupMember)
+	orgsApi.Put("/:slug/groups/:group_slug/vaults/:vault_slug", H.SetOrgVaultGrant)
+	orgsApi.Delete("/:slug/groups/:group_slug/vaults/:vault_slug", H.RemoveOrgVaultGrant)
+	orgsApi.Get("/:slug/vaults", H.ListOrgVaults)
+
+	orgInvitationsApi := api.Group("/org_invitations")
+	orgInvitationsApi.Post("/:slug/accept", H.AcceptOrgInvitation)
+	orgInvitationsApi.Post("/:slug/decline", H.DeclineOrgInvitation)
+
+	emergencyContactsApi := api.Group("/emergency_contacts")
+	emergencyContactsApi.Post("/", H.AddEmergencyContact)
+	emergencyContactsApi.Get("/", H.ListEmergencyContacts)
+	emergencyContactsApi.Patch("/:slug", H.UpdateEmergencyContact)
+	emergencyContactsApi.Delete("/:slug", H.RemoveEmergencyContact)
+	emergencyContactsApi.Post("/:slug/reject", H.RejectEmergencyAccess)
+
+	api.Post("/emergency_access/:slug/request", H.RequestEmergencyAccess)
 
 	entriesApi := api.Group("/entries")
-	entriesApi.Post("/", H.VaultsCreateEntry)
-	entriesApi.Get("/:slug", H.VaultsRetrieveEntry)
-	entriesApi.Patch("/:slug", H.VaultsUpdateEntry)
-	entriesApi.Delete("/:slug", H.VaultsDeleteEntry)
+	entriesApi.Post("/", H.CheckVaultAccess, H.CheckIdempotencyKey, H.VaultsCreateEntry)
+	entriesApi.Post("/import", H.CheckVaultAccess, H.VaultsImportEntries)
+	entriesApi.Get("/:slug", H.CheckVaultAccess, H.VaultsRetrieveEntry)
+	entriesApi.Get("/:slug/totp", H.CheckVaultAccess, H.VaultsGetEntryTOTP)
+	entriesApi.Patch(
+		"/:slug", H.CheckVaultAccess, H.CheckIdempotencyKey, H.VaultsUpdateEntry, H.VaultsMoveEntry,
+	)
+	entriesApi.Delete("/:slug", H.CheckVaultAccess, H.VaultsDeleteEntry)
+	entriesApi.Post("/:slug/tags", H.CheckVaultAccess, H.VaultsAddTag)
+	entriesApi.Delete("/:slug/tags/:tag", H.CheckVaultAccess, H.VaultsRemoveTag)
+	entriesApi.Put("/:slug/favorite", H.CheckVaultAccess, H.VaultsSetFavorite)
+	entriesApi.Post(
+		"/:slug/attachments", H.CheckVaultAccess, H.CheckIdempotencyKey, H.VaultsUploadAttachment,
+	)
+	entriesApi.Get("/:slug/attachments", H.CheckVaultAccess, H.VaultsListAttachments)
+	entriesApi.Get(
+		"/:slug/attachments/:attachment_slug", H.CheckVaultAccess, H.VaultsDownloadAttachment,
+	)
+	entriesApi.Delete(
+		"/:slug/attachments/:attachment_slug", H.CheckVaultAccess, H.VaultsDeleteAttachment,
+	)
 
 	secretsApi := api.Group("/secrets")
-	secretsApi.Post("/", H.VaultsCreateSecret)
-	secretsApi.Patch("/:slug", H.VaultsUpdateSecret, H.VaultsMoveSecret)
-	secretsApi.Delete("/:slug", H.VaultsDeleteSecret)
+	secretsApi.Post("/", H.CheckVaultAccess, H.CheckIdempotencyKey, H.VaultsCreateSecret)
+	secretsApi.Patch(
+		"/:slug", H.CheckVaultAccess, H.CheckIdempotencyKey, H.VaultsUpdateSecret, H.VaultsMoveSecret,
+	)
+	secretsApi.Delete("/:slug", H.CheckVaultAccess, H.VaultsDeleteSecret)
+	secretsApi.Get("/:slug/versions", H.CheckVaultAccess, H.VaultsListSecretVersions)
+	secretsApi.Post(
+		"/:slug/versions/:version/restore", H.CheckVaultAccess, H.VaultsRestoreSecretVersion,
+	)
+
+	trashApi := api.Group("/trash")
+	trashApi.Get("/", H.CheckVaultAccess, H.VaultsListTrash)
+	trashApi.Delete("/", H.CheckVaultAccess, H.VaultsEmptyTrash)
+	trashApi.Post("/:type/:slug/restore", H.CheckVaultAccess, H.VaultsRestoreItem)
+
+	sharesApi.Post("/", H.CreateShare)
+	sharesApi.Get("/", H.ListShares)
+	sharesApi.Delete("/:slug", H.RevokeShare)
+
+	api.Post("/batch", H.CheckVaultAccess, H.VaultsBatch)
+	api.Get("/tags", H.CheckVaultAccess, H.VaultsListTags)
+	api.Get("/search", H.CheckVaultAccess, H.VaultsSearchEntries)
+	api.Get("/match", H.CheckVaultAccess, H.VaultsMatchURL)
+	api.Post("/export", H.CheckVaultAccess, H.VaultsExportVaults)
+	api.Get("/health_report", H.CheckVaultAccess, H.VaultsHealthReport)
+	api.Post("/generate_password", H.GeneratePassword)
+	api.Get("/breached/:prefix", H.CheckBreached)
 }
@@ -6,10 +6,12 @@ import (
 	"github.com/gofiber/fiber/v2"
 
 	"github.com/liobrdev/simplepasswords_api_gateway/app"
+	"github.com/liobrdev/simplepasswords_api_gateway/breached"
 	"github.com/liobrdev/simplepasswords_api_gateway/config"
 	"github.com/liobrdev/simplepasswords_api_gateway/databases"
 	"github.com/liobrdev/simplepasswords_api_gateway/routes"
 	testDBs "github.com/liobrdev/simplepasswords_api_gateway/tests/databases"
+	"github.com/liobrdev/simplepasswords_api_gateway/upstreams"
 )
 
 func TestApp(t *testing.T) {
@@ -25,7 +27,7 @@ func TestApp(t *testing.T) {
 		conf.BEHIND_PROXY = true
 		app := app.CreateApp(&conf)
 		dbs := testDBs.Init(&conf)
-		routes.Register(app, dbs, &conf)
+		routes.Register(app, dbs, upstreams.Init(&conf), breached.Init(&conf), &conf)
 		runTests(t, app, dbs, &conf)
 	})
 
@@ -35,7 +37,7 @@ func TestApp(t *testing.T) {
 		conf.BEHIND_PROXY = false
 		app := app.CreateApp(&conf)
 		dbs := testDBs.Init(&conf)
-		routes.Register(app, dbs, &conf)
+		routes.Register(app, dbs, upstreams.Init(&conf), breached.Init(&conf), &conf)
 		runTests(t, app, dbs, &conf)
 	})
 }
@@ -72,4 +74,96 @@ func runTests(t *testing.T, app *fiber.App, dbs *databases.Databases, conf *conf
 	t.Run("test_verify_email_try", func(t *testing.T) {
 		testVerifyEmailTry(t, app, dbs, conf)
 	})
+
+	t.Run("test_list_vaults", func(t *testing.T) {
+		testListVaults(t, app, dbs, conf)
+	})
+
+	t.Run("test_stream_vaults_response", func(t *testing.T) {
+		testStreamVaultsResponse(t, app, dbs, conf)
+	})
+
+	t.Run("test_search_entries", func(t *testing.T) {
+		testSearchEntries(t, app, dbs, conf)
+	})
+
+	t.Run("test_match_url", func(t *testing.T) {
+		testMatchURL(t, app, dbs, conf)
+	})
+
+	t.Run("test_import_entries", func(t *testing.T) {
+		testImportEntries(t, app, dbs, conf)
+	})
+
+	t.Run("test_export_vaults", func(t *testing.T) {
+		testExportVaults(t, app, dbs, conf)
+	})
+
+	t.Run("test_entry_totp", func(t *testing.T) {
+		testEntryTOTP(t, app, dbs, conf)
+	})
+
+	t.Run("test_secret_versions", func(t *testing.T) {
+		testSecretVersions(t, app, dbs, conf)
+	})
+
+	t.Run("test_trash", func(t *testing.T) {
+		testTrash(t, app, dbs, conf)
+	})
+
+	t.Run("test_conditional_requests", func(t *testing.T) {
+		testConditionalRequests(t, app, dbs, conf)
+	})
+
+	t.Run("test_batch", func(t *testing.T) {
+		testBatch(t, app, dbs, conf)
+	})
+
+	t.Run("test_move_entry", func(t *testing.T) {
+		testMoveEntry(t, app, dbs, conf)
+	})
+
+	t.Run("test_entry_types", func(t *testing.T) {
+		testEntryTypes(t, app, dbs, conf)
+	})
+
+	t.Run("test_tags", func(t *testing.T) {
+		testTags(t, app, dbs, conf)
+	})
+
+	t.Run("test_idempotency", func(t *testing.T) {
+		testIdempotency(t, app, dbs, conf)
+	})
+
+	t.Run("test_shares", func(t *testing.T) {
+		testShares(t, app, dbs, conf)
+	})
+
+	t.Run("test_attachments", func(t *testing.T) {
+		testAttachments(t, app, dbs, conf)
+	})
+
+	t.Run("test_vault_sharing", func(t *testing.T) {
+		testVaultSharing(t, app, dbs, conf)
+	})
+
+	t.Run("test_orgs", func(t *testing.T) {
+		testOrgs(t, app, dbs, conf)
+	})
+
+	t.Run("test_emergency_access", func(t *testing.T) {
+		testEmergencyAccess(t, app, dbs, conf)
+	})
+
+	t.Run("test_vault_health_report", func(t *testing.T) {
+		testVaultHealthReport(t, app, dbs, conf)
+	})
+
+	t.Run("test_generate_password", func(t *testing.T) {
+		testGeneratePassword(t, app, dbs, conf)
+	})
+
+	t.Run("test_check_breached", func(t *testing.T) {
+		testCheckBreached(t, app, dbs, conf)
+	})
 }
@@ -16,6 +16,20 @@ func SetUpApiGateway(t *testing.T, dbs *databases.Databases) {
 		&models.MFAToken{},
 		&models.EmailVerificationToken{},
 		&models.PhoneVerificationToken{},
+		&models.IdempotencyRecord{},
+		&models.Share{},
+		&models.UserKeyPair{},
+		&models.SharedVault{},
+		&models.VaultMember{},
+		&models.VaultInvitation{},
+		&models.Organization{},
+		&models.OrgMember{},
+		&models.OrgGroup{},
+		&models.OrgGroupMember{},
+		&models.OrgVaultGrant{},
+		&models.OrgInvitation{},
+		&models.EmergencyContact{},
+		&models.EmergencyContactVault{},
 	); err != nil {
 		t.Fatalf("Failed database auto-migrate: %s", err.Error())
 	}
@@ -57,6 +71,25 @@ func TearDownApiGateway(t *testing.T, dbs *databases.Databases) {
 	result.Error != nil {
 		t.Fatalf("Test database tear-down failed: %s", result.Error.Error())
 	}
+
+	if result := dbs.ApiGateway.Exec("DROP TABLE IF EXISTS idempotency_records");
+	result.Error != nil {
+		t.Fatalf("Test database tear-down failed: %s", result.Error.Error())
+	}
+
+	if result := dbs.ApiGateway.Exec("DROP TABLE IF EXISTS shares"); result.Error != nil {
+		t.Fatalf("Test database tear-down failed: %s", result.Error.Error())
+	}
+
+	for _, table := range []string{
+		"user_key_pairs", "vault_invitations", "vault_members", "shared_vaults",
+		"org_invitations", "org_vault_grants", "org_group_members", "org_groups", "org_members",
+		"organizations", "emergency_contact_vaults", "emergency_contacts",
+	} {
+		if result := dbs.ApiGateway.Exec("DROP TABLE IF EXISTS " + table); result.Error != nil {
+			t.Fatalf("Test database tear-down failed: %s", result.Error.Error())
+		}
+	}
 }
 
 func TearDownLogger(t *testing.T, dbs *databases.Databases) {
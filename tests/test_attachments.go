@@ -0,0 +1,162 @@
+package tests
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/config"
+	"github.com/liobrdev/simplepasswords_api_gateway/databases"
+	"github.com/liobrdev/simplepasswords_api_gateway/models"
+	"github.com/liobrdev/simplepasswords_api_gateway/tests/helpers"
+	"github.com/liobrdev/simplepasswords_api_gateway/tests/setup"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+func testAttachments(
+	t *testing.T, app *fiber.App, dbs *databases.Databases, conf *config.AppConfig,
+) {
+	var clientIP string
+
+	if conf.BEHIND_PROXY {
+		clientIP = helpers.CLIENT_IP
+	} else {
+		clientIP = "0.0.0.0"
+	}
+
+	setup.SetUpLogger(t, dbs)
+	user := setup.SetUpApiGatewayWithData(t, dbs)
+	validTokens := setup.CreateValidTestClientSessions(&user, t, dbs, conf)
+	slug := helpers.NewSlug(t)
+	target := "/api/entries/" + slug + "/attachments"
+
+	upload := func(t *testing.T, fieldName string, content []byte) int {
+		var body bytes.Buffer
+		writer := multipart.NewWriter(&body)
+
+		if fieldName != "" {
+			part, err := writer.CreateFormFile(fieldName, "recovery.pdf")
+			require.NoError(t, err)
+			_, err = part.Write(content)
+			require.NoError(t, err)
+		}
+
+		require.NoError(t, writer.Close())
+
+		req := httptest.NewRequest("POST", target, &body)
+		req.Header.Set("Authorization", "Token " + validTokens[0])
+		req.Header.Set("Client-Operation", utils.UploadAttachment)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		req.Header.Set("X-Forwarded-For", helpers.CLIENT_IP)
+		req.Header.Set(conf.PASSWORD_HEADER_KEY, helpers.HexHash1)
+
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+
+		return resp.StatusCode
+	}
+
+	assertLog := func(t *testing.T, level, message, detail string) {
+		var actualLog models.Log
+		helpers.QueryTestLogLatest(t, dbs.Logger, &actualLog)
+		require.Equal(t, clientIP, actualLog.ClientIP)
+		require.Equal(t, utils.UploadAttachment, actualLog.ClientOperation)
+		require.Equal(t, level, actualLog.Level)
+		require.Equal(t, message, actualLog.Message)
+		require.Equal(t, user.Slug, actualLog.UserSlug)
+
+		if detail != "" {
+			require.Equal(t, detail, actualLog.Detail)
+		}
+	}
+
+	t.Run("upload_missing_file_400_bad_request", func(t *testing.T) {
+		require.Equal(t, 400, upload(t, "", nil))
+		assertLog(t, "warn", utils.ErrorAttachment, "")
+		require.Equal(t, 400, upload(t, "attachment", []byte("%PDF-1.7")))
+	})
+
+	t.Run("upload_empty_400_bad_request", func(t *testing.T) {
+		require.Equal(t, 400, upload(t, "file", nil))
+		assertLog(t, "warn", utils.ErrorAttachment, "0")
+	})
+
+	t.Run("upload_html_415_unsupported_media_type", func(t *testing.T) {
+		require.Equal(t, 415, upload(t, "file", []byte("<html><script>alert(1)</script>")))
+		assertLog(t, "warn", utils.ErrorAttachmentType, "text/html; charset=utf-8")
+	})
+
+	t.Run("upload_too_large_413_request_entity_too_large", func(t *testing.T) {
+		content := make([]byte, utils.AttachmentMaxSize + 1)
+		copy(content, "%PDF-1.7")
+
+		require.Equal(t, 413, upload(t, "file", content))
+		assertLog(t, "warn", utils.ErrorAttachmentSize, "10485761")
+	})
+
+	t.Run("other_route_over_default_body_limit_413_request_entity_too_large", func(t *testing.T) {
+		reqBody := strings.Repeat("a", fiber.DefaultBodyLimit + 1)
+		req := httptest.NewRequest(
+			"POST", "/api/entries/" + slug + "/tags", strings.NewReader(reqBody),
+		)
+		req.Header.Set("Authorization", "Token " + validTokens[0])
+		req.Header.Set("Client-Operation", utils.AddTag)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Forwarded-For", helpers.CLIENT_IP)
+
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+		require.Equal(t, 413, resp.StatusCode)
+
+		helpers.AssertErrorResponseBody(t, resp, &utils.ErrorResponseBody{
+			Detail: utils.ErrorRequestTooLarge,
+		})
+
+		var actualLog models.Log
+		helpers.QueryTestLogLatest(t, dbs.Logger, &actualLog)
+		helpers.AssertLog(t, &models.Log{
+			ClientIP:        clientIP,
+			ClientOperation: utils.AddTag,
+			Detail:          "4194305",
+			Extra:           "/api/entries/" + slug + "/tags",
+			Level:           "warn",
+			Message:         utils.ErrorBodyLimit,
+		}, &actualLog)
+	})
+
+	t.Run("upload_vaults_unavailable_503_service_unavailable", func(t *testing.T) {
+		require.Equal(t, 503, upload(t, "file", []byte("%PDF-1.7\nrecovery codes")))
+		assertLog(t, "error", utils.ErrorVaultsUploadAttachment, "")
+	})
+
+	t.Run("download_without_password_401_unauthorized", func(t *testing.T) {
+		req := httptest.NewRequest("GET", target + "/" + helpers.NewSlug(t), nil)
+		req.Header.Set("Authorization", "Token " + validTokens[0])
+		req.Header.Set("Client-Operation", utils.DownloadAttachment)
+		req.Header.Set("X-Forwarded-For", helpers.CLIENT_IP)
+
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+		require.Equal(t, 401, resp.StatusCode)
+	})
+
+	t.Run("download_invalid_slug_400_bad_request", func(t *testing.T) {
+		testClientOperationHeaderError(
+			t, app, dbs, conf, "GET", target + "/not-a-slug", "Token " + validTokens[0],
+			utils.DownloadAttachment, 400, utils.ErrorBadRequest, nil, nil, &models.Log{
+				ClientIP:        clientIP,
+				ClientOperation: utils.DownloadAttachment,
+				Detail:          slug,
+				Extra:           "not-a-slug",
+				Level:           "warn",
+				Message:         utils.ErrorParams,
+				UserSlug:        user.Slug,
+			},
+		)
+	})
+}
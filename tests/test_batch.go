@@ -0,0 +1,202 @@
+package tests
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/config"
+	"github.com/liobrdev/simplepasswords_api_gateway/controllers"
+	"github.com/liobrdev/simplepasswords_api_gateway/databases"
+	"github.com/liobrdev/simplepasswords_api_gateway/models"
+	"github.com/liobrdev/simplepasswords_api_gateway/tests/helpers"
+	"github.com/liobrdev/simplepasswords_api_gateway/tests/setup"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+func testBatch(t *testing.T, app *fiber.App, dbs *databases.Databases, conf *config.AppConfig) {
+	var clientIP string
+
+	if conf.BEHIND_PROXY {
+		clientIP = helpers.CLIENT_IP
+	} else {
+		clientIP = "0.0.0.0"
+	}
+
+	setup.SetUpLogger(t, dbs)
+	user := setup.SetUpApiGatewayWithData(t, dbs)
+	validTokens := setup.CreateValidTestClientSessions(&user, t, dbs, conf)
+	slug := helpers.NewSlug(t)
+
+	type invalidBatch struct {
+		body        string
+		count       string
+		fieldErrors map[string][]string
+	}
+
+	invalidBatches := []invalidBatch{
+		{`{"operations":[]}`, "0", map[string][]string{
+			"operations": {"Must contain 1 to 100 operations"},
+		}},
+		{`{"operations":[{"op":"retrieve_entry","slug":"` + slug + `"}]}`, "1", map[string][]string{
+			"operations[0].op": {"Unsupported operation"},
+		}},
+		{`{"operations":[{"op":"move_secret","slug":"` + slug + `","if_match":"\"1\"",` +
+		`"body":{"entry_slug":"` + slug + `"}},` +
+		`{"op":"delete_entry","if_match":"\"1\""}]}`, "2",
+		map[string][]string{
+			"operations[1].slug": {"Invalid slug"},
+		}},
+		{`{"operations":[{"op":"create_vault","slug":"` + slug + `","body":{}}]}`, "1",
+		map[string][]string{
+			"operations[0].slug": {"Must be empty"},
+		}},
+		{`{"operations":[{"op":"update_entry","slug":"` + slug + `","body":{}}]}`, "1",
+		map[string][]string{
+			"operations[0].if_match": {"Must be the item's current ETag"},
+		}},
+		{`{"atomic":true,"operations":[{"op":"delete_secret","slug":"` + slug + `","if_match":"*"},` +
+		`{"op":"create_vault","body":{"vault_title":"New"}}]}`, "2", map[string][]string{
+			"atomic": {"Create operations can't run in an atomic batch"},
+		}},
+		{`{"operations":[{"op":"create_secret","body":{"secret_label":"2FA",` +
+		`"secret_string":"nope","secret_kind":"totp"}}]}`, "1", map[string][]string{
+			"operations[0].body.secret_string": {"Must be an otpauth://totp/ URI or base32 seed"},
+		}},
+	}
+
+	for _, batch := range invalidBatches {
+		t.Run("invalid_batch_400_bad_request", func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/api/batch", strings.NewReader(batch.body))
+			req.Header.Set("Authorization", "Token " + validTokens[0])
+			req.Header.Set("Client-Operation", utils.Batch)
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("X-Forwarded-For", helpers.CLIENT_IP)
+			req.Header.Set(conf.PASSWORD_HEADER_KEY, helpers.HexHash1)
+
+			resp, err := app.Test(req, -1)
+			require.NoError(t, err)
+			require.Equal(t, 400, resp.StatusCode)
+
+			helpers.AssertErrorResponseBody(t, resp, &utils.ErrorResponseBody{
+				Detail:      utils.ErrorBadRequest,
+				FieldErrors: batch.fieldErrors,
+			})
+
+			var actualLog models.Log
+			helpers.QueryTestLogLatest(t, dbs.Logger, &actualLog)
+			helpers.AssertLog(t, &models.Log{
+				ClientIP:        clientIP,
+				ClientOperation: utils.Batch,
+				Detail:          batch.count,
+				Level:           "warn",
+				Message:         utils.ErrorBatch,
+				RequestBody:     "[redacted]",
+				UserSlug:        user.Slug,
+			}, &actualLog)
+		})
+	}
+
+	missingSlug, staleSlug := helpers.NewSlug(t), helpers.NewSlug(t)
+	var mu sync.Mutex
+	var paths, bodies []string
+
+	stubApp := newVaultsStubApp(t, dbs, conf, conf.VAULTS_MAX_RESPONSE_BYTES,
+		func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			paths = append(paths, r.Method + " " + r.URL.Path)
+			body, _ := io.ReadAll(r.Body)
+			bodies = append(bodies, string(body))
+			mu.Unlock()
+
+			if strings.HasSuffix(r.URL.Path, missingSlug) {
+				w.WriteHeader(404)
+			} else if strings.HasSuffix(r.URL.Path, staleSlug) {
+				w.WriteHeader(412)
+			} else {
+				w.WriteHeader(204)
+			}
+		},
+	)
+
+	doBatch := func(t *testing.T, body string) (int, controllers.BatchResponseBody) {
+		mu.Lock()
+		paths, bodies = nil, nil
+		mu.Unlock()
+
+		req := httptest.NewRequest("POST", "/api/batch", strings.NewReader(body))
+		req.Header.Set("Authorization", "Token " + validTokens[0])
+		req.Header.Set("Client-Operation", utils.Batch)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Forwarded-For", helpers.CLIENT_IP)
+		req.Header.Set(conf.PASSWORD_HEADER_KEY, helpers.HexHash1)
+
+		resp, err := stubApp.Test(req, -1)
+		require.NoError(t, err)
+
+		var respBody controllers.BatchResponseBody
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&respBody))
+
+		return resp.StatusCode, respBody
+	}
+
+	deletes := `[{"op":"delete_secret","slug":"` + slug + `","if_match":"*"},` +
+		`{"op":"delete_secret","slug":"` + missingSlug + `","if_match":"*"},` +
+		`{"op":"delete_entry","slug":"` + staleSlug + `","if_match":"\"1\""}]`
+
+	t.Run("not_atomic_unless_asked_207_multi_status", func(t *testing.T) {
+		status, respBody := doBatch(t, `{"operations":` + deletes + `}`)
+		require.Equal(t, 207, status)
+		require.False(t, respBody.Atomic)
+
+		require.Equal(t, []controllers.BatchResult{
+			{Index: 0, Op: utils.DeleteSecret, Status: 204},
+			{Index: 1, Op: utils.DeleteSecret, Status: 404, Error: utils.ErrorNotFound},
+			{Index: 2, Op: utils.DeleteEntry, Status: 412, Error: utils.ErrorPreconditionFailed},
+		}, respBody.Results)
+
+		require.Equal(t, []string{
+			"DELETE /api/secrets/" + slug,
+			"DELETE /api/secrets/" + missingSlug,
+			"DELETE /api/entries/" + staleSlug,
+		}, paths)
+	})
+
+	t.Run("atomic_when_asked_200_ok", func(t *testing.T) {
+		status, respBody := doBatch(t, `{"atomic":true,"operations":` + deletes + `}`)
+		require.Equal(t, 200, status)
+		require.True(t, respBody.Atomic)
+		require.Len(t, respBody.Results, 3)
+		require.Equal(t, []string{"POST /api/batch"}, paths)
+	})
+
+	t.Run("bodies_rebuilt_from_typed_requests", func(t *testing.T) {
+		status, _ := doBatch(t, `{"operations":[` +
+			`{"op":"create_vault","body":{"vault_title":"New","owner":"x"}},` +
+			`{"op":"move_secret","slug":"` + slug + `","if_match":"*",` +
+			`"body":{"entry_slug":"` + slug + `","vault_slug":"x"}},` +
+			`{"op":"delete_entry","slug":"` + slug + `","if_match":"*","body":{"hard":true}}]}`,
+		)
+		require.Equal(t, 200, status)
+		require.Equal(t, []string{
+			`{"user_slug":"","vault_title":"New"}`,
+			`{"secret_priority":"","entry_slug":"` + slug + `"}`,
+			"",
+		}, bodies)
+
+		status, _ = doBatch(t, `{"atomic":true,"operations":[` +
+			`{"op":"update_vault","slug":"` + slug + `","if_match":"*",` +
+			`"body":{"vault_title":"Renamed","user_slug":"x"}}]}`,
+		)
+		require.Equal(t, 200, status)
+		require.Len(t, bodies, 1)
+		require.Contains(t, bodies[0], `"body":{"vault_title":"Renamed"}`)
+	})
+}
@@ -0,0 +1,87 @@
+package tests
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/breached"
+	"github.com/liobrdev/simplepasswords_api_gateway/config"
+	"github.com/liobrdev/simplepasswords_api_gateway/controllers"
+	"github.com/liobrdev/simplepasswords_api_gateway/databases"
+	"github.com/liobrdev/simplepasswords_api_gateway/models"
+	"github.com/liobrdev/simplepasswords_api_gateway/tests/helpers"
+	"github.com/liobrdev/simplepasswords_api_gateway/tests/setup"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+func testCheckBreached(
+	t *testing.T, app *fiber.App, dbs *databases.Databases, conf *config.AppConfig,
+) {
+	var clientIP string
+
+	if conf.BEHIND_PROXY {
+		clientIP = helpers.CLIENT_IP
+	} else {
+		clientIP = "0.0.0.0"
+	}
+
+	setup.SetUpLogger(t, dbs)
+	user := setup.SetUpApiGatewayWithData(t, dbs)
+	validTokens := setup.CreateValidTestClientSessions(&user, t, dbs, conf)
+
+	sum := sha1.Sum([]byte("password"))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	prefix, suffix := hash[:breached.PrefixLength], hash[breached.PrefixLength:]
+
+	for _, invalidPrefix := range []string{"ABCD", "ABCDEF", "ABCDG"} {
+		t.Run("invalid_prefix_" + invalidPrefix + "_400_bad_request", func(t *testing.T) {
+			testClientOperationHeaderError(
+				t, app, dbs, conf, "GET", "/api/breached/" + invalidPrefix,
+				"Token " + validTokens[0], utils.CheckBreached, 400, utils.ErrorBadRequest,
+				map[string][]string{"prefix": {"Must be 5 hexadecimal characters"}}, nil,
+				&models.Log{
+					ClientIP:        clientIP,
+					ClientOperation: utils.CheckBreached,
+					Detail:          invalidPrefix,
+					Level:           "warn",
+					Message:         utils.ErrorParams,
+					UserSlug:				 user.Slug,
+				},
+			)
+		})
+	}
+
+	t.Run("range_file_200_ok", func(t *testing.T) {
+		path := filepath.Join(conf.BREACHED_DATA_DIR, prefix + ".txt")
+		require.NoError(t, os.WriteFile(
+			path, []byte("003D68EB55068C33ACE09247EE4C639306B:3\r\n" + suffix + ":9545824\r\n"), 0600,
+		))
+		t.Cleanup(func() { os.Remove(path) })
+
+		req := httptest.NewRequest("GET", "/api/breached/" + strings.ToLower(prefix), nil)
+		req.Header.Set("Authorization", "Token " + validTokens[0])
+		req.Header.Set("Client-Operation", utils.CheckBreached)
+		req.Header.Set("X-Forwarded-For", helpers.CLIENT_IP)
+
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+		require.Equal(t, 200, resp.StatusCode)
+
+		var respBody controllers.CheckBreachedResponseBody
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&respBody))
+		require.Equal(t, prefix, respBody.Prefix)
+		require.Equal(t, []breached.Match{
+			{Suffix: "003D68EB55068C33ACE09247EE4C639306B", Count: 3},
+			{Suffix: suffix, Count: 9545824},
+		}, respBody.Matches)
+	})
+}
@@ -47,23 +47,95 @@ func testClientOperationHeader(
 		"create_entry":					{"POST", "/api/entries"},
 		"retrieve_entry":				{"GET", "/api/entries/" + dummySlug},
 		"update_entry":					{"PATCH", "/api/entries/" + dummySlug},
+		"move_entry":						{"PATCH", "/api/entries/" + dummySlug},
 		"delete_entry":					{"DELETE", "/api/entries/" + dummySlug},
+		"get_entry_totp":				{"GET", "/api/entries/" + dummySlug + "/totp"},
+		"add_tag":							{"POST", "/api/entries/" + dummySlug + "/tags"},
+		"remove_tag":						{"DELETE", "/api/entries/" + dummySlug + "/tags/work"},
+		"set_favorite":					{"PUT", "/api/entries/" + dummySlug + "/favorite"},
+		"upload_attachment":		{"POST", "/api/entries/" + dummySlug + "/attachments"},
+		"list_attachments":			{"GET", "/api/entries/" + dummySlug + "/attachments"},
+		"download_attachment":	{"GET", "/api/entries/" + dummySlug + "/attachments/" + dummySlug},
+		"delete_attachment":		{"DELETE", "/api/entries/" + dummySlug + "/attachments/" + dummySlug},
+		"set_key_pair":					{"PUT", "/api/users/key_pair"},
+		"retrieve_key_pair":		{"GET", "/api/users/key_pair"},
+		"invite_vault_member":	{"POST", "/api/vaults/" + dummySlug + "/invitations"},
+		"revoke_invitation":		{"DELETE", "/api/vaults/" + dummySlug + "/invitations/" + dummySlug},
+		"list_invitations":			{"GET", "/api/invitations"},
+		"accept_invitation":		{"POST", "/api/invitations/" + dummySlug + dummySlug + "/accept"},
+		"decline_invitation":		{"POST", "/api/invitations/" + dummySlug + dummySlug + "/decline"},
+		"list_shared_vaults":		{"GET", "/api/shared_vaults"},
+		"list_vault_members":		{"GET", "/api/vaults/" + dummySlug + "/members"},
+		"update_vault_member":	{"PATCH", "/api/vaults/" + dummySlug + "/members/" + dummySlug},
+		"remove_vault_member":	{"DELETE", "/api/vaults/" + dummySlug + "/members/" + dummySlug},
+		"retrieve_vault_key":		{"GET", "/api/vaults/" + dummySlug + "/key"},
+		"set_vault_keys":				{"PUT", "/api/vaults/" + dummySlug + "/keys"},
+		"create_org":						{"POST", "/api/orgs"},
+		"list_orgs":						{"GET", "/api/orgs"},
+		"invite_org_member":		{"POST", "/api/orgs/" + dummySlug + "/invitations"},
+		"revoke_org_invitation": {"DELETE", "/api/orgs/" + dummySlug + "/invitations/" + dummySlug},
+		"accept_org_invitation": {"POST", "/api/org_invitations/" + dummySlug + "/accept"},
+		"decline_org_invitation": {"POST", "/api/org_invitations/" + dummySlug + "/decline"},
+		"list_org_members":			{"GET", "/api/orgs/" + dummySlug + "/members"},
+		"update_org_member":		{"PATCH", "/api/orgs/" + dummySlug + "/members/" + dummySlug},
+		"remove_org_member":		{"DELETE", "/api/orgs/" + dummySlug + "/members/" + dummySlug},
+		"create_org_group":			{"POST", "/api/orgs/" + dummySlug + "/groups"},
+		"delete_org_group":			{"DELETE", "/api/orgs/" + dummySlug + "/groups/" + dummySlug},
+		"add_org_group_member":	{"PUT", "/api/orgs/" + dummySlug + "/groups/g/members/" + dummySlug},
+		"remove_org_group_member": {"DELETE", "/api/orgs/" + dummySlug + "/groups/g/members/u"},
+		"set_org_vault_grant":	{"PUT", "/api/orgs/" + dummySlug + "/groups/g/vaults/" + dummySlug},
+		"remove_org_vault_grant": {"DELETE", "/api/orgs/" + dummySlug + "/groups/g/vaults/v"},
+		"list_org_vaults":			{"GET", "/api/orgs/" + dummySlug + "/vaults"},
+		"add_emergency_contact": {"POST", "/api/emergency_contacts"},
+		"list_emergency_contacts": {"GET", "/api/emergency_contacts"},
+		"update_emergency_contact": {"PATCH", "/api/emergency_contacts/" + dummySlug},
+		"remove_emergency_contact": {"DELETE", "/api/emergency_contacts/" + dummySlug},
+		"request_emergency_access": {"POST", "/api/emergency_access/" + dummySlug + "/request"},
+		"reject_emergency_access": {"POST", "/api/emergency_contacts/" + dummySlug + "/reject"},
+		"list_tags":						{"GET", "/api/tags"},
 		"create_secret":				{"POST", "/api/secrets"},
 		"update_secret":				{"PATCH", "/api/secrets/" + dummySlug},
 		"delete_secret":				{"DELETE", "/api/secrets/" + dummySlug},
+		"list_secret_versions":	{"GET", "/api/secrets/" + dummySlug + "/versions"},
+		"restore_secret_version": {"POST", "/api/secrets/" + dummySlug + "/versions/1/restore"},
+		"list_trash":						{"GET", "/api/trash"},
+		"restore_item":					{"POST", "/api/trash/entry/" + dummySlug + "/restore"},
+		"empty_trash":					{"DELETE", "/api/trash"},
+		"batch":								{"POST", "/api/batch"},
+		"create_share":					{"POST", "/api/shares"},
+		"list_shares":					{"GET", "/api/shares"},
+		"revoke_share":					{"DELETE", "/api/shares/" + dummySlug + dummySlug},
+		"open_share":						{"POST", "/api/shares/" + dummySlug + dummySlug + "/open"},
+		"search_entries":				{"GET", "/api/search?q=bank"},
+		"match_url":						{"GET", "/api/match?url=https%3A%2F%2Fexample.com"},
+		"import_entries":				{"POST", "/api/entries/import"},
+		"export_vaults":				{"POST", "/api/export"},
+		"vault_health_report":	{"GET", "/api/health_report"},
+		"generate_password":		{"POST", "/api/generate_password"},
+		"check_breached":				{"GET", "/api/breached/ABCDE"},
 	}
 
 	setup.SetUpLogger(t, dbs)
 	user := setup.SetUpApiGatewayWithData(t, dbs)
 	validTokens := setup.CreateValidTestClientSessions(&user, t, dbs, conf)
 
+	// Operations sharing a route with another are rejected by the route's first handler, which
+	// logs its own operation
+	loggedOperations := map[string]string{"move_entry": "update_entry"}
+
 	for operation, route := range clientOperations {
+		loggedOperation, ok := loggedOperations[operation]
+
+		if !ok {
+			loggedOperation = operation
+		}
+
 		t.Run("wrong_client_operation_" + operation + "_400_bad_request", func(t *testing.T) {
 			testClientOperationHeaderError(
 				t, app, dbs, conf, route[0], route[1], "Token " + validTokens[0], "wrong_operation",
 				400, utils.ErrorBadRequest, nil, nil, &models.Log{
 					ClientIP:        clientIP,
-					ClientOperation: operation,
+					ClientOperation: loggedOperation,
 					Detail:          "wrong_operation",
 					Level:           "warn",
 					Message:         utils.ErrorClientOperation,
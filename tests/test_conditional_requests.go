@@ -0,0 +1,122 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/config"
+	"github.com/liobrdev/simplepasswords_api_gateway/databases"
+	"github.com/liobrdev/simplepasswords_api_gateway/models"
+	"github.com/liobrdev/simplepasswords_api_gateway/tests/helpers"
+	"github.com/liobrdev/simplepasswords_api_gateway/tests/setup"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+func testConditionalRequests(
+	t *testing.T, app *fiber.App, dbs *databases.Databases, conf *config.AppConfig,
+) {
+	var clientIP string
+
+	if conf.BEHIND_PROXY {
+		clientIP = helpers.CLIENT_IP
+	} else {
+		clientIP = "0.0.0.0"
+	}
+
+	setup.SetUpLogger(t, dbs)
+	user := setup.SetUpApiGatewayWithData(t, dbs)
+	validTokens := setup.CreateValidTestClientSessions(&user, t, dbs, conf)
+	slug := helpers.NewSlug(t)
+
+	deleteOps := map[string]string{
+		utils.DeleteVault:  "/api/vaults/",
+		utils.DeleteEntry:  "/api/entries/",
+		utils.DeleteSecret: "/api/secrets/",
+	}
+
+	for operation, path := range deleteOps {
+		t.Run(operation + "_missing_if_match_428_precondition_required", func(t *testing.T) {
+			testClientOperationHeaderError(
+				t, app, dbs, conf, "DELETE", path + slug, "Token " + validTokens[0], operation, 428,
+				utils.ErrorPreconditionRequired, nil, nil, &models.Log{
+					ClientIP:        clientIP,
+					ClientOperation: operation,
+					Extra:           slug,
+					Level:           "warn",
+					Message:         utils.ErrorIfMatch,
+				},
+			)
+		})
+	}
+
+	updateOps := map[string]string{
+		utils.UpdateVault:  "/api/vaults/",
+		utils.UpdateEntry:  "/api/entries/",
+		utils.UpdateSecret: "/api/secrets/",
+		utils.MoveSecret:   "/api/secrets/",
+		utils.MoveEntry:    "/api/entries/",
+	}
+
+	for operation, path := range updateOps {
+		t.Run(operation + "_invalid_if_match_428_precondition_required", func(t *testing.T) {
+			req := httptest.NewRequest("PATCH", path + slug, strings.NewReader("{}"))
+			req.Header.Set("Authorization", "Token " + validTokens[0])
+			req.Header.Set("Client-Operation", operation)
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("If-Match", "abc")
+			req.Header.Set("X-Forwarded-For", helpers.CLIENT_IP)
+			req.Header.Set(conf.PASSWORD_HEADER_KEY, helpers.HexHash1)
+
+			resp, err := app.Test(req, -1)
+			require.NoError(t, err)
+			require.Equal(t, 428, resp.StatusCode)
+
+			helpers.AssertErrorResponseBody(t, resp, &utils.ErrorResponseBody{
+				Detail: utils.ErrorPreconditionRequired,
+			})
+
+			var actualLog models.Log
+			helpers.QueryTestLogLatest(t, dbs.Logger, &actualLog)
+			helpers.AssertLog(t, &models.Log{
+				ClientIP:        clientIP,
+				ClientOperation: operation,
+				Detail:          "abc",
+				Extra:           slug,
+				Level:           "warn",
+				Message:         utils.ErrorIfMatch,
+				RequestBody:     "{}",
+			}, &actualLog)
+		})
+	}
+
+	t.Run("stale_if_match_412_precondition_failed", func(t *testing.T) {
+		stubApp := newVaultsStubApp(t, dbs, conf, conf.VAULTS_MAX_RESPONSE_BYTES,
+			func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("ETag", `"5"`)
+				w.WriteHeader(412)
+			},
+		)
+
+		req := httptest.NewRequest("PATCH", "/api/secrets/" + slug, strings.NewReader("{}"))
+		req.Header.Set("Authorization", "Token " + validTokens[0])
+		req.Header.Set("Client-Operation", utils.UpdateSecret)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("If-Match", `"4"`)
+		req.Header.Set("X-Forwarded-For", helpers.CLIENT_IP)
+		req.Header.Set(conf.PASSWORD_HEADER_KEY, helpers.HexHash1)
+
+		resp, err := stubApp.Test(req, -1)
+		require.NoError(t, err)
+		require.Equal(t, 412, resp.StatusCode)
+		require.Equal(t, `"5"`, resp.Header.Get("ETag"))
+
+		helpers.AssertErrorResponseBody(t, resp, &utils.ErrorResponseBody{
+			Detail: utils.ErrorPreconditionFailed,
+		})
+	})
+}
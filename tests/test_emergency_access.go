@@ -0,0 +1,379 @@
+package tests
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/config"
+	"github.com/liobrdev/simplepasswords_api_gateway/controllers"
+	"github.com/liobrdev/simplepasswords_api_gateway/databases"
+	"github.com/liobrdev/simplepasswords_api_gateway/models"
+	"github.com/liobrdev/simplepasswords_api_gateway/tests/helpers"
+	"github.com/liobrdev/simplepasswords_api_gateway/tests/setup"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+func testEmergencyAccess(
+	t *testing.T, app *fiber.App, dbs *databases.Databases, conf *config.AppConfig,
+) {
+	setup.SetUpLogger(t, dbs)
+	grantor := setup.SetUpApiGatewayWithData(t, dbs)
+	grantorTokens := setup.CreateValidTestClientSessions(&grantor, t, dbs, conf)
+
+	hash, salt, err := utils.GenerateUserCredentials(
+		utils.HashToken(helpers.VALID_EMAIL_2 + helpers.VALID_PW_2),
+	)
+	require.NoError(t, err)
+
+	contactUser := models.User{
+		Slug:            helpers.NewSlug(t),
+		Name:            helpers.VALID_NAME_2,
+		EmailAddress:    helpers.VALID_EMAIL_2,
+		PhoneNumber:     helpers.VALID_PHONE_2,
+		PasswordHash:    hash,
+		PasswordSalt:    salt,
+		EmailIsVerified: true,
+		PhoneIsVerified: true,
+	}
+	require.NoError(t, dbs.ApiGateway.Create(&contactUser).Error)
+	contactTokens := setup.CreateValidTestClientSessions(&contactUser, t, dbs, conf)
+
+	grantorAuth := "Token " + grantorTokens[0]
+	contactAuth := "Token " + contactTokens[0]
+	passwords := map[string]string{grantorAuth: helpers.HexHash1, contactAuth: helpers.HexHash2}
+
+	doRequest := func(
+		t *testing.T, method, target, clientOperation, authHeader, contactSlug, body string,
+	) (*http.Response, []byte) {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set("Authorization", authHeader)
+		req.Header.Set("Client-Operation", clientOperation)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Forwarded-For", helpers.CLIENT_IP)
+		req.Header.Set(conf.PASSWORD_HEADER_KEY, passwords[authHeader])
+
+		if contactSlug != "" {
+			req.Header.Set("Emergency-Contact-Slug", contactSlug)
+		}
+
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+
+		respBody, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		return resp, respBody
+	}
+
+	assertLogMessage := func(t *testing.T, clientOperation, message string) {
+		var actualLog models.Log
+		helpers.QueryTestLogLatest(t, dbs.Logger, &actualLog)
+		require.Equal(t, clientOperation, actualLog.ClientOperation)
+		require.Equal(t, message, actualLog.Message)
+	}
+
+	t.Run("emergency_access_opens_at", func(t *testing.T) {
+		requestedAt := time.Date(2024, 1, 30, 12, 0, 0, 0, time.UTC)
+		require.Equal(
+			t, time.Date(2024, 2, 6, 12, 0, 0, 0, time.UTC),
+			utils.EmergencyAccessOpensAt(requestedAt, 7),
+		)
+		require.Nil(t, utils.ValidateWaitingDays(utils.EmergencyWaitingDaysMax))
+		require.NotNil(t, utils.ValidateWaitingDays(utils.EmergencyWaitingDaysMax + 1))
+		require.NotNil(t, utils.ValidateWaitingDays(0))
+	})
+
+	t.Run("add_invalid_400_bad_request", func(t *testing.T) {
+		resp, respBody := doRequest(
+			t, "POST", "/api/emergency_contacts", utils.AddEmergencyContact, grantorAuth, "",
+			`{"email_address":"` + helpers.VALID_EMAIL_1 + `","waiting_days":0}`,
+		)
+		require.Equal(t, 400, resp.StatusCode)
+		require.Contains(t, string(respBody), "You can't be your own emergency contact")
+		require.Contains(t, string(respBody), "Must be from 1 to 90 days")
+		assertLogMessage(t, utils.AddEmergencyContact, utils.ErrorEmergencyContact)
+	})
+
+	addBody := `{"email_address":"` + helpers.VALID_EMAIL_2 + `","waiting_days":3}`
+
+	t.Run("add_without_password_401_unauthorized", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/api/emergency_contacts", strings.NewReader(addBody))
+		req.Header.Set("Authorization", grantorAuth)
+		req.Header.Set("Client-Operation", utils.AddEmergencyContact)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Forwarded-For", helpers.CLIENT_IP)
+
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+		require.Equal(t, 401, resp.StatusCode)
+	})
+
+	var contact controllers.EmergencyContactResponse
+
+	t.Run("add_201_created", func(t *testing.T) {
+		resp, respBody := doRequest(
+			t, "POST", "/api/emergency_contacts", utils.AddEmergencyContact, grantorAuth, "",
+			addBody,
+		)
+		require.Equal(t, 201, resp.StatusCode)
+		require.NoError(t, json.Unmarshal(respBody, &contact))
+		require.Equal(t, helpers.VALID_EMAIL_2, contact.EmailAddress)
+		require.Equal(t, 3, contact.WaitingDays)
+		require.Empty(t, contact.VaultSlugs)
+		require.Nil(t, contact.RequestedAt)
+		assertLogMessage(t, utils.AddEmergencyContact, utils.InfoEmergencyAdded)
+
+		resp, respBody = doRequest(
+			t, "POST", "/api/emergency_contacts", utils.AddEmergencyContact, grantorAuth, "",
+			addBody,
+		)
+		require.Equal(t, 409, resp.StatusCode)
+		require.Contains(t, string(respBody), utils.ErrorAlreadyEmergencyContact)
+	})
+
+	contactTarget := "/api/emergency_contacts/" + contact.ContactSlug
+	requestTarget := "/api/emergency_access/" + contact.ContactSlug + "/request"
+
+	t.Run("list_emergency_contacts_200_ok", func(t *testing.T) {
+		resp, respBody := doRequest(
+			t, "GET", "/api/emergency_contacts", utils.ListEmergencyContacts, grantorAuth, "", "",
+		)
+		require.Equal(t, 200, resp.StatusCode)
+
+		var listed controllers.ListEmergencyContactsResponseBody
+		require.NoError(t, json.Unmarshal(respBody, &listed))
+		require.Len(t, listed.Contacts, 1)
+		require.Empty(t, listed.Grants)
+
+		resp, respBody = doRequest(
+			t, "GET", "/api/emergency_contacts", utils.ListEmergencyContacts, contactAuth, "", "",
+		)
+		require.Equal(t, 200, resp.StatusCode)
+		require.NoError(t, json.Unmarshal(respBody, &listed))
+		require.Empty(t, listed.Contacts)
+		require.Len(t, listed.Grants, 1)
+		require.Equal(t, contact.ContactSlug, listed.Grants[0].ContactSlug)
+		require.Equal(t, helpers.VALID_NAME_1, listed.Grants[0].GrantorName)
+		require.False(t, listed.Grants[0].AccessOpen)
+	})
+
+	t.Run("update_emergency_contact", func(t *testing.T) {
+		resp, _ := doRequest(
+			t, "PATCH", contactTarget, utils.UpdateEmergencyContact, contactAuth, "",
+			`{"waiting_days":1}`,
+		)
+		require.Equal(t, 404, resp.StatusCode)
+		assertLogMessage(t, utils.UpdateEmergencyContact, utils.ErrorEmergencyNotFound)
+
+		resp, respBody := doRequest(
+			t, "PATCH", contactTarget, utils.UpdateEmergencyContact, grantorAuth, "", `{}`,
+		)
+		require.Equal(t, 400, resp.StatusCode)
+		require.Contains(t, string(respBody), "Nothing to update")
+
+		resp, respBody = doRequest(
+			t, "PATCH", contactTarget, utils.UpdateEmergencyContact, grantorAuth, "",
+			`{"vault_slugs":["not a slug"]}`,
+		)
+		require.Equal(t, 400, resp.StatusCode)
+		require.Contains(t, string(respBody), "vault_slugs")
+		assertLogMessage(t, utils.UpdateEmergencyContact, utils.ErrorEmergencyContact)
+
+		resp, respBody = doRequest(
+			t, "PATCH", contactTarget, utils.UpdateEmergencyContact, grantorAuth, "",
+			`{"waiting_days":7}`,
+		)
+		require.Equal(t, 200, resp.StatusCode)
+		require.NoError(t, json.Unmarshal(respBody, &contact))
+		require.Equal(t, 7, contact.WaitingDays)
+		assertLogMessage(t, utils.UpdateEmergencyContact, utils.InfoEmergencyUpdated)
+	})
+
+	// The vaults service isn't reachable in tests, so select the vault directly
+	vaultSlug := helpers.NewSlug(t)
+	otherVaultSlug := helpers.NewSlug(t)
+	require.NoError(t, dbs.ApiGateway.Omit("EmergencyContact").Create(&models.EmergencyContactVault{
+		ContactSlug: contact.ContactSlug, VaultSlug: vaultSlug,
+	}).Error)
+
+	vaultTarget := "/api/vaults/" + vaultSlug
+
+	t.Run("policy_not_contact_404_not_found", func(t *testing.T) {
+		resp, _ := doRequest(
+			t, "GET", vaultTarget, utils.RetrieveVault, grantorAuth, contact.ContactSlug, "",
+		)
+		require.Equal(t, 404, resp.StatusCode)
+		assertLogMessage(t, utils.RetrieveVault, utils.ErrorEmergencyNotFound)
+	})
+
+	t.Run("policy_before_request_403_forbidden", func(t *testing.T) {
+		resp, respBody := doRequest(
+			t, "GET", vaultTarget, utils.RetrieveVault, contactAuth, contact.ContactSlug, "",
+		)
+		require.Equal(t, 403, resp.StatusCode)
+		require.Contains(t, string(respBody), utils.ErrorVaultPermission)
+		assertLogMessage(t, utils.RetrieveVault, utils.ErrorVaultRole)
+	})
+
+	t.Run("request_emergency_access_200_ok", func(t *testing.T) {
+		resp, _ := doRequest(
+			t, "POST", requestTarget, utils.RequestEmergencyAccess, grantorAuth, "", "",
+		)
+		require.Equal(t, 404, resp.StatusCode)
+
+		resp, respBody := doRequest(
+			t, "POST", requestTarget, utils.RequestEmergencyAccess, contactAuth, "", "",
+		)
+		require.Equal(t, 200, resp.StatusCode)
+		assertLogMessage(t, utils.RequestEmergencyAccess, utils.InfoEmergencyRequested)
+
+		var grant controllers.EmergencyGrantResponse
+		require.NoError(t, json.Unmarshal(respBody, &grant))
+		require.NotNil(t, grant.RequestedAt)
+		require.Equal(t, utils.EmergencyAccessOpensAt(*grant.RequestedAt, 7), *grant.OpensAt)
+		require.False(t, grant.AccessOpen)
+		require.Empty(t, grant.VaultSlugs)
+
+		resp, respBody = doRequest(
+			t, "POST", requestTarget, utils.RequestEmergencyAccess, contactAuth, "", "",
+		)
+		require.Equal(t, 409, resp.StatusCode)
+		require.Contains(t, string(respBody), utils.ErrorEmergencyRequested)
+	})
+
+	t.Run("policy_while_waiting_403_forbidden", func(t *testing.T) {
+		resp, _ := doRequest(
+			t, "GET", vaultTarget, utils.RetrieveVault, contactAuth, contact.ContactSlug, "",
+		)
+		require.Equal(t, 403, resp.StatusCode)
+	})
+
+	notices := controllers.Handler{DBs: dbs, Conf: conf}
+
+	countLogs := func(t *testing.T, message string) int64 {
+		var count int64
+		require.NoError(t, dbs.Logger.Model(&models.Log{}).Where("message = ?", message).
+			Count(&count).Error)
+
+		return count
+	}
+
+	t.Run("emergency_access_reminder", func(t *testing.T) {
+		var stored models.EmergencyContact
+		require.NoError(t, dbs.ApiGateway.Where("slug = ?", contact.ContactSlug).First(&stored).Error)
+		opensAt := utils.EmergencyAccessOpensAt(*stored.RequestedAt, stored.WaitingDays)
+
+		// Not yet a day before the waiting period ends
+		require.NoError(t, notices.SendEmergencyAccessNotices(opensAt.Add(-25 * time.Hour)))
+		require.Equal(t, int64(0), countLogs(t, utils.InfoEmergencyReminded))
+
+		for i := 0; i < 2; i++ {
+			require.NoError(t, notices.SendEmergencyAccessNotices(opensAt.Add(-time.Hour)))
+		}
+
+		require.Equal(t, int64(1), countLogs(t, utils.InfoEmergencyReminded))
+		assertLogMessage(t, utils.EmergencyAccessNotices, utils.InfoEmergencyReminded)
+		require.Equal(t, int64(0), countLogs(t, utils.InfoEmergencyOpened))
+	})
+
+	t.Run("reject_emergency_access_204_no_content", func(t *testing.T) {
+		resp, _ := doRequest(
+			t, "POST", contactTarget + "/reject", utils.RejectEmergencyAccess, grantorAuth, "", "",
+		)
+		require.Equal(t, 204, resp.StatusCode)
+		assertLogMessage(t, utils.RejectEmergencyAccess, utils.InfoEmergencyRejected)
+
+		resp, respBody := doRequest(
+			t, "POST", contactTarget + "/reject", utils.RejectEmergencyAccess, grantorAuth, "", "",
+		)
+		require.Equal(t, 409, resp.StatusCode)
+		require.Contains(t, string(respBody), utils.ErrorNoEmergencyRequest)
+
+		var stored models.EmergencyContact
+		require.NoError(t, dbs.ApiGateway.Where("slug = ?", contact.ContactSlug).First(&stored).Error)
+		require.Nil(t, stored.RemindedAt)
+	})
+
+	t.Run("policy_after_waiting_period", func(t *testing.T) {
+		// Request access again, then let the waiting period pass
+		resp, _ := doRequest(
+			t, "POST", requestTarget, utils.RequestEmergencyAccess, contactAuth, "", "",
+		)
+		require.Equal(t, 200, resp.StatusCode)
+		require.NoError(t, dbs.ApiGateway.Model(&models.EmergencyContact{}).
+			Where("slug = ?", contact.ContactSlug).
+			Update("requested_at", time.Now().UTC().Add(-8 * 24 * time.Hour)).Error)
+
+		resp, respBody := doRequest(
+			t, "GET", "/api/emergency_contacts", utils.ListEmergencyContacts, contactAuth, "", "",
+		)
+		require.Equal(t, 200, resp.StatusCode)
+
+		var listed controllers.ListEmergencyContactsResponseBody
+		require.NoError(t, json.Unmarshal(respBody, &listed))
+		require.True(t, listed.Grants[0].AccessOpen)
+		require.Equal(t, []string{vaultSlug}, listed.Grants[0].VaultSlugs)
+
+		// Both are notified once that access is open
+		for i := 0; i < 2; i++ {
+			require.NoError(t, notices.SendEmergencyAccessNotices(time.Now().UTC()))
+		}
+
+		require.Equal(t, int64(1), countLogs(t, utils.InfoEmergencyOpened))
+		assertLogMessage(t, utils.EmergencyAccessNotices, utils.InfoEmergencyOpened)
+		require.Equal(t, int64(1), countLogs(t, utils.InfoEmergencyReminded))
+
+		// Read-only, and only the selected vaults
+		resp, _ = doRequest(
+			t, "PATCH", vaultTarget, utils.UpdateVault, contactAuth, contact.ContactSlug,
+			`{"vault_title":"Renamed"}`,
+		)
+		require.Equal(t, 403, resp.StatusCode)
+
+		resp, _ = doRequest(
+			t, "GET", "/api/vaults/" + otherVaultSlug, utils.RetrieveVault, contactAuth,
+			contact.ContactSlug, "",
+		)
+		require.Equal(t, 403, resp.StatusCode)
+
+		// Passes the policy and reaches the vaults service
+		resp, _ = doRequest(
+			t, "GET", vaultTarget, utils.RetrieveVault, contactAuth, contact.ContactSlug, "",
+		)
+		require.Equal(t, 503, resp.StatusCode)
+
+		var count int64
+		dbs.Logger.Model(&models.Log{}).Where("message = ?", utils.InfoEmergencyAccess).Count(&count)
+		require.Equal(t, int64(1), count)
+	})
+
+	t.Run("remove_emergency_contact_204_no_content", func(t *testing.T) {
+		resp, _ := doRequest(
+			t, "DELETE", contactTarget, utils.RemoveEmergencyContact, contactAuth, "", "",
+		)
+		require.Equal(t, 404, resp.StatusCode)
+
+		resp, _ = doRequest(
+			t, "DELETE", contactTarget, utils.RemoveEmergencyContact, grantorAuth, "", "",
+		)
+		require.Equal(t, 204, resp.StatusCode)
+		assertLogMessage(t, utils.RemoveEmergencyContact, utils.InfoEmergencyRemoved)
+
+		var count int64
+		dbs.ApiGateway.Model(&models.EmergencyContactVault{}).Count(&count)
+		require.Equal(t, int64(0), count)
+
+		resp, _ = doRequest(
+			t, "GET", vaultTarget, utils.RetrieveVault, contactAuth, contact.ContactSlug, "",
+		)
+		require.Equal(t, 404, resp.StatusCode)
+	})
+}
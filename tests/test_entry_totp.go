@@ -0,0 +1,107 @@
+package tests
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/config"
+	"github.com/liobrdev/simplepasswords_api_gateway/databases"
+	"github.com/liobrdev/simplepasswords_api_gateway/models"
+	"github.com/liobrdev/simplepasswords_api_gateway/tests/helpers"
+	"github.com/liobrdev/simplepasswords_api_gateway/tests/setup"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+func testEntryTOTP(
+	t *testing.T, app *fiber.App, dbs *databases.Databases, conf *config.AppConfig,
+) {
+	var clientIP string
+
+	if conf.BEHIND_PROXY {
+		clientIP = helpers.CLIENT_IP
+	} else {
+		clientIP = "0.0.0.0"
+	}
+
+	setup.SetUpLogger(t, dbs)
+	user := setup.SetUpApiGatewayWithData(t, dbs)
+	validTokens := setup.CreateValidTestClientSessions(&user, t, dbs, conf)
+
+	invalidBodies := map[string]map[string][]string{
+		`{"entry_title":"Email","secrets":[{"secret_label":"2FA","secret_string":"JBSWY3DP",` +
+		`"secret_priority":0,"secret_kind":"totp"}]}`:
+			{"secrets[0].secret_string": {"Must be an otpauth://totp/ URI or base32 seed"}},
+		`{"entry_title":"Email","secrets":[{"secret_label":"Password","secret_string":"x",` +
+		`"secret_priority":0},{"secret_label":"2FA","secret_string":"x","secret_priority":1,` +
+		`"secret_kind":"hotp"}]}`:
+			{"secrets[1].secret_kind": {"Must be one of text, totp"}},
+	}
+
+	for body, fieldErrors := range invalidBodies {
+		t.Run("create_entry_invalid_totp_400_bad_request", func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/api/entries", strings.NewReader(body))
+			req.Header.Set("Authorization", "Token " + validTokens[0])
+			req.Header.Set("Client-Operation", utils.CreateEntry)
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("X-Forwarded-For", helpers.CLIENT_IP)
+			req.Header.Set(conf.PASSWORD_HEADER_KEY, helpers.HexHash1)
+
+			resp, err := app.Test(req, -1)
+			require.NoError(t, err)
+			require.Equal(t, 400, resp.StatusCode)
+
+			helpers.AssertErrorResponseBody(t, resp, &utils.ErrorResponseBody{
+				Detail:      utils.ErrorBadRequest,
+				FieldErrors: fieldErrors,
+			})
+
+			var actualLog models.Log
+			helpers.QueryTestLogLatest(t, dbs.Logger, &actualLog)
+			helpers.AssertLog(t, &models.Log{
+				ClientIP:        clientIP,
+				ClientOperation: utils.CreateEntry,
+				Level:           "warn",
+				Message:         utils.ErrorSecretKind,
+				RequestBody:     body,
+			}, &actualLog)
+		})
+	}
+
+	t.Run("update_secret_invalid_totp_400_bad_request", func(t *testing.T) {
+		slug := helpers.NewSlug(t)
+		body := `{"secret_label":"2FA","secret_string":"JBSWY3DP","secret_kind":"totp"}`
+		req := httptest.NewRequest("PATCH", "/api/secrets/" + slug, strings.NewReader(body))
+		req.Header.Set("Authorization", "Token " + validTokens[0])
+		req.Header.Set("Client-Operation", utils.UpdateSecret)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("If-Match", `"1"`)
+		req.Header.Set("X-Forwarded-For", helpers.CLIENT_IP)
+		req.Header.Set(conf.PASSWORD_HEADER_KEY, helpers.HexHash1)
+
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+		require.Equal(t, 400, resp.StatusCode)
+
+		helpers.AssertErrorResponseBody(t, resp, &utils.ErrorResponseBody{
+			Detail:      utils.ErrorBadRequest,
+			FieldErrors: map[string][]string{
+				"secret_string": {"Must be an otpauth://totp/ URI or base32 seed"},
+			},
+		})
+
+		var actualLog models.Log
+		helpers.QueryTestLogLatest(t, dbs.Logger, &actualLog)
+		helpers.AssertLog(t, &models.Log{
+			ClientIP:        clientIP,
+			ClientOperation: utils.UpdateSecret,
+			Extra:           slug,
+			Level:           "warn",
+			Message:         utils.ErrorSecretKind,
+			RequestBody:     body,
+		}, &actualLog)
+	})
+}
@@ -0,0 +1,112 @@
+package tests
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/config"
+	"github.com/liobrdev/simplepasswords_api_gateway/databases"
+	"github.com/liobrdev/simplepasswords_api_gateway/models"
+	"github.com/liobrdev/simplepasswords_api_gateway/tests/helpers"
+	"github.com/liobrdev/simplepasswords_api_gateway/tests/setup"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+func testEntryTypes(
+	t *testing.T, app *fiber.App, dbs *databases.Databases, conf *config.AppConfig,
+) {
+	var clientIP string
+
+	if conf.BEHIND_PROXY {
+		clientIP = helpers.CLIENT_IP
+	} else {
+		clientIP = "0.0.0.0"
+	}
+
+	setup.SetUpLogger(t, dbs)
+	user := setup.SetUpApiGatewayWithData(t, dbs)
+	validTokens := setup.CreateValidTestClientSessions(&user, t, dbs, conf)
+	slug := helpers.NewSlug(t)
+
+	type invalidEntry struct {
+		method      string
+		target      string
+		operation   string
+		body        string
+		message     string
+		detail      string
+		extra       string
+		fieldErrors map[string][]string
+	}
+
+	invalidEntries := []invalidEntry{
+		{"POST", "/api/entries", utils.CreateEntry, `{"entry_type":"bank","secrets":[]}`,
+		utils.ErrorEntryType, "bank", "", map[string][]string{
+			"entry_type": {"Must be one of login, card, identity, secure_note, ssh_key"},
+		}},
+		{"POST", "/api/entries", utils.CreateEntry, `{"entry_type":"card","secrets":[` +
+		`{"secret_label":"Card Number","secret_string":"4111111111111112"},` +
+		`{"secret_label":"Expiry","secret_string":"2031-09"}]}`,
+		utils.ErrorEntryType, "card", "", map[string][]string{
+			"secrets[0].secret_string": {"Must be a valid card number"},
+			"secrets[1].secret_string": {"Must be MM/YY or MM/YYYY"},
+			"secrets":                  {"Missing required cardholder_name field"},
+		}},
+		{"POST", "/api/entries", utils.CreateEntry, `{"entry_type":"login","secrets":[` +
+		`{"secret_label":"Password","secret_string":"hunter2"},` +
+		`{"secret_label":"TOTP","secret_string":"nope"}]}`,
+		utils.ErrorSecretKind, "", "", map[string][]string{
+			"secrets[1].secret_string": {"Must be an otpauth://totp/ URI or base32 seed"},
+		}},
+		{"PATCH", "/api/entries/" + slug, utils.UpdateEntry, `{"entry_type":"secure_note",` +
+		`"secrets":[{"secret_label":"Note","secret_string":""}]}`,
+		utils.ErrorEntryType, "secure_note", slug, map[string][]string{
+			"secrets[0].secret_string": {"Required"},
+		}},
+		{"POST", "/api/entries", utils.CreateEntry, `{"url_match":"regex","secrets":[]}`,
+		utils.ErrorURLMatch, "regex", "", map[string][]string{
+			"url_match": {"Must be one of domain, host, prefix, never"},
+		}},
+		{"PATCH", "/api/entries/" + slug, utils.UpdateEntry, `{"url_match":"exact"}`,
+		utils.ErrorURLMatch, "exact", slug, map[string][]string{
+			"url_match": {"Must be one of domain, host, prefix, never"},
+		}},
+	}
+
+	for _, entry := range invalidEntries {
+		t.Run(entry.operation + "_invalid_fields_400_bad_request", func(t *testing.T) {
+			req := httptest.NewRequest(entry.method, entry.target, strings.NewReader(entry.body))
+			req.Header.Set("Authorization", "Token " + validTokens[0])
+			req.Header.Set("Client-Operation", entry.operation)
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("If-Match", `"1"`)
+			req.Header.Set("X-Forwarded-For", helpers.CLIENT_IP)
+			req.Header.Set(conf.PASSWORD_HEADER_KEY, helpers.HexHash1)
+
+			resp, err := app.Test(req, -1)
+			require.NoError(t, err)
+			require.Equal(t, 400, resp.StatusCode)
+
+			helpers.AssertErrorResponseBody(t, resp, &utils.ErrorResponseBody{
+				Detail:      utils.ErrorBadRequest,
+				FieldErrors: entry.fieldErrors,
+			})
+
+			var actualLog models.Log
+			helpers.QueryTestLogLatest(t, dbs.Logger, &actualLog)
+			helpers.AssertLog(t, &models.Log{
+				ClientIP:        clientIP,
+				ClientOperation: entry.operation,
+				Detail:          entry.detail,
+				Extra:           entry.extra,
+				Level:           "warn",
+				Message:         entry.message,
+				RequestBody:     entry.body,
+			}, &actualLog)
+		})
+	}
+}
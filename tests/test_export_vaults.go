@@ -0,0 +1,141 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/config"
+	"github.com/liobrdev/simplepasswords_api_gateway/databases"
+	"github.com/liobrdev/simplepasswords_api_gateway/models"
+	"github.com/liobrdev/simplepasswords_api_gateway/tests/helpers"
+	"github.com/liobrdev/simplepasswords_api_gateway/tests/setup"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+func testExportVaults(
+	t *testing.T, app *fiber.App, dbs *databases.Databases, conf *config.AppConfig,
+) {
+	var clientIP string
+
+	if conf.BEHIND_PROXY {
+		clientIP = helpers.CLIENT_IP
+	} else {
+		clientIP = "0.0.0.0"
+	}
+
+	setup.SetUpLogger(t, dbs)
+	user := setup.SetUpApiGatewayWithData(t, dbs)
+	validTokens := setup.CreateValidTestClientSessions(&user, t, dbs, conf)
+
+	invalidBodies := []struct {
+		name        string
+		body        string
+		fieldErrors map[string][]string
+	}{
+		{"format", `{"format":"xml"}`,
+			map[string][]string{"format": {"Must be one of encrypted_json, csv"}}},
+		{"passphrase", `{"format":"encrypted_json","passphrase":"too short"}`,
+			map[string][]string{"passphrase": {"Must be at least 12 characters"}}},
+		{"confirm_plaintext", `{"format":"csv"}`,
+			map[string][]string{"confirm_plaintext": {"Must confirm an unencrypted export"}}},
+	}
+
+	for _, invalid := range invalidBodies {
+		body, fieldErrors := invalid.body, invalid.fieldErrors
+
+		t.Run("invalid_" + invalid.name + "_400_bad_request", func(t *testing.T) {
+			resp := newRequestExportVaults(t, app, conf, "Token " + validTokens[0], body)
+			require.Equal(t, 400, resp.StatusCode)
+
+			helpers.AssertErrorResponseBody(t, resp, &utils.ErrorResponseBody{
+				Detail:      utils.ErrorBadRequest,
+				FieldErrors: fieldErrors,
+			})
+
+			var actualLog models.Log
+			helpers.QueryTestLogLatest(t, dbs.Logger, &actualLog)
+			helpers.AssertLog(t, &models.Log{
+				ClientIP:        clientIP,
+				ClientOperation: utils.ExportVaults,
+				Detail:          strings.Split(strings.Split(body, `"format":"`)[1], `"`)[0],
+				Level:           "warn",
+				Message:         utils.ErrorExportVaults,
+				RequestBody:     "[redacted]",
+				UserSlug:        user.Slug,
+			}, &actualLog)
+		})
+	}
+
+	t.Run("wrong_password_401_unauthorized", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/api/export", strings.NewReader(
+			`{"format":"encrypted_json","passphrase":"correct horse battery staple"}`,
+		))
+		req.Header.Set("Authorization", "Token " + validTokens[0])
+		req.Header.Set("Client-Operation", utils.ExportVaults)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Forwarded-For", helpers.CLIENT_IP)
+		req.Header.Set(conf.PASSWORD_HEADER_KEY, helpers.HexHash2)
+
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+		require.Equal(t, 401, resp.StatusCode)
+
+		helpers.AssertErrorResponseBody(t, resp, &utils.ErrorResponseBody{Detail: utils.ErrorAcctPW})
+
+		var actualLog models.Log
+		helpers.QueryTestLogLatest(t, dbs.Logger, &actualLog)
+		helpers.AssertLog(t, &models.Log{
+			ClientIP:        clientIP,
+			ClientOperation: utils.ExportVaults,
+			Level:           "warn",
+			Message:         utils.ErrorAcctPW,
+			RequestBody:     "[redacted]",
+			UserSlug:        user.Slug,
+		}, &actualLog)
+	})
+
+	t.Run("vaults_unavailable_503_service_unavailable", func(t *testing.T) {
+		resp := newRequestExportVaults(
+			t, app, conf, "Token " + validTokens[0], `{"format":"csv","confirm_plaintext":true}`,
+		)
+		require.Equal(t, 503, resp.StatusCode)
+
+		helpers.AssertErrorResponseBody(t, resp, &utils.ErrorResponseBody{Detail: utils.ErrorServer})
+
+		var actualLog models.Log
+		helpers.QueryTestLogLatest(t, dbs.Logger, &actualLog)
+		helpers.AssertLog(t, &models.Log{
+			ClientIP:        clientIP,
+			ClientOperation: utils.ExportVaults,
+			Detail:          "no healthy vaults upstream;;",
+			Level:           "warn",
+			Message:         utils.ErrorVaultsExportVaults,
+			RequestBody:     "[redacted]",
+			UserSlug:        user.Slug,
+		}, &actualLog)
+	})
+}
+
+func newRequestExportVaults(
+	t *testing.T, app *fiber.App, conf *config.AppConfig, authHeader, body string,
+) *http.Response {
+	req := httptest.NewRequest("POST", "/api/export", strings.NewReader(body))
+	req.Header.Set("Authorization", authHeader)
+	req.Header.Set("Client-Operation", utils.ExportVaults)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Forwarded-For", helpers.CLIENT_IP)
+	req.Header.Set(conf.PASSWORD_HEADER_KEY, helpers.HexHash1)
+
+	resp, err := app.Test(req, -1)
+
+	if err != nil {
+		t.Fatalf("Send test request failed: %s", err.Error())
+	}
+
+	return resp
+}
@@ -0,0 +1,138 @@
+package tests
+
+import (
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/config"
+	"github.com/liobrdev/simplepasswords_api_gateway/controllers"
+	"github.com/liobrdev/simplepasswords_api_gateway/databases"
+	"github.com/liobrdev/simplepasswords_api_gateway/models"
+	"github.com/liobrdev/simplepasswords_api_gateway/tests/helpers"
+	"github.com/liobrdev/simplepasswords_api_gateway/tests/setup"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+func testGeneratePassword(
+	t *testing.T, app *fiber.App, dbs *databases.Databases, conf *config.AppConfig,
+) {
+	var clientIP string
+
+	if conf.BEHIND_PROXY {
+		clientIP = helpers.CLIENT_IP
+	} else {
+		clientIP = "0.0.0.0"
+	}
+
+	setup.SetUpLogger(t, dbs)
+	user := setup.SetUpApiGatewayWithData(t, dbs)
+	validTokens := setup.CreateValidTestClientSessions(&user, t, dbs, conf)
+
+	invalidBodies := map[string]utils.ErrorResponseBody{
+		`{"mode":"pin"}`:
+			{FieldErrors: map[string][]string{"mode": {"Must be one of password, passphrase"}}},
+		`{"length":7}`:
+			{FieldErrors: map[string][]string{"length": {"Must be an integer from 8 to 128"}}},
+		`{"uppercase":false,"lowercase":false,"digits":false,"special":false}`:
+			{NonFieldErrors: []string{"Must enable at least one character class"}},
+		`{"special":false,"min_special":2}`:
+			{FieldErrors: map[string][]string{"min_special": {"Character class is disabled"}}},
+		`{"length":8,"min_digits":5,"min_special":4}`:
+			{NonFieldErrors: []string{"Required minimums exceed length"}},
+		`{"mode":"passphrase","words":3}`:
+			{FieldErrors: map[string][]string{"words": {"Must be an integer from 4 to 20"}}},
+		`{"mode":"passphrase","separator":"++"}`:
+			{FieldErrors: map[string][]string{
+				"separator": {`Must be one of "-", "_", ".", " ", ""`},
+			}},
+	}
+
+	for body, errRespBody := range invalidBodies {
+		t.Run("invalid_body_400_bad_request", func(t *testing.T) {
+			resp := newRequestGeneratePassword(t, app, "Token " + validTokens[0], body)
+			require.Equal(t, 400, resp.StatusCode)
+
+			errRespBody.Detail = utils.ErrorBadRequest
+			helpers.AssertErrorResponseBody(t, resp, &errRespBody)
+
+			var reqBody controllers.GeneratePasswordRequestBody
+			require.NoError(t, json.Unmarshal([]byte(body), &reqBody))
+
+			if reqBody.Mode == "" {
+				reqBody.Mode = controllers.GenerateModePassword
+			}
+
+			var actualLog models.Log
+			helpers.QueryTestLogLatest(t, dbs.Logger, &actualLog)
+			helpers.AssertLog(t, &models.Log{
+				ClientIP:        clientIP,
+				ClientOperation: utils.GeneratePasswordOp,
+				Detail:          reqBody.Mode,
+				Level:           "warn",
+				Message:         utils.ErrorGeneratePassword,
+				RequestBody:     body,
+				UserSlug:        user.Slug,
+			}, &actualLog)
+		})
+	}
+
+	t.Run("valid_password_200_ok", func(t *testing.T) {
+		resp := newRequestGeneratePassword(
+			t, app, "Token " + validTokens[0], `{"length":32,"exclude_ambiguous":true}`,
+		)
+		require.Equal(t, 200, resp.StatusCode)
+
+		var respBody controllers.GeneratePasswordResponseBody
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&respBody))
+		require.Equal(t, controllers.GenerateModePassword, respBody.Mode)
+		require.Len(t, respBody.Password, 32)
+		require.NotContains(t, respBody.Password, "0")
+
+		alphabetSize := len(utils.PASSWORD_ALPHABET) - len(utils.AMBIGUOUS_CHARS)
+		require.InDelta(t, 32 * math.Log2(float64(alphabetSize)), respBody.EntropyBits, 0.1)
+	})
+
+	t.Run("valid_passphrase_200_ok", func(t *testing.T) {
+		resp := newRequestGeneratePassword(
+			t, app, "Token " + validTokens[0], `{"mode":"passphrase","words":5,"separator":" "}`,
+		)
+		require.Equal(t, 200, resp.StatusCode)
+
+		var respBody controllers.GeneratePasswordResponseBody
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&respBody))
+		require.Equal(t, controllers.GenerateModePassphrase, respBody.Mode)
+		require.InDelta(t, 5 * math.Log2(7772), respBody.EntropyBits, 0.1)
+
+		words := strings.Split(respBody.Password, " ")
+		require.Len(t, words, 5)
+
+		for _, word := range words {
+			require.True(t, utils.PassphraseWordlistContains(word))
+		}
+	})
+}
+
+func newRequestGeneratePassword(
+	t *testing.T, app *fiber.App, authHeader, body string,
+) *http.Response {
+	req := httptest.NewRequest("POST", "/api/generate_password", strings.NewReader(body))
+	req.Header.Set("Authorization", authHeader)
+	req.Header.Set("Client-Operation", utils.GeneratePasswordOp)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Forwarded-For", helpers.CLIENT_IP)
+
+	resp, err := app.Test(req, -1)
+
+	if err != nil {
+		t.Fatalf("Send test request failed: %s", err.Error())
+	}
+
+	return resp
+}
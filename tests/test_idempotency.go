@@ -0,0 +1,214 @@
+package tests
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/config"
+	"github.com/liobrdev/simplepasswords_api_gateway/databases"
+	"github.com/liobrdev/simplepasswords_api_gateway/idempotency"
+	"github.com/liobrdev/simplepasswords_api_gateway/models"
+	"github.com/liobrdev/simplepasswords_api_gateway/tests/helpers"
+	"github.com/liobrdev/simplepasswords_api_gateway/tests/setup"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+func testIdempotency(
+	t *testing.T, app *fiber.App, dbs *databases.Databases, conf *config.AppConfig,
+) {
+	var clientIP string
+
+	if conf.BEHIND_PROXY {
+		clientIP = helpers.CLIENT_IP
+	} else {
+		clientIP = "0.0.0.0"
+	}
+
+	setup.SetUpLogger(t, dbs)
+	user := setup.SetUpApiGatewayWithData(t, dbs)
+	validTokens := setup.CreateValidTestClientSessions(&user, t, dbs, conf)
+	store := idempotency.New(dbs)
+	ctx := context.Background()
+	body := `{"vault_title":"New Vault"}`
+	fingerprint := idempotency.Fingerprint("POST", "/api/vaults/", []byte(body))
+
+	doRequest := func(t *testing.T, idempotencyKey, reqBody string) (int, string, string) {
+		req := httptest.NewRequest("POST", "/api/vaults/", strings.NewReader(reqBody))
+		req.Header.Set("Authorization", "Token " + validTokens[0])
+		req.Header.Set("Client-Operation", utils.CreateVault)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Idempotency-Key", idempotencyKey)
+		req.Header.Set("X-Forwarded-For", helpers.CLIENT_IP)
+		req.Header.Set(conf.PASSWORD_HEADER_KEY, helpers.HexHash1)
+
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+
+		respBody, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		return resp.StatusCode, resp.Header.Get("Idempotent-Replayed"), string(respBody)
+	}
+
+	t.Run("store_begin_complete_release", func(t *testing.T) {
+		key := idempotency.Key(user.Slug, utils.CreateVault, "store")
+		other := idempotency.Fingerprint("POST", "/api/vaults/", []byte(`{}`))
+
+		stored, err := store.Begin(ctx, key, user.Slug, fingerprint)
+		require.NoError(t, err)
+		require.Nil(t, stored)
+
+		_, err = store.Begin(ctx, key, user.Slug, fingerprint)
+		require.ErrorIs(t, err, idempotency.ErrInProgress)
+
+		_, err = store.Begin(ctx, key, user.Slug, other)
+		require.ErrorIs(t, err, idempotency.ErrMismatch)
+
+		require.NoError(t, store.Complete(ctx, key, fingerprint, &idempotency.Response{
+			StatusCode: 204,
+		}))
+
+		stored, err = store.Begin(ctx, key, user.Slug, fingerprint)
+		require.NoError(t, err)
+		require.Equal(t, 204, stored.StatusCode)
+
+		require.NoError(t, store.Release(ctx, key))
+
+		stored, err = store.Begin(ctx, key, user.Slug, other)
+		require.NoError(t, err)
+		require.Nil(t, stored)
+	})
+
+	t.Run("store_complete_without_begin", func(t *testing.T) {
+		key := idempotency.Key(user.Slug, utils.CreateVault, "never_begun")
+
+		require.ErrorIs(t, store.Complete(ctx, key, fingerprint, &idempotency.Response{
+			StatusCode: 204,
+		}), idempotency.ErrNotBegun)
+	})
+
+	t.Run("keys_scoped_by_user_and_operation", func(t *testing.T) {
+		require.NotEqual(
+			t, idempotency.Key(user.Slug, utils.CreateVault, "k"),
+			idempotency.Key(user.Slug, utils.CreateEntry, "k"),
+		)
+		require.NotEqual(
+			t, idempotency.Key(user.Slug, utils.CreateVault, "k"),
+			idempotency.Key(helpers.NewSlug(t), utils.CreateVault, "k"),
+		)
+	})
+
+	for _, invalid := range []string{"has space", strings.Repeat("k", 256), "café"} {
+		t.Run("invalid_key_400_bad_request", func(t *testing.T) {
+			statusCode, _, _ := doRequest(t, invalid, body)
+			require.Equal(t, 400, statusCode)
+
+			var actualLog models.Log
+			helpers.QueryTestLogLatest(t, dbs.Logger, &actualLog)
+			helpers.AssertLog(t, &models.Log{
+				ClientIP:        clientIP,
+				ClientOperation: utils.CreateVault,
+				Detail:          invalid,
+				Level:           "warn",
+				Message:         utils.ErrorIdempotencyKey,
+				RequestBody:     body,
+			}, &actualLog)
+		})
+	}
+
+	key := idempotency.Key(user.Slug, utils.CreateVault, "replay-1")
+	_, err := store.Begin(ctx, key, user.Slug, fingerprint)
+	require.NoError(t, err)
+
+	t.Run("in_progress_409_conflict", func(t *testing.T) {
+		statusCode, _, respBody := doRequest(t, "replay-1", body)
+		require.Equal(t, 409, statusCode)
+		require.Contains(t, respBody, utils.ErrorIdempotencyKeyInProgress)
+
+		var actualLog models.Log
+		helpers.QueryTestLogLatest(t, dbs.Logger, &actualLog)
+		helpers.AssertLog(t, &models.Log{
+			ClientIP:        clientIP,
+			ClientOperation: utils.CreateVault,
+			Detail:          "replay-1",
+			Level:           "warn",
+			Message:         utils.ErrorIdempotencyInProgress,
+			RequestBody:     body,
+			UserSlug:        user.Slug,
+		}, &actualLog)
+	})
+
+	require.NoError(t, store.Complete(ctx, key, fingerprint, &idempotency.Response{
+		StatusCode:  201,
+		ContentType: fiber.MIMEApplicationJSON,
+		Body:        []byte(`{"vault_slug":"abc"}`),
+	}))
+
+	t.Run("repeat_replays_stored_response", func(t *testing.T) {
+		statusCode, replayed, respBody := doRequest(t, "replay-1", body)
+		require.Equal(t, 201, statusCode)
+		require.Equal(t, "true", replayed)
+		require.Equal(t, `{"vault_slug":"abc"}`, respBody)
+	})
+
+	t.Run("different_body_422_unprocessable_entity", func(t *testing.T) {
+		otherBody := `{"vault_title":"Other Vault"}`
+		statusCode, _, respBody := doRequest(t, "replay-1", otherBody)
+		require.Equal(t, 422, statusCode)
+		require.Contains(t, respBody, utils.ErrorIdempotencyKeyReused)
+
+		var actualLog models.Log
+		helpers.QueryTestLogLatest(t, dbs.Logger, &actualLog)
+		helpers.AssertLog(t, &models.Log{
+			ClientIP:        clientIP,
+			ClientOperation: utils.CreateVault,
+			Detail:          "replay-1",
+			Level:           "warn",
+			Message:         utils.ErrorIdempotencyMismatch,
+			RequestBody:     otherBody,
+			UserSlug:        user.Slug,
+		}, &actualLog)
+	})
+
+	t.Run("failed_request_releases_key", func(t *testing.T) {
+		statusCode, replayed, _ := doRequest(t, "fails-1", body)
+		require.GreaterOrEqual(t, statusCode, 500)
+		require.Empty(t, replayed)
+
+		var count int64
+		dbs.ApiGateway.Model(&models.IdempotencyRecord{}).
+			Where("key = ?", idempotency.Key(user.Slug, utils.CreateVault, "fails-1")).Count(&count)
+		require.Zero(t, count)
+	})
+
+	t.Run("vault_access_checked_before_key", func(t *testing.T) {
+		// A request that's denied access must not learn whether its key is in use
+		key := idempotency.Key(user.Slug, utils.CreateVault, "denied-1")
+		stored, err := store.Begin(ctx, key, user.Slug, fingerprint)
+		require.NoError(t, err)
+		require.Nil(t, stored)
+
+		req := httptest.NewRequest("POST", "/api/vaults/", strings.NewReader(body))
+		req.Header.Set("Authorization", "Token " + validTokens[0])
+		req.Header.Set("Client-Operation", utils.CreateVault)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Idempotency-Key", "denied-1")
+		req.Header.Set("Org-Slug", helpers.NewSlug(t))
+		req.Header.Set("X-Forwarded-For", helpers.CLIENT_IP)
+		req.Header.Set(conf.PASSWORD_HEADER_KEY, helpers.HexHash1)
+
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+		require.Equal(t, 404, resp.StatusCode)
+
+		_, err = store.Begin(ctx, key, user.Slug, fingerprint)
+		require.ErrorIs(t, err, idempotency.ErrInProgress)
+		require.NoError(t, store.Release(ctx, key))
+	})
+}
@@ -0,0 +1,195 @@
+package tests
+
+import (
+	"archive/zip"
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/config"
+	"github.com/liobrdev/simplepasswords_api_gateway/databases"
+	"github.com/liobrdev/simplepasswords_api_gateway/importers"
+	"github.com/liobrdev/simplepasswords_api_gateway/models"
+	"github.com/liobrdev/simplepasswords_api_gateway/tests/helpers"
+	"github.com/liobrdev/simplepasswords_api_gateway/tests/setup"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+func testImportEntries(
+	t *testing.T, app *fiber.App, dbs *databases.Databases, conf *config.AppConfig,
+) {
+	t.Run("chrome_csv", func(t *testing.T) {
+		entries, rowErrors := testImportParse(t, importers.ChromeCSV,
+			"name,url,username,password,note\n" +
+			"GitHub,https://github.com/login,jane,hunter2,\n" +
+			",,,,\n",
+		)
+
+		require.Len(t, entries, 1)
+		require.Equal(t, importers.Entry{
+			Row: 1, Title: "GitHub", URL: "https://github.com/login", Username: "jane",
+			Password: "hunter2",
+		}, entries[0])
+		require.Equal(t, []importers.RowError{{Row: 2, Error: "Missing title and URL."}}, rowErrors)
+	})
+
+	t.Run("firefox_csv_title_from_host", func(t *testing.T) {
+		entries, _ := testImportParse(t, importers.FirefoxCSV,
+			`"url","username","password","httpRealm","formActionOrigin","guid","timeCreated"` + "\n" +
+			`"https://www.example.com","jane","pw","","https://www.example.com","{1}","1"` + "\n",
+		)
+
+		require.Len(t, entries, 1)
+		require.Equal(t, "www.example.com", entries[0].Title)
+		require.Empty(t, entries[0].Fields)
+	})
+
+	t.Run("bitwarden_json", func(t *testing.T) {
+		entries, _ := testImportParse(t, importers.BitwardenJSON, `{"encrypted":false,"items":[{
+			"type":1,"name":"Bank","notes":"pin 1234","login":{"username":"jane","password":"pw",
+			"uris":[{"uri":"https://bank.example"},{"uri":"https://m.bank.example"}]},
+			"fields":[{"name":"Security answer","value":"blue"}]}]}`,
+		)
+
+		require.Len(t, entries, 1)
+		require.Equal(t, "Bank", entries[0].Title)
+		require.Equal(t, "https://bank.example", entries[0].URL)
+		require.Equal(t, []importers.Field{
+			{Label: "URL", Value: "https://m.bank.example"},
+			{Label: "Security answer", Value: "blue"},
+		}, entries[0].Fields)
+	})
+
+	t.Run("onepassword_1pux", func(t *testing.T) {
+		var archive bytes.Buffer
+		w := zip.NewWriter(&archive)
+
+		if f, err := w.Create("export.data"); err != nil {
+			t.Fatalf("Create zip entry failed: %s", err.Error())
+		} else {
+			f.Write([]byte(`{"accounts":[{"vaults":[{"items":[
+				{"state":"active","overview":{"title":"Mail","url":"https://mail.example"},
+				"details":{"loginFields":[{"designation":"username","value":"jane"},
+				{"designation":"password","value":"pw"}],"notesPlain":"",
+				"sections":[{"fields":[{"title":"Recovery","value":{"concealed":"abcd"}}]}]}},
+				{"state":"archived","overview":{"title":"Old"},"details":{"password":"x"}}
+			]}]}]}`))
+		}
+
+		w.Close()
+
+		entries, rowErrors, err := importers.Parse(importers.OnePassword1PUX, archive.Bytes())
+		require.NoError(t, err)
+		require.Empty(t, rowErrors)
+		require.Len(t, entries, 1)
+		require.Equal(t, "jane", entries[0].Username)
+		require.Equal(t, []importers.Field{{Label: "Recovery", Value: "abcd"}}, entries[0].Fields)
+	})
+
+	t.Run("keepass_xml", func(t *testing.T) {
+		entries, _ := testImportParse(t, importers.KeePassXML, `<KeePassFile><Root><Group>
+			<Name>Root</Name>
+			<Entry>
+				<String><Key>Title</Key><Value>Router</Value></String>
+				<String><Key>UserName</Key><Value>admin</Value></String>
+				<String><Key>Password</Key><Value>pw</Value></String>
+				<History><Entry><String><Key>Title</Key><Value>Old router</Value></String></Entry></History>
+			</Entry>
+			<Group><Name>Recycle Bin</Name><Entry>
+				<String><Key>Title</Key><Value>Deleted</Value></String>
+			</Entry></Group>
+		</Group></Root></KeePassFile>`)
+
+		require.Len(t, entries, 1)
+		require.Equal(t, "Router", entries[0].Title)
+		require.Equal(t, "admin", entries[0].Username)
+	})
+
+	t.Run("unknown_format", func(t *testing.T) {
+		_, _, err := importers.Parse("lastpass_csv", []byte("url,username"))
+		require.ErrorIs(t, err, importers.ErrUnknownFormat)
+	})
+
+	t.Run("vault_slug_not_owned_400_bad_request", func(t *testing.T) {
+		var clientIP string
+
+		if conf.BEHIND_PROXY {
+			clientIP = helpers.CLIENT_IP
+		} else {
+			clientIP = "0.0.0.0"
+		}
+
+		setup.SetUpLogger(t, dbs)
+		user := setup.SetUpApiGatewayWithData(t, dbs)
+		validTokens := setup.CreateValidTestClientSessions(&user, t, dbs, conf)
+		ownedSlug, otherSlug := helpers.NewSlug(t), helpers.NewSlug(t)
+		searched := false
+
+		stubApp := newVaultsStubApp(t, dbs, conf, conf.VAULTS_MAX_RESPONSE_BYTES,
+			func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/api/search" {
+					searched = true
+				}
+
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{"vaults":[{"vault_slug":"` + ownedSlug + `"}]}`))
+			},
+		)
+
+		var body bytes.Buffer
+		writer := multipart.NewWriter(&body)
+		writer.WriteField("format", importers.ChromeCSV)
+		writer.WriteField("vault_slug", otherSlug)
+		file, err := writer.CreateFormFile("file", "passwords.csv")
+		require.NoError(t, err)
+		file.Write([]byte("name,url,username,password,note\nGitHub,,jane,hunter2,\n"))
+		require.NoError(t, writer.Close())
+
+		req := httptest.NewRequest("POST", "/api/entries/import", &body)
+		req.Header.Set("Authorization", "Token " + validTokens[0])
+		req.Header.Set("Client-Operation", utils.ImportEntries)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		req.Header.Set("X-Forwarded-For", helpers.CLIENT_IP)
+		req.Header.Set(conf.PASSWORD_HEADER_KEY, helpers.HexHash1)
+
+		resp, err := stubApp.Test(req, -1)
+		require.NoError(t, err)
+		require.Equal(t, 400, resp.StatusCode)
+		require.False(t, searched)
+
+		helpers.AssertErrorResponseBody(t, resp, &utils.ErrorResponseBody{
+			Detail:      utils.ErrorBadRequest,
+			FieldErrors: map[string][]string{"vault_slug": {"Invalid vault slug."}},
+		})
+
+		var actualLog models.Log
+		helpers.QueryTestLogLatest(t, dbs.Logger, &actualLog)
+		helpers.AssertLog(t, &models.Log{
+			ClientIP:        clientIP,
+			ClientOperation: utils.ImportEntries,
+			Detail:          importers.ChromeCSV,
+			Extra:           otherSlug,
+			Level:           "warn",
+			Message:         utils.ErrorImportEntries,
+			RequestBody:     "[redacted]",
+			UserSlug:        user.Slug,
+		}, &actualLog)
+	})
+}
+
+func testImportParse(t *testing.T, format, data string) (
+	[]importers.Entry, []importers.RowError,
+) {
+	entries, rowErrors, err := importers.Parse(format, []byte(data))
+
+	if err != nil {
+		t.Fatalf("Parse %s failed: %s", format, err.Error())
+	}
+
+	return entries, rowErrors
+}
@@ -0,0 +1,106 @@
+package tests
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/config"
+	"github.com/liobrdev/simplepasswords_api_gateway/databases"
+	"github.com/liobrdev/simplepasswords_api_gateway/models"
+	"github.com/liobrdev/simplepasswords_api_gateway/tests/helpers"
+	"github.com/liobrdev/simplepasswords_api_gateway/tests/setup"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+func testListVaults(
+	t *testing.T, app *fiber.App, dbs *databases.Databases, conf *config.AppConfig,
+) {
+	var clientIP string
+
+	if conf.BEHIND_PROXY {
+		clientIP = helpers.CLIENT_IP
+	} else {
+		clientIP = "0.0.0.0"
+	}
+
+	setup.SetUpLogger(t, dbs)
+	user := setup.SetUpApiGatewayWithData(t, dbs)
+	validTokens := setup.CreateValidTestClientSessions(&user, t, dbs, conf)
+
+	invalidQueries := map[string]map[string][]string{
+		"limit=0":							{"limit": {"Must be an integer from 1 to 100"}},
+		"limit=101":						{"limit": {"Must be an integer from 1 to 100"}},
+		"limit=ten":						{"limit": {"Must be an integer from 1 to 100"}},
+		"cursor=not%20valid":		{"cursor": {"Invalid cursor."}},
+		"sort=name":						{"sort": {"Must be one of title, created, updated, optionally prefixed by '-'"}},
+		"title=%00abc":					{"title": {"Invalid title filter."}},
+		"tag=-work":						{"tag": {"Invalid tag filter."}},
+		"favorite=yes":					{"favorite": {"Must be true or false"}},
+		"limit=0&sort=-name":		{
+			"limit": {"Must be an integer from 1 to 100"},
+			"sort":	 {"Must be one of title, created, updated, optionally prefixed by '-'"},
+		},
+	}
+
+	for query, fieldErrors := range invalidQueries {
+		t.Run("invalid_query_" + query + "_400_bad_request", func(t *testing.T) {
+			testClientOperationHeaderError(
+				t, app, dbs, conf, "GET", "/api/vaults?" + query, "Token " + validTokens[0],
+				utils.ListVaults, 400, utils.ErrorBadRequest, fieldErrors, nil, &models.Log{
+					ClientIP:        clientIP,
+					ClientOperation: utils.ListVaults,
+					Detail:          query,
+					Level:           "warn",
+					Message:         utils.ErrorParams,
+					UserSlug:				 user.Slug,
+				},
+			)
+		})
+	}
+
+	t.Run("query_forwarded_200_ok", func(t *testing.T) {
+		var forwarded url.Values
+		var forwardedUserSlug string
+
+		stubApp := newVaultsStubApp(t, dbs, conf, conf.VAULTS_MAX_RESPONSE_BYTES,
+			func(w http.ResponseWriter, r *http.Request) {
+				forwarded = r.URL.Query()
+				forwardedUserSlug = r.Header.Get("User-Slug")
+
+				w.Header().Set("Content-Type", "application/json")
+				w.Header().Set("X-Next-Cursor", "next_page-2")
+				w.Write([]byte(`{"vaults":[]}`))
+			},
+		)
+
+		req := httptest.NewRequest(
+			"GET", "/api/vaults?limit=20&cursor=page-1&sort=-updated&title=bank%20card", nil,
+		)
+		req.Header.Set("Authorization", "Token " + validTokens[0])
+		req.Header.Set("Client-Operation", utils.ListVaults)
+		req.Header.Set("X-Forwarded-For", helpers.CLIENT_IP)
+
+		resp, err := stubApp.Test(req, -1)
+		require.NoError(t, err)
+		require.Equal(t, 200, resp.StatusCode)
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.Equal(t, `{"vaults":[]}`, string(body))
+		require.Equal(t, "next_page-2", resp.Header.Get("X-Next-Cursor"))
+
+		require.Equal(t, url.Values{
+			"limit":  {"20"},
+			"cursor": {"page-1"},
+			"sort":   {"-updated"},
+			"title":  {"bank card"},
+		}, forwarded)
+		require.Equal(t, user.Slug, forwardedUserSlug)
+	})
+}
@@ -0,0 +1,54 @@
+package tests
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/config"
+	"github.com/liobrdev/simplepasswords_api_gateway/databases"
+	"github.com/liobrdev/simplepasswords_api_gateway/models"
+	"github.com/liobrdev/simplepasswords_api_gateway/tests/helpers"
+	"github.com/liobrdev/simplepasswords_api_gateway/tests/setup"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+func testMatchURL(
+	t *testing.T, app *fiber.App, dbs *databases.Databases, conf *config.AppConfig,
+) {
+	var clientIP string
+
+	if conf.BEHIND_PROXY {
+		clientIP = helpers.CLIENT_IP
+	} else {
+		clientIP = "0.0.0.0"
+	}
+
+	setup.SetUpLogger(t, dbs)
+	user := setup.SetUpApiGatewayWithData(t, dbs)
+	validTokens := setup.CreateValidTestClientSessions(&user, t, dbs, conf)
+
+	invalidURLs := []string{"", "url=example.com", "url=" + url.QueryEscape(
+		"https://example.com/" + strings.Repeat("a", utils.URLMatchMaxLength),
+	)}
+
+	for _, query := range invalidURLs {
+		t.Run("invalid_url_400_bad_request", func(t *testing.T) {
+			testClientOperationHeaderError(
+				t, app, dbs, conf, "GET", "/api/match?" + query, "Token " + validTokens[0],
+				utils.MatchURL, 400, utils.ErrorBadRequest, map[string][]string{
+					"url": {"Must be an http or https URL"},
+				}, nil, &models.Log{
+					ClientIP:        clientIP,
+					ClientOperation: utils.MatchURL,
+					Detail:          query,
+					Level:           "warn",
+					Message:         utils.ErrorParams,
+					UserSlug:        user.Slug,
+				},
+			)
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/config"
+	"github.com/liobrdev/simplepasswords_api_gateway/controllers"
+	"github.com/liobrdev/simplepasswords_api_gateway/databases"
+	"github.com/liobrdev/simplepasswords_api_gateway/models"
+	"github.com/liobrdev/simplepasswords_api_gateway/tests/helpers"
+	"github.com/liobrdev/simplepasswords_api_gateway/tests/setup"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+func testMoveEntry(t *testing.T, app *fiber.App, dbs *databases.Databases, conf *config.AppConfig) {
+	var clientIP string
+
+	if conf.BEHIND_PROXY {
+		clientIP = helpers.CLIENT_IP
+	} else {
+		clientIP = "0.0.0.0"
+	}
+
+	setup.SetUpLogger(t, dbs)
+	user := setup.SetUpApiGatewayWithData(t, dbs)
+	validTokens := setup.CreateValidTestClientSessions(&user, t, dbs, conf)
+	slug := helpers.NewSlug(t)
+	fromVaultSlug := helpers.NewSlug(t)
+
+	doRequest := func(t *testing.T, app *fiber.App, reqBody string) *http.Response {
+		req := httptest.NewRequest("PATCH", "/api/entries/" + slug, strings.NewReader(reqBody))
+		req.Header.Set("Authorization", "Token " + validTokens[0])
+		req.Header.Set("Client-Operation", utils.MoveEntry)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("If-Match", `"1"`)
+		req.Header.Set("X-Forwarded-For", helpers.CLIENT_IP)
+		req.Header.Set(conf.PASSWORD_HEADER_KEY, helpers.HexHash1)
+
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+
+		return resp
+	}
+
+	invalidVaultSlugs := map[string]string{"empty": "", "short": "short", "long": slug + "x"}
+
+	for name, vaultSlug := range invalidVaultSlugs {
+		t.Run("invalid_vault_slug_" + name + "_400_bad_request", func(t *testing.T) {
+			reqBody := `{"vault_slug":"` + vaultSlug + `"}`
+			resp := doRequest(t, app, reqBody)
+			require.Equal(t, 400, resp.StatusCode)
+
+			helpers.AssertErrorResponseBody(t, resp, &utils.ErrorResponseBody{
+				Detail:      utils.ErrorBadRequest,
+				FieldErrors: map[string][]string{"vault_slug": {"Invalid vault slug."}},
+			})
+
+			var actualLog models.Log
+			helpers.QueryTestLogLatest(t, dbs.Logger, &actualLog)
+			helpers.AssertLog(t, &models.Log{
+				ClientIP:        clientIP,
+				ClientOperation: utils.MoveEntry,
+				Detail:          vaultSlug,
+				Extra:           slug,
+				Level:           "warn",
+				Message:         utils.ErrorMoveEntry,
+				RequestBody:     reqBody,
+				UserSlug:        user.Slug,
+			}, &actualLog)
+		})
+	}
+
+	t.Run("vaults_unavailable_503_service_unavailable", func(t *testing.T) {
+		resp := doRequest(t, app, `{"vault_slug":"` + helpers.NewSlug(t) + `"}`)
+		require.Equal(t, 503, resp.StatusCode)
+
+		helpers.AssertErrorResponseBody(t, resp, &utils.ErrorResponseBody{Detail: utils.ErrorServer})
+
+		var actualLog models.Log
+		helpers.QueryTestLogLatest(t, dbs.Logger, &actualLog)
+		require.Equal(t, utils.MoveEntry, actualLog.ClientOperation)
+		require.Equal(t, utils.ErrorVaultsListVaults, actualLog.Message)
+		require.Equal(t, user.Slug, actualLog.UserSlug)
+	})
+
+	// The user owns fromVaultSlug, which holds the entry, and toVaultSlug
+	toVaultSlug := helpers.NewSlug(t)
+	var forwarded *controllers.MoveEntryRequestBody
+
+	stubApp := newVaultsStubApp(t, dbs, conf, conf.VAULTS_MAX_RESPONSE_BYTES,
+		func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/api/vaults":
+				w.Write([]byte(
+					`{"vaults":[{"vault_slug":"` + fromVaultSlug + `"},{"vault_slug":"` + toVaultSlug +
+					`"}]}`,
+				))
+			case "/api/search":
+				w.Write([]byte(`[{"entry_slug":"` + slug + `","vault_slug":"` + fromVaultSlug + `"}]`))
+			default:
+				require.Equal(t, "PATCH", r.Method)
+				require.Equal(t, "/api/entries/" + slug, r.URL.Path)
+
+				forwarded = &controllers.MoveEntryRequestBody{}
+				require.NoError(t, json.NewDecoder(r.Body).Decode(forwarded))
+				w.WriteHeader(204)
+			}
+		},
+	)
+
+	t.Run("target_vault_not_owned_404_not_found", func(t *testing.T) {
+		forwarded = nil
+		reqBody := `{"vault_slug":"` + helpers.NewSlug(t) + `"}`
+		resp := doRequest(t, stubApp, reqBody)
+		require.Equal(t, 404, resp.StatusCode)
+		require.Nil(t, forwarded)
+
+		helpers.AssertErrorResponseBody(t, resp, &utils.ErrorResponseBody{Detail: utils.ErrorNotFound})
+
+		var actualLog models.Log
+		helpers.QueryTestLogLatest(t, dbs.Logger, &actualLog)
+		require.Equal(t, utils.ErrorMoveEntry, actualLog.Message)
+		require.Equal(t, "warn", actualLog.Level)
+	})
+
+	t.Run("same_vault_400_bad_request", func(t *testing.T) {
+		forwarded = nil
+		resp := doRequest(t, stubApp, `{"vault_slug":"` + fromVaultSlug + `"}`)
+		require.Equal(t, 400, resp.StatusCode)
+		require.Nil(t, forwarded)
+
+		helpers.AssertErrorResponseBody(t, resp, &utils.ErrorResponseBody{
+			Detail:      utils.ErrorBadRequest,
+			FieldErrors: map[string][]string{"vault_slug": {"Entry is already in this vault."}},
+		})
+	})
+
+	t.Run("move_204_no_content", func(t *testing.T) {
+		forwarded = nil
+		resp := doRequest(t, stubApp, `{"vault_slug":"` + toVaultSlug + `"}`)
+		require.Equal(t, 204, resp.StatusCode)
+		require.Equal(t, &controllers.MoveEntryRequestBody{VaultSlug: toVaultSlug}, forwarded)
+	})
+}
@@ -0,0 +1,464 @@
+package tests
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/config"
+	"github.com/liobrdev/simplepasswords_api_gateway/controllers"
+	"github.com/liobrdev/simplepasswords_api_gateway/databases"
+	"github.com/liobrdev/simplepasswords_api_gateway/models"
+	"github.com/liobrdev/simplepasswords_api_gateway/tests/helpers"
+	"github.com/liobrdev/simplepasswords_api_gateway/tests/setup"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+func testOrgs(t *testing.T, app *fiber.App, dbs *databases.Databases, conf *config.AppConfig) {
+	setup.SetUpLogger(t, dbs)
+	owner := setup.SetUpApiGatewayWithData(t, dbs)
+	ownerTokens := setup.CreateValidTestClientSessions(&owner, t, dbs, conf)
+
+	hash, salt, err := utils.GenerateUserCredentials(
+		utils.HashToken(helpers.VALID_EMAIL_2 + helpers.VALID_PW_2),
+	)
+	require.NoError(t, err)
+
+	invitee := models.User{
+		Slug:            helpers.NewSlug(t),
+		Name:            helpers.VALID_NAME_2,
+		EmailAddress:    helpers.VALID_EMAIL_2,
+		PhoneNumber:     helpers.VALID_PHONE_2,
+		PasswordHash:    hash,
+		PasswordSalt:    salt,
+		EmailIsVerified: true,
+		PhoneIsVerified: true,
+	}
+	require.NoError(t, dbs.ApiGateway.Create(&invitee).Error)
+	inviteeTokens := setup.CreateValidTestClientSessions(&invitee, t, dbs, conf)
+
+	ownerAuth := "Token " + ownerTokens[0]
+	inviteeAuth := "Token " + inviteeTokens[0]
+	passwords := map[string]string{ownerAuth: helpers.HexHash1, inviteeAuth: helpers.HexHash2}
+
+	doRequest := func(
+		t *testing.T, method, target, clientOperation, authHeader, orgSlug, body string,
+	) (*http.Response, []byte) {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set("Authorization", authHeader)
+		req.Header.Set("Client-Operation", clientOperation)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Forwarded-For", helpers.CLIENT_IP)
+		req.Header.Set(conf.PASSWORD_HEADER_KEY, passwords[authHeader])
+
+		if orgSlug != "" {
+			req.Header.Set("Org-Slug", orgSlug)
+		}
+
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+
+		respBody, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		return resp, respBody
+	}
+
+	assertLogMessage := func(t *testing.T, clientOperation, message string) {
+		var actualLog models.Log
+		helpers.QueryTestLogLatest(t, dbs.Logger, &actualLog)
+		require.Equal(t, clientOperation, actualLog.ClientOperation)
+		require.Equal(t, message, actualLog.Message)
+	}
+
+	t.Run("org_role_at_least", func(t *testing.T) {
+		require.True(t, utils.OrgRoleAtLeast(utils.OrgRoleOwner, utils.OrgRoleAdmin))
+		require.True(t, utils.OrgRoleAtLeast(utils.OrgRoleMember, utils.OrgRoleMember))
+		require.False(t, utils.OrgRoleAtLeast(utils.OrgRoleAdmin, utils.OrgRoleOwner))
+		require.False(t, utils.OrgRoleAtLeast("manage", utils.OrgRoleMember))
+	})
+
+	t.Run("create_org_invalid_400_bad_request", func(t *testing.T) {
+		resp, respBody := doRequest(
+			t, "POST", "/api/orgs", utils.CreateOrg, ownerAuth, "", `{"name":"   "}`,
+		)
+		require.Equal(t, 400, resp.StatusCode)
+		require.Contains(t, string(respBody), "name")
+		assertLogMessage(t, utils.CreateOrg, utils.ErrorOrg)
+	})
+
+	var org controllers.OrgResponse
+
+	t.Run("create_org_201_created", func(t *testing.T) {
+		resp, respBody := doRequest(
+			t, "POST", "/api/orgs", utils.CreateOrg, ownerAuth, "", `{"name":" Acme "}`,
+		)
+		require.Equal(t, 201, resp.StatusCode)
+		require.NoError(t, json.Unmarshal(respBody, &org))
+		require.Equal(t, "Acme", org.Name)
+		require.Equal(t, utils.OrgRoleOwner, org.Role)
+	})
+
+	orgTarget := "/api/orgs/" + org.OrgSlug
+
+	t.Run("list_orgs_200_ok", func(t *testing.T) {
+		resp, respBody := doRequest(t, "GET", "/api/orgs", utils.ListOrgs, ownerAuth, "", "")
+		require.Equal(t, 200, resp.StatusCode)
+
+		var listed controllers.ListOrgsResponseBody
+		require.NoError(t, json.Unmarshal(respBody, &listed))
+		require.Len(t, listed.Orgs, 1)
+		require.Equal(t, org.OrgSlug, listed.Orgs[0].OrgSlug)
+
+		resp, respBody = doRequest(t, "GET", "/api/orgs", utils.ListOrgs, inviteeAuth, "", "")
+		require.Equal(t, 200, resp.StatusCode)
+		require.NoError(t, json.Unmarshal(respBody, &listed))
+		require.Empty(t, listed.Orgs)
+	})
+
+	t.Run("non_member_404_not_found", func(t *testing.T) {
+		resp, _ := doRequest(
+			t, "GET", orgTarget + "/members", utils.ListOrgMembers, inviteeAuth, "", "",
+		)
+		require.Equal(t, 404, resp.StatusCode)
+		assertLogMessage(t, utils.ListOrgMembers, utils.ErrorOrgNotFound)
+	})
+
+	t.Run("invite_invalid_400_bad_request", func(t *testing.T) {
+		resp, respBody := doRequest(
+			t, "POST", orgTarget + "/invitations", utils.InviteOrgMember, ownerAuth, "",
+			`{"email_address":"not an email","role":"manage"}`,
+		)
+		require.Equal(t, 400, resp.StatusCode)
+		require.Contains(t, string(respBody), "email_address")
+		require.Contains(t, string(respBody), "Must be one of: owner, admin, member")
+		assertLogMessage(t, utils.InviteOrgMember, utils.ErrorInvitation)
+	})
+
+	t.Run("invite_existing_member_409_conflict", func(t *testing.T) {
+		resp, respBody := doRequest(
+			t, "POST", orgTarget + "/invitations", utils.InviteOrgMember, ownerAuth, "",
+			`{"email_address":"` + helpers.VALID_EMAIL_1 + `","role":"member"}`,
+		)
+		require.Equal(t, 409, resp.StatusCode)
+		require.Contains(t, string(respBody), utils.ErrorAlreadyOrgMember)
+	})
+
+	var invitation controllers.OrgInvitationResponse
+
+	t.Run("invite_201_created", func(t *testing.T) {
+		for _, role := range []string{utils.OrgRoleAdmin, utils.OrgRoleMember} {
+			resp, respBody := doRequest(
+				t, "POST", orgTarget + "/invitations", utils.InviteOrgMember, ownerAuth, "",
+				`{"email_address":"` + helpers.VALID_EMAIL_2 + `","role":"` + role + `"}`,
+			)
+			require.Equal(t, 201, resp.StatusCode)
+			require.NoError(t, json.Unmarshal(respBody, &invitation))
+			require.Equal(t, role, invitation.Role)
+			require.Equal(t, "Acme", invitation.OrgName)
+		}
+
+		// Inviting the same address again replaces the pending invitation
+		var count int64
+		dbs.ApiGateway.Model(&models.OrgInvitation{}).Count(&count)
+		require.Equal(t, int64(1), count)
+	})
+
+	t.Run("list_invitations_includes_org_invitations", func(t *testing.T) {
+		resp, respBody := doRequest(
+			t, "GET", "/api/invitations", utils.ListInvitations, inviteeAuth, "", "",
+		)
+		require.Equal(t, 200, resp.StatusCode)
+
+		var listed controllers.ListInvitationsResponseBody
+		require.NoError(t, json.Unmarshal(respBody, &listed))
+		require.Empty(t, listed.Invitations)
+		require.Len(t, listed.OrgInvitations, 1)
+		require.Equal(t, invitation.InvitationSlug, listed.OrgInvitations[0].InvitationSlug)
+		require.Equal(t, helpers.VALID_NAME_1, listed.OrgInvitations[0].InviterName)
+	})
+
+	invitationTarget := "/api/org_invitations/" + invitation.InvitationSlug
+
+	t.Run("accept_other_users_invitation_404_not_found", func(t *testing.T) {
+		resp, _ := doRequest(
+			t, "POST", invitationTarget + "/accept", utils.AcceptOrgInvitation, ownerAuth, "", "",
+		)
+		require.Equal(t, 404, resp.StatusCode)
+		assertLogMessage(t, utils.AcceptOrgInvitation, utils.ErrorInvitationNotFound)
+	})
+
+	t.Run("accept_201_created", func(t *testing.T) {
+		resp, respBody := doRequest(
+			t, "POST", invitationTarget + "/accept", utils.AcceptOrgInvitation, inviteeAuth, "", "",
+		)
+		require.Equal(t, 201, resp.StatusCode)
+
+		var joined controllers.OrgResponse
+		require.NoError(t, json.Unmarshal(respBody, &joined))
+		require.Equal(t, org.OrgSlug, joined.OrgSlug)
+		require.Equal(t, utils.OrgRoleMember, joined.Role)
+
+		resp, _ = doRequest(
+			t, "POST", invitationTarget + "/decline", utils.DeclineOrgInvitation, inviteeAuth, "", "",
+		)
+		require.Equal(t, 404, resp.StatusCode)
+	})
+
+	t.Run("member_can't_invite_403_forbidden", func(t *testing.T) {
+		resp, respBody := doRequest(
+			t, "POST", orgTarget + "/invitations", utils.InviteOrgMember, inviteeAuth, "",
+			`{"email_address":"someone@example.com","role":"member"}`,
+		)
+		require.Equal(t, 403, resp.StatusCode)
+		require.Contains(t, string(respBody), utils.ErrorOrgPermission)
+		assertLogMessage(t, utils.InviteOrgMember, utils.ErrorOrgRole)
+	})
+
+	t.Run("revoke_and_decline_invitation", func(t *testing.T) {
+		resp, respBody := doRequest(
+			t, "POST", orgTarget + "/invitations", utils.InviteOrgMember, ownerAuth, "",
+			`{"email_address":"someone@example.com","role":"member"}`,
+		)
+		require.Equal(t, 201, resp.StatusCode)
+
+		var pending controllers.OrgInvitationResponse
+		require.NoError(t, json.Unmarshal(respBody, &pending))
+
+		resp, _ = doRequest(
+			t, "DELETE", orgTarget + "/invitations/" + pending.InvitationSlug,
+			utils.RevokeOrgInvitation, ownerAuth, "", "",
+		)
+		require.Equal(t, 204, resp.StatusCode)
+
+		var count int64
+		dbs.ApiGateway.Model(&models.OrgInvitation{}).Count(&count)
+		require.Equal(t, int64(0), count)
+	})
+
+	t.Run("list_org_members_200_ok", func(t *testing.T) {
+		resp, respBody := doRequest(
+			t, "GET", orgTarget + "/members", utils.ListOrgMembers, inviteeAuth, "", "",
+		)
+		require.Equal(t, 200, resp.StatusCode)
+
+		var listed controllers.ListOrgMembersResponseBody
+		require.NoError(t, json.Unmarshal(respBody, &listed))
+		require.Len(t, listed.Members, 2)
+		require.Nil(t, listed.Invitations)
+	})
+
+	memberTarget := orgTarget + "/members/" + invitee.Slug
+
+	t.Run("update_org_member_without_password_401_unauthorized", func(t *testing.T) {
+		req := httptest.NewRequest("PATCH", memberTarget, strings.NewReader(`{"role":"admin"}`))
+		req.Header.Set("Authorization", ownerAuth)
+		req.Header.Set("Client-Operation", utils.UpdateOrgMember)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Forwarded-For", helpers.CLIENT_IP)
+
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+		require.Equal(t, 401, resp.StatusCode)
+	})
+
+	t.Run("update_org_member", func(t *testing.T) {
+		resp, _ := doRequest(
+			t, "PATCH", memberTarget, utils.UpdateOrgMember, inviteeAuth, "", `{"role":"admin"}`,
+		)
+		require.Equal(t, 403, resp.StatusCode)
+
+		resp, respBody := doRequest(
+			t, "PATCH", memberTarget, utils.UpdateOrgMember, ownerAuth, "", `{"role":"boss"}`,
+		)
+		require.Equal(t, 400, resp.StatusCode)
+		require.Contains(t, string(respBody), "role")
+
+		resp, _ = doRequest(
+			t, "PATCH", orgTarget + "/members/" + helpers.NewSlug(t), utils.UpdateOrgMember,
+			ownerAuth, "", `{"role":"admin"}`,
+		)
+		require.Equal(t, 404, resp.StatusCode)
+
+		resp, respBody = doRequest(
+			t, "PATCH", orgTarget + "/members/" + owner.Slug, utils.UpdateOrgMember, ownerAuth, "",
+			`{"role":"admin"}`,
+		)
+		require.Equal(t, 409, resp.StatusCode)
+		require.Contains(t, string(respBody), utils.ErrorLastOrgOwner)
+	})
+
+	var group controllers.OrgGroupResponse
+
+	t.Run("create_org_group_201_created", func(t *testing.T) {
+		resp, _ := doRequest(
+			t, "POST", orgTarget + "/groups", utils.CreateOrgGroup, inviteeAuth, "",
+			`{"name":"Engineering"}`,
+		)
+		require.Equal(t, 403, resp.StatusCode)
+
+		resp, respBody := doRequest(
+			t, "POST", orgTarget + "/groups", utils.CreateOrgGroup, ownerAuth, "",
+			`{"name":"Engineering"}`,
+		)
+		require.Equal(t, 201, resp.StatusCode)
+		require.NoError(t, json.Unmarshal(respBody, &group))
+		require.Equal(t, "Engineering", group.Name)
+	})
+
+	groupTarget := orgTarget + "/groups/" + group.GroupSlug
+
+	t.Run("add_org_group_member", func(t *testing.T) {
+		resp, _ := doRequest(
+			t, "PUT", groupTarget + "/members/" + helpers.NewSlug(t), utils.AddOrgGroupMember,
+			ownerAuth, "", "",
+		)
+		require.Equal(t, 404, resp.StatusCode)
+
+		for i := 0; i < 2; i++ {
+			resp, _ = doRequest(
+				t, "PUT", groupTarget + "/members/" + invitee.Slug, utils.AddOrgGroupMember,
+				ownerAuth, "", "",
+			)
+			require.Equal(t, 204, resp.StatusCode)
+		}
+
+		resp, respBody := doRequest(
+			t, "GET", orgTarget + "/members", utils.ListOrgMembers, ownerAuth, "", "",
+		)
+		require.Equal(t, 200, resp.StatusCode)
+
+		var listed controllers.ListOrgMembersResponseBody
+		require.NoError(t, json.Unmarshal(respBody, &listed))
+		require.Len(t, listed.Groups, 1)
+
+		for _, member := range listed.Members {
+			if member.UserSlug == invitee.Slug {
+				require.Equal(t, []string{group.GroupSlug}, member.GroupSlugs)
+			} else {
+				require.Empty(t, member.GroupSlugs)
+			}
+		}
+	})
+
+	t.Run("set_org_vault_grant_invalid_400_bad_request", func(t *testing.T) {
+		resp, respBody := doRequest(
+			t, "PUT", groupTarget + "/vaults/" + helpers.NewSlug(t), utils.SetOrgVaultGrant,
+			ownerAuth, "", `{"role":"owner"}`,
+		)
+		require.Equal(t, 400, resp.StatusCode)
+		require.Contains(t, string(respBody), "Must be one of: read, write, manage")
+		assertLogMessage(t, utils.SetOrgVaultGrant, utils.ErrorOrgVaultGrant)
+	})
+
+	// The vaults service isn't reachable in tests, so grant the vault directly
+	readVaultSlug := helpers.NewSlug(t)
+	otherVaultSlug := helpers.NewSlug(t)
+	require.NoError(t, dbs.ApiGateway.Omit("OrgGroup").Create(&models.OrgVaultGrant{
+		GroupSlug: group.GroupSlug, VaultSlug: readVaultSlug, Role: utils.VaultRoleRead,
+	}).Error)
+
+	t.Run("list_org_vaults_200_ok", func(t *testing.T) {
+		resp, respBody := doRequest(
+			t, "GET", orgTarget + "/vaults", utils.ListOrgVaults, inviteeAuth, "", "",
+		)
+		require.Equal(t, 200, resp.StatusCode)
+
+		var listed controllers.ListOrgVaultsResponseBody
+		require.NoError(t, json.Unmarshal(respBody, &listed))
+		require.Equal(t, []controllers.OrgVaultResponse{
+			{VaultSlug: readVaultSlug, Role: utils.VaultRoleRead},
+		}, listed.Vaults)
+		require.Empty(t, listed.Grants)
+
+		resp, respBody = doRequest(
+			t, "GET", orgTarget + "/vaults", utils.ListOrgVaults, ownerAuth, "", "",
+		)
+		require.Equal(t, 200, resp.StatusCode)
+		require.NoError(t, json.Unmarshal(respBody, &listed))
+		require.Equal(t, []controllers.OrgVaultGrantResponse{
+			{GroupSlug: group.GroupSlug, VaultSlug: readVaultSlug, Role: utils.VaultRoleRead},
+		}, listed.Grants)
+	})
+
+	t.Run("policy_non_member_404_not_found", func(t *testing.T) {
+		stranger := models.Organization{
+			Slug: helpers.NewSlug(t), Name: "Other", CreatedAt: time.Now().UTC(),
+		}
+		require.NoError(t, dbs.ApiGateway.Create(&stranger).Error)
+
+		resp, _ := doRequest(
+			t, "GET", "/api/vaults/" + readVaultSlug, utils.RetrieveVault, inviteeAuth,
+			stranger.Slug, "",
+		)
+		require.Equal(t, 404, resp.StatusCode)
+		assertLogMessage(t, utils.RetrieveVault, utils.ErrorOrgNotFound)
+	})
+
+	t.Run("policy_without_grant_403_forbidden", func(t *testing.T) {
+		resp, respBody := doRequest(
+			t, "GET", "/api/vaults/" + otherVaultSlug, utils.RetrieveVault, inviteeAuth,
+			org.OrgSlug, "",
+		)
+		require.Equal(t, 403, resp.StatusCode)
+		require.Contains(t, string(respBody), utils.ErrorVaultPermission)
+		assertLogMessage(t, utils.RetrieveVault, utils.ErrorVaultRole)
+
+		resp, _ = doRequest(
+			t, "PATCH", "/api/vaults/" + readVaultSlug, utils.UpdateVault, inviteeAuth,
+			org.OrgSlug, `{"vault_title":"Renamed"}`,
+		)
+		require.Equal(t, 403, resp.StatusCode)
+		assertLogMessage(t, utils.UpdateVault, utils.ErrorVaultRole)
+	})
+
+	t.Run("policy_with_grant_reaches_vaults", func(t *testing.T) {
+		resp, _ := doRequest(
+			t, "GET", "/api/vaults/" + readVaultSlug, utils.RetrieveVault, inviteeAuth,
+			org.OrgSlug, "",
+		)
+		require.Equal(t, 503, resp.StatusCode)
+	})
+
+	t.Run("remove_org_member", func(t *testing.T) {
+		resp, respBody := doRequest(
+			t, "DELETE", orgTarget + "/members/" + owner.Slug, utils.RemoveOrgMember, ownerAuth,
+			"", "",
+		)
+		require.Equal(t, 409, resp.StatusCode)
+		require.Contains(t, string(respBody), utils.ErrorLastOrgOwner)
+
+		resp, _ = doRequest(t, "DELETE", memberTarget, utils.RemoveOrgMember, ownerAuth, "", "")
+		require.Equal(t, 204, resp.StatusCode)
+
+		var count int64
+		dbs.ApiGateway.Model(&models.OrgGroupMember{}).Count(&count)
+		require.Equal(t, int64(0), count)
+
+		resp, _ = doRequest(
+			t, "GET", "/api/vaults/" + readVaultSlug, utils.RetrieveVault, inviteeAuth,
+			org.OrgSlug, "",
+		)
+		require.Equal(t, 404, resp.StatusCode)
+	})
+
+	t.Run("delete_org_group_204_no_content", func(t *testing.T) {
+		resp, _ := doRequest(t, "DELETE", groupTarget, utils.DeleteOrgGroup, ownerAuth, "", "")
+		require.Equal(t, 204, resp.StatusCode)
+
+		var count int64
+		dbs.ApiGateway.Model(&models.OrgVaultGrant{}).Count(&count)
+		require.Equal(t, int64(0), count)
+
+		resp, _ = doRequest(t, "DELETE", groupTarget, utils.DeleteOrgGroup, ownerAuth, "", "")
+		require.Equal(t, 404, resp.StatusCode)
+		assertLogMessage(t, utils.DeleteOrgGroup, utils.ErrorOrgGroupNotFound)
+	})
+}
@@ -0,0 +1,54 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/config"
+	"github.com/liobrdev/simplepasswords_api_gateway/databases"
+	"github.com/liobrdev/simplepasswords_api_gateway/models"
+	"github.com/liobrdev/simplepasswords_api_gateway/tests/helpers"
+	"github.com/liobrdev/simplepasswords_api_gateway/tests/setup"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+func testSearchEntries(
+	t *testing.T, app *fiber.App, dbs *databases.Databases, conf *config.AppConfig,
+) {
+	var clientIP string
+
+	if conf.BEHIND_PROXY {
+		clientIP = helpers.CLIENT_IP
+	} else {
+		clientIP = "0.0.0.0"
+	}
+
+	setup.SetUpLogger(t, dbs)
+	user := setup.SetUpApiGatewayWithData(t, dbs)
+	validTokens := setup.CreateValidTestClientSessions(&user, t, dbs, conf)
+
+	invalidQueries := map[string]map[string][]string{
+		"":											{"q": {"Must be 1 to 100 characters"}},
+		"q=":										{"q": {"Must be 1 to 100 characters"}},
+		"q=bank&match=regex":		{"match": {"Must be one of prefix, fuzzy"}},
+		"q=bank&limit=51":			{"limit": {"Must be an integer from 1 to 50"}},
+		"q=bank&tag=a%2Fb":			{"tag": {"Invalid tag filter."}},
+	}
+
+	for query, fieldErrors := range invalidQueries {
+		t.Run("invalid_query_" + query + "_400_bad_request", func(t *testing.T) {
+			testClientOperationHeaderError(
+				t, app, dbs, conf, "GET", "/api/search?" + query, "Token " + validTokens[0],
+				utils.SearchEntries, 400, utils.ErrorBadRequest, fieldErrors, nil, &models.Log{
+					ClientIP:        clientIP,
+					ClientOperation: utils.SearchEntries,
+					Detail:          query,
+					Level:           "warn",
+					Message:         utils.ErrorParams,
+					UserSlug:				 user.Slug,
+				},
+			)
+		})
+	}
+}
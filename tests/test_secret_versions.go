@@ -0,0 +1,78 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/config"
+	"github.com/liobrdev/simplepasswords_api_gateway/databases"
+	"github.com/liobrdev/simplepasswords_api_gateway/models"
+	"github.com/liobrdev/simplepasswords_api_gateway/tests/helpers"
+	"github.com/liobrdev/simplepasswords_api_gateway/tests/setup"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+func testSecretVersions(
+	t *testing.T, app *fiber.App, dbs *databases.Databases, conf *config.AppConfig,
+) {
+	var clientIP string
+
+	if conf.BEHIND_PROXY {
+		clientIP = helpers.CLIENT_IP
+	} else {
+		clientIP = "0.0.0.0"
+	}
+
+	setup.SetUpLogger(t, dbs)
+	user := setup.SetUpApiGatewayWithData(t, dbs)
+	validTokens := setup.CreateValidTestClientSessions(&user, t, dbs, conf)
+	slug := helpers.NewSlug(t)
+
+	for _, version := range []string{"0", "-1", "latest"} {
+		t.Run("restore_invalid_version_" + version + "_400_bad_request", func(t *testing.T) {
+			testClientOperationHeaderError(
+				t, app, dbs, conf, "POST", "/api/secrets/" + slug + "/versions/" + version + "/restore",
+				"Token " + validTokens[0], utils.RestoreSecretVersion, 400, utils.ErrorBadRequest,
+				map[string][]string{"version": {"Must be a positive integer"}}, nil, &models.Log{
+					ClientIP:        clientIP,
+					ClientOperation: utils.RestoreSecretVersion,
+					Detail:          slug,
+					Extra:           version,
+					Level:           "warn",
+					Message:         utils.ErrorParams,
+					UserSlug:				 user.Slug,
+				},
+			)
+		})
+	}
+
+	t.Run("restore_missing_if_match_428_precondition_required", func(t *testing.T) {
+		testClientOperationHeaderError(
+			t, app, dbs, conf, "POST", "/api/secrets/" + slug + "/versions/1/restore",
+			"Token " + validTokens[0], utils.RestoreSecretVersion, 428,
+			utils.ErrorPreconditionRequired, nil, nil, &models.Log{
+				ClientIP:        clientIP,
+				ClientOperation: utils.RestoreSecretVersion,
+				Extra:           slug,
+				Level:           "warn",
+				Message:         utils.ErrorIfMatch,
+				UserSlug:				 user.Slug,
+			},
+		)
+	})
+
+	t.Run("list_invalid_slug_400_bad_request", func(t *testing.T) {
+		testClientOperationHeaderError(
+			t, app, dbs, conf, "GET", "/api/secrets/not-a-slug/versions", "Token " + validTokens[0],
+			utils.ListSecretVersions, 400, utils.ErrorBadRequest, nil, nil, &models.Log{
+				ClientIP:        clientIP,
+				ClientOperation: utils.ListSecretVersions,
+				Detail:          "not-a-slug",
+				Level:           "warn",
+				Message:         utils.ErrorParams,
+				UserSlug:				 user.Slug,
+			},
+		)
+	})
+}
@@ -0,0 +1,263 @@
+package tests
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/config"
+	"github.com/liobrdev/simplepasswords_api_gateway/controllers"
+	"github.com/liobrdev/simplepasswords_api_gateway/databases"
+	"github.com/liobrdev/simplepasswords_api_gateway/models"
+	"github.com/liobrdev/simplepasswords_api_gateway/tests/helpers"
+	"github.com/liobrdev/simplepasswords_api_gateway/tests/setup"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+func testShares(
+	t *testing.T, app *fiber.App, dbs *databases.Databases, conf *config.AppConfig,
+) {
+	var clientIP string
+
+	if conf.BEHIND_PROXY {
+		clientIP = helpers.CLIENT_IP
+	} else {
+		clientIP = "0.0.0.0"
+	}
+
+	setup.SetUpLogger(t, dbs)
+	user := setup.SetUpApiGatewayWithData(t, dbs)
+	validTokens := setup.CreateValidTestClientSessions(&user, t, dbs, conf)
+
+	doRequest := func(
+		t *testing.T, method, target, clientOperation, authHeader, body string,
+	) (*http.Response, []byte) {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set("Client-Operation", clientOperation)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Forwarded-For", helpers.CLIENT_IP)
+		req.Header.Set(conf.PASSWORD_HEADER_KEY, helpers.HexHash1)
+
+		if authHeader != "" {
+			req.Header.Set("Authorization", authHeader)
+		}
+
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+
+		respBody, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		return resp, respBody
+	}
+
+	authHeader := "Token " + validTokens[0]
+
+	invalidShares := map[string]map[string][]string{
+		`{"ciphertext":""}`: {"ciphertext": {"Must be base64, at most 16384 characters"}},
+		`{"ciphertext":"not base64!"}`: {"ciphertext": {"Must be base64, at most 16384 characters"}},
+		`{"ciphertext":"` + strings.Repeat("A", 16388) + `"}`: {
+			"ciphertext": {"Must be base64, at most 16384 characters"},
+		},
+		`{"ciphertext":"AAAA","label":"` + strings.Repeat("l", 65) + `"}`: {
+			"label": {"Must be at most 64 characters"},
+		},
+		`{"ciphertext":"AAAA","expires_in":60}`: {
+			"expires_in": {"Must be from 300 to 604800 seconds"},
+		},
+		`{"ciphertext":"AAAA","max_views":11}`: {"max_views": {"Must be from 1 to 10"}},
+	}
+
+	for body, fieldErrors := range invalidShares {
+		t.Run("create_invalid_400_bad_request", func(t *testing.T) {
+			resp, respBody := doRequest(t, "POST", "/api/shares", utils.CreateShare, authHeader, body)
+			require.Equal(t, 400, resp.StatusCode)
+
+			var errorBody utils.ErrorResponseBody
+			require.NoError(t, json.Unmarshal(respBody, &errorBody))
+			require.Equal(t, fieldErrors, errorBody.FieldErrors)
+
+			var actualLog models.Log
+			helpers.QueryTestLogLatest(t, dbs.Logger, &actualLog)
+			helpers.AssertLog(t, &models.Log{
+				ClientIP:        clientIP,
+				ClientOperation: utils.CreateShare,
+				Level:           "warn",
+				Message:         utils.ErrorShare,
+				RequestBody:     body,
+				UserSlug:        user.Slug,
+			}, &actualLog)
+		})
+	}
+
+	var share models.Share
+
+	t.Run("create_201_created", func(t *testing.T) {
+		resp, respBody := doRequest(
+			t, "POST", "/api/shares", utils.CreateShare, authHeader,
+			`{"ciphertext":"c2VjcmV0","label":"Wi-Fi","max_views":2,"expires_in":3600}`,
+		)
+		require.Equal(t, 201, resp.StatusCode)
+		require.NotContains(t, string(respBody), "c2VjcmV0")
+		require.NoError(t, json.Unmarshal(respBody, &share))
+		require.Regexp(t, utils.ShareSlugRegexp, share.Slug)
+		require.Equal(t, "Wi-Fi", share.Label)
+		require.Equal(t, 2, share.MaxViews)
+		require.Equal(t, 2, share.ViewsLeft)
+		require.WithinDuration(t, time.Now().Add(time.Hour), share.ExpiresAt, time.Minute)
+	})
+
+	t.Run("create_defaults", func(t *testing.T) {
+		resp, respBody := doRequest(
+			t, "POST", "/api/shares", utils.CreateShare, authHeader, `{"ciphertext":"AAAA"}`,
+		)
+		require.Equal(t, 201, resp.StatusCode)
+
+		var created models.Share
+		require.NoError(t, json.Unmarshal(respBody, &created))
+		require.Equal(t, 1, created.ViewsLeft)
+		require.WithinDuration(t, time.Now().Add(24 * time.Hour), created.ExpiresAt, time.Minute)
+	})
+
+	t.Run("list_200_ok", func(t *testing.T) {
+		resp, respBody := doRequest(t, "GET", "/api/shares", utils.ListShares, authHeader, "")
+		require.Equal(t, 200, resp.StatusCode)
+		require.NotContains(t, string(respBody), "c2VjcmV0")
+
+		var listed controllers.ListSharesResponseBody
+		require.NoError(t, json.Unmarshal(respBody, &listed))
+		require.Len(t, listed.Shares, 2)
+		require.Equal(t, share.Slug, listed.Shares[1].Slug)
+	})
+
+	openTarget := func(slug string) string {
+		return "/api/shares/" + slug + "/open"
+	}
+
+	t.Run("open_until_used_up", func(t *testing.T) {
+		for viewsLeft := 1; viewsLeft >= 0; viewsLeft-- {
+			resp, respBody := doRequest(t, "POST", openTarget(share.Slug), utils.OpenShare, "", "")
+			require.Equal(t, 200, resp.StatusCode)
+			require.Equal(t, "no-store", resp.Header.Get("Cache-Control"))
+
+			var opened controllers.OpenShareResponseBody
+			require.NoError(t, json.Unmarshal(respBody, &opened))
+			require.Equal(t, "c2VjcmV0", opened.Ciphertext)
+			require.Equal(t, viewsLeft, opened.ViewsLeft)
+		}
+
+		var count int64
+		dbs.ApiGateway.Model(&models.Share{}).Where("slug = ?", share.Slug).Count(&count)
+		require.Zero(t, count)
+
+		resp, _ := doRequest(t, "POST", openTarget(share.Slug), utils.OpenShare, "", "")
+		require.Equal(t, 404, resp.StatusCode)
+
+		var actualLog models.Log
+		helpers.QueryTestLogLatest(t, dbs.Logger, &actualLog)
+		helpers.AssertLog(t, &models.Log{
+			ClientIP:        clientIP,
+			ClientOperation: utils.OpenShare,
+			Level:           "warn",
+			Message:         utils.ErrorShareNotFound,
+		}, &actualLog)
+	})
+
+	t.Run("open_expired_404_not_found", func(t *testing.T) {
+		expired := models.Share{
+			Slug:       strings.Repeat("e", utils.ShareSlugLength),
+			UserSlug:   user.Slug,
+			Ciphertext: "AAAA",
+			MaxViews:   1,
+			ViewsLeft:  1,
+			CreatedAt:  time.Now().Add(-2 * time.Hour),
+			ExpiresAt:  time.Now().Add(-time.Hour),
+		}
+		require.NoError(t, dbs.ApiGateway.Create(&expired).Error)
+
+		resp, _ := doRequest(t, "POST", openTarget(expired.Slug), utils.OpenShare, "", "")
+		require.Equal(t, 404, resp.StatusCode)
+
+		var count int64
+		dbs.ApiGateway.Model(&models.Share{}).Where("slug = ?", expired.Slug).Count(&count)
+		require.Zero(t, count)
+	})
+
+	t.Run("open_invalid_slug_404_not_found", func(t *testing.T) {
+		resp, _ := doRequest(t, "POST", openTarget("short"), utils.OpenShare, "", "")
+		require.Equal(t, 404, resp.StatusCode)
+	})
+
+	t.Run("revoke", func(t *testing.T) {
+		resp, respBody := doRequest(
+			t, "POST", "/api/shares", utils.CreateShare, authHeader, `{"ciphertext":"AAAA"}`,
+		)
+		require.Equal(t, 201, resp.StatusCode)
+
+		var created models.Share
+		require.NoError(t, json.Unmarshal(respBody, &created))
+
+		resp, _ = doRequest(
+			t, "DELETE", "/api/shares/" + created.Slug, utils.RevokeShare, authHeader, "",
+		)
+		require.Equal(t, 204, resp.StatusCode)
+
+		resp, _ = doRequest(
+			t, "DELETE", "/api/shares/" + created.Slug, utils.RevokeShare, authHeader, "",
+		)
+		require.Equal(t, 404, resp.StatusCode)
+
+		var actualLog models.Log
+		helpers.QueryTestLogLatest(t, dbs.Logger, &actualLog)
+		helpers.AssertLog(t, &models.Log{
+			ClientIP:        clientIP,
+			ClientOperation: utils.RevokeShare,
+			Detail:          created.Slug,
+			Level:           "warn",
+			Message:         utils.ErrorShareNotFound,
+			UserSlug:        user.Slug,
+		}, &actualLog)
+
+		resp, _ = doRequest(t, "POST", openTarget(created.Slug), utils.OpenShare, "", "")
+		require.Equal(t, 404, resp.StatusCode)
+	})
+
+	t.Run("create_limit_400_bad_request", func(t *testing.T) {
+		for i := 0; i < utils.SharesPerUserMax; i++ {
+			slug, err := utils.GenerateSlug(utils.ShareSlugLength)
+			require.NoError(t, err)
+			require.NoError(t, dbs.ApiGateway.Create(&models.Share{
+				Slug:       slug,
+				UserSlug:   user.Slug,
+				Ciphertext: "AAAA",
+				MaxViews:   1,
+				ViewsLeft:  1,
+				CreatedAt:  time.Now(),
+				ExpiresAt:  time.Now().Add(time.Hour),
+			}).Error)
+		}
+
+		resp, _ := doRequest(
+			t, "POST", "/api/shares", utils.CreateShare, authHeader, `{"ciphertext":"AAAA"}`,
+		)
+		require.Equal(t, 400, resp.StatusCode)
+
+		var actualLog models.Log
+		helpers.QueryTestLogLatest(t, dbs.Logger, &actualLog)
+		helpers.AssertLog(t, &models.Log{
+			ClientIP:        clientIP,
+			ClientOperation: utils.CreateShare,
+			Level:           "warn",
+			Message:         utils.ErrorShareLimit,
+			RequestBody:     `{"ciphertext":"AAAA"}`,
+			UserSlug:        user.Slug,
+		}, &actualLog)
+	})
+}
@@ -0,0 +1,149 @@
+package tests
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/app"
+	"github.com/liobrdev/simplepasswords_api_gateway/breached"
+	"github.com/liobrdev/simplepasswords_api_gateway/config"
+	"github.com/liobrdev/simplepasswords_api_gateway/databases"
+	"github.com/liobrdev/simplepasswords_api_gateway/models"
+	"github.com/liobrdev/simplepasswords_api_gateway/routes"
+	"github.com/liobrdev/simplepasswords_api_gateway/tests/helpers"
+	"github.com/liobrdev/simplepasswords_api_gateway/tests/setup"
+	"github.com/liobrdev/simplepasswords_api_gateway/upstreams"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+// newVaultsStubApp returns an app whose only vaults upstream is handler, which must not serve
+// /readyz itself. The upstream is healthy by the time it returns.
+func newVaultsStubApp(
+	t *testing.T, dbs *databases.Databases, conf *config.AppConfig, maxResponseBytes int64,
+	handler http.HandlerFunc,
+) *fiber.App {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/readyz" {
+			w.WriteHeader(200)
+		} else {
+			handler(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	stubConf := *conf
+	stubConf.VAULTS_UPSTREAMS = []string{strings.TrimPrefix(server.URL, "http://")}
+	stubConf.VAULTS_UPSTREAMS_FILE = ""
+	stubConf.VAULTS_MAX_RESPONSE_BYTES = maxResponseBytes
+
+	pool := upstreams.Init(&stubConf)
+	go pool.RunHealthChecks()
+	require.Eventually(t, pool.Ready, 5 * time.Second, 10 * time.Millisecond)
+
+	stubApp := app.CreateApp(&stubConf)
+	routes.Register(stubApp, dbs, pool, breached.Init(&stubConf), &stubConf)
+
+	return stubApp
+}
+
+func testStreamVaultsResponse(
+	t *testing.T, app *fiber.App, dbs *databases.Databases, conf *config.AppConfig,
+) {
+	var clientIP string
+
+	if conf.BEHIND_PROXY {
+		clientIP = helpers.CLIENT_IP
+	} else {
+		clientIP = "0.0.0.0"
+	}
+
+	setup.SetUpLogger(t, dbs)
+	user := setup.SetUpApiGatewayWithData(t, dbs)
+	validTokens := setup.CreateValidTestClientSessions(&user, t, dbs, conf)
+
+	const maxResponseBytes = 64
+
+	// The title filter picks the size of the body, and chunked leaves out Content-Length so
+	// that only the limit on the stream itself can catch it
+	stubApp := newVaultsStubApp(t, dbs, conf, maxResponseBytes,
+		func(w http.ResponseWriter, r *http.Request) {
+			size := maxResponseBytes
+
+			if strings.HasPrefix(r.URL.Query().Get("title"), "over") {
+				size++
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("ETag", `"7"`)
+			w.Header().Set("X-Next-Cursor", "abc123")
+			w.Header().Set("X-Internal", "secret")
+
+			if strings.HasSuffix(r.URL.Query().Get("title"), "chunked") {
+				w.WriteHeader(200)
+				w.(http.Flusher).Flush()
+			}
+
+			w.Write([]byte(strings.Repeat("a", size)))
+		},
+	)
+
+	doRequest := func(t *testing.T, title string) (*http.Response, error) {
+		req := httptest.NewRequest("GET", "/api/vaults?title=" + title, nil)
+		req.Header.Set("Authorization", "Token " + validTokens[0])
+		req.Header.Set("Client-Operation", utils.ListVaults)
+		req.Header.Set("X-Forwarded-For", helpers.CLIENT_IP)
+
+		return stubApp.Test(req, -1)
+	}
+
+	for _, title := range []string{"exact", "exact_chunked"} {
+		t.Run("body_at_limit_" + title + "_200_ok", func(t *testing.T) {
+			resp, err := doRequest(t, title)
+			require.NoError(t, err)
+			require.Equal(t, 200, resp.StatusCode)
+
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			require.Equal(t, strings.Repeat("a", maxResponseBytes), string(body))
+
+			require.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+			require.Equal(t, `"7"`, resp.Header.Get("ETag"))
+			require.Equal(t, "abc123", resp.Header.Get("X-Next-Cursor"))
+			require.Empty(t, resp.Header.Get("X-Internal"))
+		})
+	}
+
+	t.Run("content_length_over_limit_500_internal_server_error", func(t *testing.T) {
+		resp, err := doRequest(t, "over")
+		require.NoError(t, err)
+		require.Equal(t, 500, resp.StatusCode)
+
+		helpers.AssertErrorResponseBody(t, resp, &utils.ErrorResponseBody{Detail: utils.ErrorServer})
+		require.Empty(t, resp.Header.Get("X-Next-Cursor"))
+
+		var actualLog models.Log
+		helpers.QueryTestLogLatest(t, dbs.Logger, &actualLog)
+		helpers.AssertLog(t, &models.Log{
+			ClientIP:        clientIP,
+			ClientOperation: utils.ListVaults,
+			Detail:          "vaults response exceeds VAULTS_MAX_RESPONSE_BYTES;;65;;",
+			Level:           "error",
+			Message:         utils.ErrorVaultsListVaults,
+			UserSlug:        user.Slug,
+		}, &actualLog)
+	})
+
+	t.Run("chunked_over_limit_cut_short", func(t *testing.T) {
+		// Headers are already out when the stream passes the limit, so the gateway can only
+		// abort the response
+		_, err := doRequest(t, "over_chunked")
+		require.ErrorContains(t, err, "vaults response exceeds VAULTS_MAX_RESPONSE_BYTES")
+	})
+}
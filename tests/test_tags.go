@@ -0,0 +1,116 @@
+package tests
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/config"
+	"github.com/liobrdev/simplepasswords_api_gateway/databases"
+	"github.com/liobrdev/simplepasswords_api_gateway/models"
+	"github.com/liobrdev/simplepasswords_api_gateway/tests/helpers"
+	"github.com/liobrdev/simplepasswords_api_gateway/tests/setup"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+func testTags(t *testing.T, app *fiber.App, dbs *databases.Databases, conf *config.AppConfig) {
+	var clientIP string
+
+	if conf.BEHIND_PROXY {
+		clientIP = helpers.CLIENT_IP
+	} else {
+		clientIP = "0.0.0.0"
+	}
+
+	setup.SetUpLogger(t, dbs)
+	user := setup.SetUpApiGatewayWithData(t, dbs)
+	validTokens := setup.CreateValidTestClientSessions(&user, t, dbs, conf)
+	slug := helpers.NewSlug(t)
+	tagFieldError := "Must be 1 to 32 letters, digits, spaces, '-' or '_', starting with a " +
+		"letter or digit"
+
+	for _, tag := range []string{"", "-work", strings.Repeat("a", 33)} {
+		t.Run("add_tag_invalid_tag_400_bad_request", func(t *testing.T) {
+			reqBody := `{"tag":"` + tag + `"}`
+			req := httptest.NewRequest(
+				"POST", "/api/entries/" + slug + "/tags", strings.NewReader(reqBody),
+			)
+			req.Header.Set("Authorization", "Token " + validTokens[0])
+			req.Header.Set("Client-Operation", utils.AddTag)
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("X-Forwarded-For", helpers.CLIENT_IP)
+
+			resp, err := app.Test(req, -1)
+			require.NoError(t, err)
+			require.Equal(t, 400, resp.StatusCode)
+
+			helpers.AssertErrorResponseBody(t, resp, &utils.ErrorResponseBody{
+				Detail:      utils.ErrorBadRequest,
+				FieldErrors: map[string][]string{"tag": {tagFieldError}},
+			})
+
+			var actualLog models.Log
+			helpers.QueryTestLogLatest(t, dbs.Logger, &actualLog)
+			helpers.AssertLog(t, &models.Log{
+				ClientIP:        clientIP,
+				ClientOperation: utils.AddTag,
+				Detail:          tag,
+				Extra:           slug,
+				Level:           "warn",
+				Message:         utils.ErrorTag,
+				RequestBody:     reqBody,
+				UserSlug:        user.Slug,
+			}, &actualLog)
+		})
+	}
+
+	t.Run("remove_tag_invalid_tag_400_bad_request", func(t *testing.T) {
+		testClientOperationHeaderError(
+			t, app, dbs, conf, "DELETE", "/api/entries/" + slug + "/tags/-work",
+			"Token " + validTokens[0], utils.RemoveTag, 400, utils.ErrorBadRequest,
+			map[string][]string{"tag": {tagFieldError}}, nil, &models.Log{
+				ClientIP:        clientIP,
+				ClientOperation: utils.RemoveTag,
+				Detail:          "-work",
+				Extra:           slug,
+				Level:           "warn",
+				Message:         utils.ErrorTag,
+				UserSlug:        user.Slug,
+			},
+		)
+	})
+
+	t.Run("set_favorite_missing_favorite_400_bad_request", func(t *testing.T) {
+		req := httptest.NewRequest(
+			"PUT", "/api/entries/" + slug + "/favorite", strings.NewReader("{}"),
+		)
+		req.Header.Set("Authorization", "Token " + validTokens[0])
+		req.Header.Set("Client-Operation", utils.SetFavorite)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Forwarded-For", helpers.CLIENT_IP)
+
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+		require.Equal(t, 400, resp.StatusCode)
+
+		helpers.AssertErrorResponseBody(t, resp, &utils.ErrorResponseBody{
+			Detail:      utils.ErrorBadRequest,
+			FieldErrors: map[string][]string{"favorite": {"Must be true or false"}},
+		})
+
+		var actualLog models.Log
+		helpers.QueryTestLogLatest(t, dbs.Logger, &actualLog)
+		helpers.AssertLog(t, &models.Log{
+			ClientIP:        clientIP,
+			ClientOperation: utils.SetFavorite,
+			Extra:           slug,
+			Level:           "warn",
+			Message:         utils.ErrorSetFavorite,
+			RequestBody:     "{}",
+			UserSlug:        user.Slug,
+		}, &actualLog)
+	})
+}
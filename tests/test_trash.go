@@ -0,0 +1,96 @@
+package tests
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/config"
+	"github.com/liobrdev/simplepasswords_api_gateway/databases"
+	"github.com/liobrdev/simplepasswords_api_gateway/models"
+	"github.com/liobrdev/simplepasswords_api_gateway/tests/helpers"
+	"github.com/liobrdev/simplepasswords_api_gateway/tests/setup"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+func testTrash(t *testing.T, app *fiber.App, dbs *databases.Databases, conf *config.AppConfig) {
+	var clientIP string
+
+	if conf.BEHIND_PROXY {
+		clientIP = helpers.CLIENT_IP
+	} else {
+		clientIP = "0.0.0.0"
+	}
+
+	setup.SetUpLogger(t, dbs)
+	user := setup.SetUpApiGatewayWithData(t, dbs)
+	validTokens := setup.CreateValidTestClientSessions(&user, t, dbs, conf)
+	slug := helpers.NewSlug(t)
+
+	invalidTargets := map[[2]string]map[string][]string{
+		{"user", slug}:
+			{"item_type": {"Must be one of vault, entry, secret"}},
+		{"entry", "short"}:
+			{"item_slug": {"Invalid slug"}},
+		{"folder", "short"}:
+			{"item_type": {"Must be one of vault, entry, secret"}, "item_slug": {"Invalid slug"}},
+	}
+
+	for target, fieldErrors := range invalidTargets {
+		t.Run("restore_item_" + target[0] + "_400_bad_request", func(t *testing.T) {
+			testClientOperationHeaderError(
+				t, app, dbs, conf, "POST", "/api/trash/" + target[0] + "/" + target[1] + "/restore",
+				"Token " + validTokens[0], utils.RestoreItem, 400, utils.ErrorBadRequest, fieldErrors,
+				nil, &models.Log{
+					ClientIP:        clientIP,
+					ClientOperation: utils.RestoreItem,
+					Detail:          target[0],
+					Extra:           target[1],
+					Level:           "warn",
+					Message:         utils.ErrorParams,
+					UserSlug:				 user.Slug,
+				},
+			)
+		})
+	}
+
+	missingSlug, staleSlug := helpers.NewSlug(t), helpers.NewSlug(t)
+
+	stubApp := newVaultsStubApp(t, dbs, conf, conf.VAULTS_MAX_RESPONSE_BYTES,
+		func(w http.ResponseWriter, r *http.Request) {
+			if strings.Contains(r.URL.Path, missingSlug) {
+				w.WriteHeader(404)
+			} else if strings.Contains(r.URL.Path, staleSlug) {
+				w.WriteHeader(412)
+			} else {
+				w.WriteHeader(400)
+			}
+		},
+	)
+
+	vaultsStatuses := []struct {
+		name   string
+		slug   string
+		status int
+		detail string
+	}{
+		{"404_not_found", missingSlug, 404, utils.ErrorNotFound},
+		{"412_precondition_failed", staleSlug, 412, utils.ErrorPreconditionFailed},
+		{"400_500_internal_server_error", slug, 500, utils.ErrorServer},
+	}
+
+	for _, tc := range vaultsStatuses {
+		t.Run("restore_item_vaults_" + tc.name, func(t *testing.T) {
+			resp := newRequestGeneric(
+				t, stubApp, conf, "POST", "/api/trash/entry/" + tc.slug + "/restore",
+				"Token " + validTokens[0], utils.RestoreItem,
+			)
+			require.Equal(t, tc.status, resp.StatusCode)
+
+			helpers.AssertErrorResponseBody(t, resp, &utils.ErrorResponseBody{Detail: tc.detail})
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package tests
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/config"
+	"github.com/liobrdev/simplepasswords_api_gateway/databases"
+	"github.com/liobrdev/simplepasswords_api_gateway/tests/setup"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+func testVaultHealthReport(
+	t *testing.T, app *fiber.App, dbs *databases.Databases, conf *config.AppConfig,
+) {
+	t.Run("stale_by_secret_timestamp", func(t *testing.T) {
+		setup.SetUpLogger(t, dbs)
+		user := setup.SetUpApiGatewayWithData(t, dbs)
+		validTokens := setup.CreateValidTestClientSessions(&user, t, dbs, conf)
+		recent, old := time.Now().UTC().AddDate(0, -1, 0), time.Now().UTC().AddDate(-3, 0, 0)
+
+		// "old_secret" had its title edited recently, "new_secret" its password changed
+		entries := map[string]map[string]interface{}{
+			"old_secret": {"updated_at": recent, "secret_updated_at": old},
+			"new_secret": {"updated_at": old, "secret_updated_at": recent},
+		}
+
+		stubApp := newVaultsStubApp(t, dbs, conf, conf.VAULTS_MAX_RESPONSE_BYTES,
+			func(w http.ResponseWriter, r *http.Request) {
+				var body interface{}
+
+				switch r.URL.Path {
+				case "/api/vaults":
+					body = map[string]interface{}{"vaults": []map[string]string{{"vault_slug": "v"}}}
+				case "/api/vaults/v":
+					body = map[string]interface{}{
+						"vault_slug": "v",
+						"entries": []map[string]string{
+							{"entry_slug": "old_secret"}, {"entry_slug": "new_secret"},
+						},
+					}
+				default:
+					slug := r.URL.Path[len("/api/entries/"):]
+					body = map[string]interface{}{
+						"entry_slug": slug,
+						"updated_at": entries[slug]["updated_at"],
+						"secrets": []map[string]interface{}{{
+							"secret_label":  "Password",
+							"secret_string": "kP9#vW2!qZ7$mL4@" + slug,
+							"updated_at":    entries[slug]["secret_updated_at"],
+						}},
+					}
+				}
+
+				w.Header().Set("Content-Type", "application/json")
+				json.NewEncoder(w).Encode(body)
+			},
+		)
+
+		resp := newRequestGeneric(
+			t, stubApp, conf, "GET", "/api/health_report", "Token " + validTokens[0],
+			utils.VaultHealthReport,
+		)
+		require.Equal(t, 200, resp.StatusCode)
+
+		var report utils.HealthReport
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&report))
+		require.Equal(t, 2, report.TotalPasswords)
+		require.Len(t, report.StaleEntries, 1)
+		require.Equal(t, "old_secret", report.StaleEntries[0].EntrySlug)
+	})
+}
@@ -0,0 +1,445 @@
+package tests
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/config"
+	"github.com/liobrdev/simplepasswords_api_gateway/controllers"
+	"github.com/liobrdev/simplepasswords_api_gateway/databases"
+	"github.com/liobrdev/simplepasswords_api_gateway/models"
+	"github.com/liobrdev/simplepasswords_api_gateway/tests/helpers"
+	"github.com/liobrdev/simplepasswords_api_gateway/tests/setup"
+	"github.com/liobrdev/simplepasswords_api_gateway/utils"
+)
+
+func testVaultSharing(
+	t *testing.T, app *fiber.App, dbs *databases.Databases, conf *config.AppConfig,
+) {
+	setup.SetUpLogger(t, dbs)
+	owner := setup.SetUpApiGatewayWithData(t, dbs)
+	ownerTokens := setup.CreateValidTestClientSessions(&owner, t, dbs, conf)
+
+	hash, salt, err := utils.GenerateUserCredentials(
+		utils.HashToken(helpers.VALID_EMAIL_2 + helpers.VALID_PW_2),
+	)
+	require.NoError(t, err)
+
+	invitee := models.User{
+		Slug:            helpers.NewSlug(t),
+		Name:            helpers.VALID_NAME_2,
+		EmailAddress:    helpers.VALID_EMAIL_2,
+		PhoneNumber:     helpers.VALID_PHONE_2,
+		PasswordHash:    hash,
+		PasswordSalt:    salt,
+		EmailIsVerified: true,
+		PhoneIsVerified: true,
+	}
+	require.NoError(t, dbs.ApiGateway.Create(&invitee).Error)
+	inviteeTokens := setup.CreateValidTestClientSessions(&invitee, t, dbs, conf)
+
+	ownerAuth := "Token " + ownerTokens[0]
+	inviteeAuth := "Token " + inviteeTokens[0]
+
+	doRequest := func(
+		t *testing.T, method, target, clientOperation, authHeader, password, body string,
+	) (*http.Response, []byte) {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set("Authorization", authHeader)
+		req.Header.Set("Client-Operation", clientOperation)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Forwarded-For", helpers.CLIENT_IP)
+		req.Header.Set(conf.PASSWORD_HEADER_KEY, password)
+
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+
+		respBody, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		return resp, respBody
+	}
+
+	assertLogMessage := func(t *testing.T, clientOperation, message string) {
+		var actualLog models.Log
+		helpers.QueryTestLogLatest(t, dbs.Logger, &actualLog)
+		require.Equal(t, clientOperation, actualLog.ClientOperation)
+		require.Equal(t, message, actualLog.Message)
+	}
+
+	t.Run("vault_role_at_least", func(t *testing.T) {
+		require.True(t, utils.VaultRoleAtLeast(utils.VaultRoleManage, utils.VaultRoleWrite))
+		require.True(t, utils.VaultRoleAtLeast(utils.VaultRoleRead, utils.VaultRoleRead))
+		require.False(t, utils.VaultRoleAtLeast(utils.VaultRoleWrite, utils.VaultRoleManage))
+		require.False(t, utils.VaultRoleAtLeast("admin", utils.VaultRoleRead))
+	})
+
+	publicKey := base64.StdEncoding.EncodeToString(make([]byte, utils.PublicKeyLength))
+	privateKey := base64.StdEncoding.EncodeToString([]byte("encrypted private key"))
+	keyPairBody := `{"public_key":"` + publicKey + `","encrypted_private_key":"` + privateKey + `"}`
+
+	t.Run("set_key_pair_invalid_400_bad_request", func(t *testing.T) {
+		resp, respBody := doRequest(
+			t, "PUT", "/api/users/key_pair", utils.SetKeyPair, ownerAuth, helpers.HexHash1,
+			`{"public_key":"AAAA","encrypted_private_key":"not base64!"}`,
+		)
+		require.Equal(t, 400, resp.StatusCode)
+		require.Contains(t, string(respBody), "public_key")
+		require.Contains(t, string(respBody), "encrypted_private_key")
+		assertLogMessage(t, utils.SetKeyPair, utils.ErrorKeyPair)
+	})
+
+	t.Run("set_key_pair_without_password_401_unauthorized", func(t *testing.T) {
+		resp, _ := doRequest(
+			t, "PUT", "/api/users/key_pair", utils.SetKeyPair, ownerAuth, "", keyPairBody,
+		)
+		require.Equal(t, 401, resp.StatusCode)
+	})
+
+	t.Run("set_and_retrieve_key_pair", func(t *testing.T) {
+		resp, _ := doRequest(
+			t, "GET", "/api/users/key_pair", utils.RetrieveKeyPair, ownerAuth, helpers.HexHash1, "",
+		)
+		require.Equal(t, 404, resp.StatusCode)
+		assertLogMessage(t, utils.RetrieveKeyPair, utils.ErrorNoKeyPair)
+
+		resp, _ = doRequest(
+			t, "PUT", "/api/users/key_pair", utils.SetKeyPair, ownerAuth, helpers.HexHash1,
+			keyPairBody,
+		)
+		require.Equal(t, 204, resp.StatusCode)
+
+		resp, respBody := doRequest(
+			t, "GET", "/api/users/key_pair", utils.RetrieveKeyPair, ownerAuth, helpers.HexHash1, "",
+		)
+		require.Equal(t, 200, resp.StatusCode)
+
+		var keyPair controllers.RetrieveKeyPairResponseBody
+		require.NoError(t, json.Unmarshal(respBody, &keyPair))
+		require.Equal(t, make([]byte, utils.PublicKeyLength), keyPair.PublicKey)
+		require.Equal(t, []byte("encrypted private key"), keyPair.EncryptedPrivateKey)
+	})
+
+	// The vaults service isn't reachable in tests, so share the vault directly
+	vaultSlug := helpers.NewSlug(t)
+	now := time.Now().UTC()
+	require.NoError(t, dbs.ApiGateway.Omit("Owner").Create(&models.SharedVault{
+		VaultSlug: vaultSlug, OwnerSlug: owner.Slug, CreatedAt: now,
+	}).Error)
+	require.NoError(t, dbs.ApiGateway.Omit("SharedVault", "User").Create(&models.VaultMember{
+		VaultSlug: vaultSlug, UserSlug: owner.Slug, Role: utils.VaultRoleManage, CreatedAt: now,
+	}).Error)
+
+	vaultTarget := "/api/vaults/" + vaultSlug
+	secretSlug := helpers.NewSlug(t)
+
+	updateSecret := func(t *testing.T, app *fiber.App) *http.Response {
+		req := httptest.NewRequest("PATCH", "/api/secrets/" + secretSlug, strings.NewReader("{}"))
+		req.Header.Set("Authorization", inviteeAuth)
+		req.Header.Set("Client-Operation", utils.UpdateSecret)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("If-Match", `"1"`)
+		req.Header.Set("X-Forwarded-For", helpers.CLIENT_IP)
+		req.Header.Set(conf.PASSWORD_HEADER_KEY, helpers.HexHash2)
+		req.Header.Set("Vault-Slug", vaultSlug)
+
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+
+		return resp
+	}
+
+	t.Run("invite_invalid_400_bad_request", func(t *testing.T) {
+		resp, respBody := doRequest(
+			t, "POST", vaultTarget + "/invitations", utils.InviteVaultMember, ownerAuth,
+			helpers.HexHash1,
+			`{"email_address":"` + helpers.VALID_EMAIL_1 + `","role":"admin"}`,
+		)
+		require.Equal(t, 400, resp.StatusCode)
+		require.Contains(t, string(respBody), "You can't invite yourself")
+		require.Contains(t, string(respBody), "Must be one of: read, write, manage")
+		assertLogMessage(t, utils.InviteVaultMember, utils.ErrorInvitation)
+	})
+
+	var invitation controllers.VaultInvitationResponse
+
+	t.Run("invite_201_created", func(t *testing.T) {
+		for _, role := range []string{utils.VaultRoleWrite, utils.VaultRoleRead} {
+			resp, respBody := doRequest(
+				t, "POST", vaultTarget + "/invitations", utils.InviteVaultMember, ownerAuth,
+			helpers.HexHash1,
+				`{"email_address":"` + helpers.VALID_EMAIL_2 + `","role":"` + role + `"}`,
+			)
+			require.Equal(t, 201, resp.StatusCode)
+			require.NoError(t, json.Unmarshal(respBody, &invitation))
+			require.Equal(t, role, invitation.Role)
+			require.Equal(t, helpers.VALID_NAME_1, invitation.InviterName)
+		}
+
+		// Inviting the same address again replaces the pending invitation
+		var count int64
+		dbs.ApiGateway.Model(&models.VaultInvitation{}).Count(&count)
+		require.Equal(t, int64(1), count)
+	})
+
+	t.Run("list_invitations_200_ok", func(t *testing.T) {
+		resp, respBody := doRequest(
+			t, "GET", "/api/invitations", utils.ListInvitations, inviteeAuth, "", "",
+		)
+		require.Equal(t, 200, resp.StatusCode)
+
+		var listed controllers.ListInvitationsResponseBody
+		require.NoError(t, json.Unmarshal(respBody, &listed))
+		require.Len(t, listed.Invitations, 1)
+		require.Equal(t, invitation.InvitationSlug, listed.Invitations[0].InvitationSlug)
+
+		resp, respBody = doRequest(
+			t, "GET", "/api/invitations", utils.ListInvitations, ownerAuth, "", "",
+		)
+		require.Equal(t, 200, resp.StatusCode)
+		require.NoError(t, json.Unmarshal(respBody, &listed))
+		require.Empty(t, listed.Invitations)
+	})
+
+	invitationTarget := "/api/invitations/" + invitation.InvitationSlug
+
+	t.Run("accept_other_users_invitation_404_not_found", func(t *testing.T) {
+		resp, _ := doRequest(
+			t, "POST", invitationTarget + "/accept", utils.AcceptInvitation, ownerAuth, "", "",
+		)
+		require.Equal(t, 404, resp.StatusCode)
+		assertLogMessage(t, utils.AcceptInvitation, utils.ErrorInvitationNotFound)
+	})
+
+	t.Run("accept_without_key_pair_409_conflict", func(t *testing.T) {
+		resp, _ := doRequest(
+			t, "POST", invitationTarget + "/accept", utils.AcceptInvitation, inviteeAuth, "", "",
+		)
+		require.Equal(t, 409, resp.StatusCode)
+		assertLogMessage(t, utils.AcceptInvitation, utils.ErrorNoKeyPair)
+	})
+
+	t.Run("accept_201_created", func(t *testing.T) {
+		resp, _ := doRequest(
+			t, "PUT", "/api/users/key_pair", utils.SetKeyPair, inviteeAuth, helpers.HexHash2,
+			keyPairBody,
+		)
+		require.Equal(t, 204, resp.StatusCode)
+
+		resp, _ = doRequest(
+			t, "POST", invitationTarget + "/accept", utils.AcceptInvitation, inviteeAuth, "", "",
+		)
+		require.Equal(t, 201, resp.StatusCode)
+
+		resp, respBody := doRequest(
+			t, "GET", "/api/shared_vaults", utils.ListSharedVaults, inviteeAuth, "", "",
+		)
+		require.Equal(t, 200, resp.StatusCode)
+
+		var listed controllers.ListSharedVaultsResponseBody
+		require.NoError(t, json.Unmarshal(respBody, &listed))
+		require.Len(t, listed.SharedVaults, 1)
+		require.Equal(t, vaultSlug, listed.SharedVaults[0].VaultSlug)
+		require.Equal(t, utils.VaultRoleRead, listed.SharedVaults[0].Role)
+		require.Equal(t, helpers.VALID_NAME_1, listed.SharedVaults[0].OwnerName)
+		require.False(t, listed.SharedVaults[0].HasKey)
+	})
+
+	t.Run("invite_existing_member_409_conflict", func(t *testing.T) {
+		resp, _ := doRequest(
+			t, "POST", vaultTarget + "/invitations", utils.InviteVaultMember, ownerAuth,
+			helpers.HexHash1,
+			`{"email_address":"` + helpers.VALID_EMAIL_2 + `","role":"read"}`,
+		)
+		require.Equal(t, 409, resp.StatusCode)
+	})
+
+	t.Run("read_member_insufficient_role_403_forbidden", func(t *testing.T) {
+		resp, _ := doRequest(
+			t, "PATCH", vaultTarget + "/members/" + owner.Slug, utils.UpdateVaultMember,
+			inviteeAuth, helpers.HexHash2, `{"role":"read"}`,
+		)
+		require.Equal(t, 403, resp.StatusCode)
+		assertLogMessage(t, utils.UpdateVaultMember, utils.ErrorVaultRole)
+
+		// Checked before the request reaches the vaults service
+		resp, _ = doRequest(
+			t, "PATCH", vaultTarget, utils.UpdateVault, inviteeAuth, helpers.HexHash2,
+			`{"vault_title":"Renamed"}`,
+		)
+		require.Equal(t, 403, resp.StatusCode)
+		assertLogMessage(t, utils.UpdateVault, utils.ErrorVaultRole)
+
+		resp, _ = doRequest(
+			t, "DELETE", vaultTarget, utils.DeleteVault, inviteeAuth, helpers.HexHash2, "",
+		)
+		require.Equal(t, 403, resp.StatusCode)
+
+		resp = updateSecret(t, app)
+		require.Equal(t, 403, resp.StatusCode)
+		assertLogMessage(t, utils.UpdateSecret, utils.ErrorVaultRole)
+	})
+
+	t.Run("entry_in_unshared_vault_404_not_found", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/entries/" + helpers.NewSlug(t), nil)
+		req.Header.Set("Authorization", inviteeAuth)
+		req.Header.Set("Client-Operation", utils.RetrieveEntry)
+		req.Header.Set("X-Forwarded-For", helpers.CLIENT_IP)
+		req.Header.Set(conf.PASSWORD_HEADER_KEY, helpers.HexHash2)
+		req.Header.Set("Vault-Slug", helpers.NewSlug(t))
+
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+		require.Equal(t, 404, resp.StatusCode)
+		assertLogMessage(t, utils.RetrieveEntry, utils.ErrorVaultNotShared)
+	})
+
+	t.Run("list_members_200_ok", func(t *testing.T) {
+		for _, authHeader := range []string{ownerAuth, inviteeAuth} {
+			resp, respBody := doRequest(
+				t, "GET", vaultTarget + "/members", utils.ListVaultMembers, authHeader, "", "",
+			)
+			require.Equal(t, 200, resp.StatusCode)
+
+			var listed controllers.ListVaultMembersResponseBody
+			require.NoError(t, json.Unmarshal(respBody, &listed))
+			require.Len(t, listed.Members, 2)
+			require.True(t, listed.Members[0].IsOwner)
+			require.Equal(t, make([]byte, utils.PublicKeyLength), listed.Members[1].PublicKey)
+		}
+	})
+
+	wrappedKey := base64.StdEncoding.EncodeToString([]byte("vault key sealed to member"))
+
+	t.Run("set_and_retrieve_vault_key", func(t *testing.T) {
+		resp, respBody := doRequest(
+			t, "PUT", vaultTarget + "/keys", utils.SetVaultKeys, ownerAuth, helpers.HexHash1,
+			`{"keys":{"` + helpers.NewSlug(t) + `":"` + wrappedKey + `"}}`,
+		)
+		require.Equal(t, 400, resp.StatusCode)
+		require.Contains(t, string(respBody), "Not a member with a key pair")
+
+		resp, _ = doRequest(
+			t, "PUT", vaultTarget + "/keys", utils.SetVaultKeys, ownerAuth, helpers.HexHash1,
+			`{"keys":{"` + invitee.Slug + `":"` + wrappedKey + `"}}`,
+		)
+		require.Equal(t, 204, resp.StatusCode)
+
+		resp, respBody = doRequest(
+			t, "GET", vaultTarget + "/key", utils.RetrieveVaultKey, inviteeAuth, "", "",
+		)
+		require.Equal(t, 200, resp.StatusCode)
+
+		var key controllers.RetrieveVaultKeyResponseBody
+		require.NoError(t, json.Unmarshal(respBody, &key))
+		require.Equal(t, []byte("vault key sealed to member"), key.WrappedKey)
+		require.False(t, key.RekeyRequired)
+	})
+
+	t.Run("update_member_without_password_401_unauthorized", func(t *testing.T) {
+		resp, _ := doRequest(
+			t, "PATCH", vaultTarget + "/members/" + invitee.Slug, utils.UpdateVaultMember,
+			ownerAuth, "", `{"role":"manage"}`,
+		)
+		require.Equal(t, 401, resp.StatusCode)
+
+		var member models.VaultMember
+		dbs.ApiGateway.Where("user_slug = ?", invitee.Slug).First(&member)
+		require.Equal(t, utils.VaultRoleRead, member.Role)
+	})
+
+	t.Run("update_member_role", func(t *testing.T) {
+		resp, _ := doRequest(
+			t, "PATCH", vaultTarget + "/members/" + owner.Slug, utils.UpdateVaultMember, ownerAuth,
+			helpers.HexHash1, `{"role":"read"}`,
+		)
+		require.Equal(t, 400, resp.StatusCode)
+		assertLogMessage(t, utils.UpdateVaultMember, utils.ErrorVaultMember)
+
+		resp, _ = doRequest(
+			t, "PATCH", vaultTarget + "/members/" + invitee.Slug, utils.UpdateVaultMember,
+			ownerAuth, helpers.HexHash1, `{"role":"write"}`,
+		)
+		require.Equal(t, 204, resp.StatusCode)
+
+		var member models.VaultMember
+		dbs.ApiGateway.Where("user_slug = ?", invitee.Slug).First(&member)
+		require.Equal(t, utils.VaultRoleWrite, member.Role)
+	})
+
+	t.Run("write_member_update_secret_204_no_content", func(t *testing.T) {
+		stubApp := newVaultsStubApp(t, dbs, conf, conf.VAULTS_MAX_RESPONSE_BYTES,
+			func(w http.ResponseWriter, r *http.Request) {
+				require.Equal(t, "PATCH", r.Method)
+				require.Equal(t, "/api/secrets/" + secretSlug, r.URL.Path)
+				require.Equal(t, vaultSlug, r.Header.Get("Vault-Slug"))
+				w.WriteHeader(204)
+			},
+		)
+
+		resp := updateSecret(t, stubApp)
+		require.Equal(t, 204, resp.StatusCode)
+	})
+
+	t.Run("remove_member_requires_rekey", func(t *testing.T) {
+		resp, _ := doRequest(
+			t, "DELETE", vaultTarget + "/members/" + owner.Slug, utils.RemoveVaultMember,
+			inviteeAuth, helpers.HexHash2, "",
+		)
+		require.Equal(t, 403, resp.StatusCode)
+
+		// Members can always leave
+		resp, _ = doRequest(
+			t, "DELETE", vaultTarget + "/members/" + invitee.Slug, utils.RemoveVaultMember,
+			inviteeAuth, helpers.HexHash2, "",
+		)
+		require.Equal(t, 204, resp.StatusCode)
+
+		resp, _ = doRequest(
+			t, "GET", vaultTarget + "/key", utils.RetrieveVaultKey, inviteeAuth, "", "",
+		)
+		require.Equal(t, 404, resp.StatusCode)
+		assertLogMessage(t, utils.RetrieveVaultKey, utils.ErrorVaultNotShared)
+
+		ownerKey := `{"keys":{"` + owner.Slug + `":"` + wrappedKey + `"}`
+
+		resp, _ = doRequest(
+			t, "PUT", vaultTarget + "/keys", utils.SetVaultKeys, ownerAuth, helpers.HexHash1,
+			ownerKey + `}`,
+		)
+		require.Equal(t, 409, resp.StatusCode)
+
+		resp, _ = doRequest(
+			t, "PUT", vaultTarget + "/keys", utils.SetVaultKeys, ownerAuth, helpers.HexHash1,
+			ownerKey + `,"rotated":true}`,
+		)
+		require.Equal(t, 204, resp.StatusCode)
+
+		var sharedVault models.SharedVault
+		dbs.ApiGateway.Where("vault_slug = ?", vaultSlug).First(&sharedVault)
+		require.False(t, sharedVault.RekeyRequired)
+	})
+
+	t.Run("new_key_pair_clears_wrapped_keys", func(t *testing.T) {
+		resp, _ := doRequest(
+			t, "PUT", "/api/users/key_pair", utils.SetKeyPair, ownerAuth, helpers.HexHash1,
+			keyPairBody,
+		)
+		require.Equal(t, 204, resp.StatusCode)
+
+		resp, _ = doRequest(
+			t, "GET", vaultTarget + "/key", utils.RetrieveVaultKey, ownerAuth, "", "",
+		)
+		require.Equal(t, 404, resp.StatusCode)
+		assertLogMessage(t, utils.RetrieveVaultKey, utils.ErrorVaultKeys)
+	})
+}
@@ -0,0 +1,345 @@
+package upstreams
+
+import (
+	"bufio"
+	"errors"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/liobrdev/simplepasswords_api_gateway/config"
+)
+
+const (
+	RoundRobin       string = "round_robin"
+	LeastConnections string = "least_connections"
+
+	healthCheckInterval = time.Duration(5) * time.Second
+	healthCheckTimeout  = time.Duration(2) * time.Second
+	ejectDuration       = time.Duration(30) * time.Second
+)
+
+var ErrNoHealthyUpstream = errors.New("no healthy vaults upstream")
+
+type Upstream struct {
+	URL          string
+	active       int64
+	healthy      bool
+	ejectedUntil time.Time
+}
+
+type UpstreamStatus struct {
+	URL         string `json:"url"`
+	Healthy     bool   `json:"healthy"`
+	Connections int64  `json:"connections"`
+}
+
+// The health of each upstream, as published by the process running the checks
+type sharedStatus struct {
+	URL     string `json:"url"`
+	Healthy bool   `json:"healthy"`
+}
+
+// Pool balances requests across the configured vaults replicas. Nodes that fail an active
+// health check are ejected for ejectDuration and only readmitted once a check passes again.
+type Pool struct {
+	mu         sync.RWMutex
+	upstreams  []*Upstream
+	next       uint64
+	balancer   string
+	reloadPath string
+	statusPath string
+}
+
+func Init(conf *config.AppConfig) *Pool {
+	pool := &Pool{balancer: conf.VAULTS_BALANCER, reloadPath: conf.VAULTS_UPSTREAMS_FILE}
+
+	// With prefork, the parent process runs the health checks and its children read the
+	// results from a file named after it, so every process agrees on which nodes are healthy
+	if fiber.IsChild() {
+		pool.statusPath = statusPath(os.Getppid())
+	} else {
+		pool.statusPath = statusPath(os.Getpid())
+	}
+
+	if pool.balancer != RoundRobin && pool.balancer != LeastConnections {
+		log.Fatalf("Invalid VAULTS_BALANCER '%s'", pool.balancer)
+	}
+
+	pool.Reload(conf.VAULTS_UPSTREAMS)
+
+	return pool
+}
+
+// Reload replaces the upstream list, keeping health state for addresses that are still listed.
+// New addresses count as unhealthy until their first health check passes.
+func (P *Pool) Reload(addresses []string) {
+	P.mu.Lock()
+	defer P.mu.Unlock()
+
+	existing := make(map[string]*Upstream, len(P.upstreams))
+
+	for _, u := range P.upstreams {
+		existing[u.URL] = u
+	}
+
+	upstreams := make([]*Upstream, 0, len(addresses))
+
+	for _, address := range addresses {
+		if address = strings.TrimSpace(address); address == "" {
+			continue
+		}
+
+		url := "http://" + address
+
+		if u, ok := existing[url]; ok {
+			upstreams = append(upstreams, u)
+		} else {
+			upstreams = append(upstreams, &Upstream{URL: url})
+		}
+	}
+
+	P.upstreams = upstreams
+}
+
+func (P *Pool) Acquire() (*Upstream, error) {
+	P.mu.RLock()
+	defer P.mu.RUnlock()
+
+	var healthy []*Upstream
+
+	for _, u := range P.upstreams {
+		if u.healthy {
+			healthy = append(healthy, u)
+		}
+	}
+
+	if len(healthy) == 0 {
+		return nil, ErrNoHealthyUpstream
+	}
+
+	var chosen *Upstream
+
+	if P.balancer == LeastConnections {
+		for _, u := range healthy {
+			if chosen == nil || atomic.LoadInt64(&u.active) < atomic.LoadInt64(&chosen.active) {
+				chosen = u
+			}
+		}
+	} else {
+		chosen = healthy[(atomic.AddUint64(&P.next, 1) - 1) % uint64(len(healthy))]
+	}
+
+	atomic.AddInt64(&chosen.active, 1)
+
+	return chosen, nil
+}
+
+func (U *Upstream) Release() {
+	atomic.AddInt64(&U.active, -1)
+}
+
+func (P *Pool) Ready() bool {
+	P.mu.RLock()
+	defer P.mu.RUnlock()
+
+	for _, u := range P.upstreams {
+		if u.healthy {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (P *Pool) Status() []UpstreamStatus {
+	P.mu.RLock()
+	defer P.mu.RUnlock()
+
+	status := make([]UpstreamStatus, len(P.upstreams))
+
+	for i, u := range P.upstreams {
+		status[i] = UpstreamStatus{
+			URL:         u.URL,
+			Healthy:     u.healthy,
+			Connections: atomic.LoadInt64(&u.active),
+		}
+	}
+
+	return status
+}
+
+// RunHealthChecks blocks, probing every upstream's readiness endpoint and re-reading the
+// upstreams file on each tick so that the list can be changed without a restart. It runs in a
+// single process, which publishes the results for RunStatusSync in the others.
+func (P *Pool) RunHealthChecks() {
+	for {
+		P.reloadFromFile()
+		P.checkAll()
+
+		if err := P.publishStatus(); err != nil {
+			log.Printf("Error publishing vaults upstreams status '%s': %s", P.statusPath, err)
+		}
+
+		time.Sleep(healthCheckInterval)
+	}
+}
+
+// RunStatusSync blocks, taking the upstream list and health published by RunHealthChecks on
+// each tick, so that prefork children neither probe nor eject nodes on their own
+func (P *Pool) RunStatusSync() {
+	for {
+		if err := P.syncStatus(); err != nil && !errors.Is(err, os.ErrNotExist) {
+			log.Printf("Error reading vaults upstreams status '%s': %s", P.statusPath, err)
+		}
+
+		time.Sleep(healthCheckInterval)
+	}
+}
+
+func statusPath(pid int) string {
+	return filepath.Join(
+		os.TempDir(), "simplepasswords_vaults_upstreams_" + strconv.Itoa(pid) + ".json",
+	)
+}
+
+func (P *Pool) publishStatus() error {
+	P.mu.RLock()
+	status := make([]sharedStatus, len(P.upstreams))
+
+	for i, u := range P.upstreams {
+		status[i] = sharedStatus{URL: u.URL, Healthy: u.healthy}
+	}
+
+	P.mu.RUnlock()
+
+	contents, err := json.Marshal(status)
+
+	if err != nil {
+		return err
+	}
+
+	// Rename over the old file so that readers never see it half written
+	tmpPath := P.statusPath + ".tmp"
+
+	if err = os.WriteFile(tmpPath, contents, 0600); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpPath, P.statusPath)
+}
+
+func (P *Pool) syncStatus() error {
+	contents, err := os.ReadFile(P.statusPath)
+
+	if err != nil {
+		return err
+	}
+
+	status := []sharedStatus{}
+
+	if err = json.Unmarshal(contents, &status); err != nil {
+		return err
+	}
+
+	P.mu.Lock()
+	defer P.mu.Unlock()
+
+	existing := make(map[string]*Upstream, len(P.upstreams))
+
+	for _, u := range P.upstreams {
+		existing[u.URL] = u
+	}
+
+	upstreams := make([]*Upstream, 0, len(status))
+
+	for _, published := range status {
+		u, ok := existing[published.URL]
+
+		if !ok {
+			u = &Upstream{URL: published.URL}
+		}
+
+		u.healthy = published.Healthy
+		upstreams = append(upstreams, u)
+	}
+
+	P.upstreams = upstreams
+
+	return nil
+}
+
+func (P *Pool) reloadFromFile() {
+	if P.reloadPath == "" {
+		return
+	}
+
+	file, err := os.Open(P.reloadPath)
+
+	if err != nil {
+		log.Printf("Error opening vaults upstreams file '%s': %s", P.reloadPath, err)
+
+		return
+	}
+
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Scan()
+
+	if contents := scanner.Text(); scanner.Err() != nil {
+		log.Printf("Error reading vaults upstreams file '%s': %s", P.reloadPath, scanner.Err())
+	} else if contents == "" {
+		log.Printf("Empty vaults upstreams file '%s', keeping current list", P.reloadPath)
+	} else {
+		P.Reload(strings.Split(contents, ","))
+	}
+}
+
+func (P *Pool) checkAll() {
+	P.mu.RLock()
+	upstreams := make([]*Upstream, len(P.upstreams))
+	copy(upstreams, P.upstreams)
+	P.mu.RUnlock()
+
+	now := time.Now()
+
+	for _, u := range upstreams {
+		P.mu.RLock()
+		ejected := u.ejectedUntil.After(now)
+		P.mu.RUnlock()
+
+		if ejected {
+			continue
+		}
+
+		healthy := checkUpstream(u)
+
+		P.mu.Lock()
+
+		if u.healthy = healthy; !healthy {
+			u.ejectedUntil = now.Add(ejectDuration)
+		}
+
+		P.mu.Unlock()
+
+		if !healthy {
+			log.Printf("Ejected unhealthy vaults upstream %s", u.URL)
+		}
+	}
+}
+
+func checkUpstream(u *Upstream) bool {
+	agent := fiber.Get(u.URL + "/readyz").Timeout(healthCheckTimeout)
+	statusCode, _, errs := agent.String()
+
+	return len(errs) == 0 && statusCode == 200
+}
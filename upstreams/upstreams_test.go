@@ -0,0 +1,160 @@
+package upstreams
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newReadyServer serves /readyz with 200 while ready is true, and 503 otherwise
+func newReadyServer(t *testing.T, ready *atomic.Bool) (*httptest.Server, string) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/readyz" && ready.Load() {
+			w.WriteHeader(200)
+		} else {
+			w.WriteHeader(503)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return server, strings.TrimPrefix(server.URL, "http://")
+}
+
+func newTestPool(balancer string, addresses ...string) *Pool {
+	pool := &Pool{balancer: balancer}
+	pool.Reload(addresses)
+
+	return pool
+}
+
+func acquireURL(t *testing.T, pool *Pool) string {
+	u, err := pool.Acquire()
+	require.NoError(t, err)
+	u.Release()
+
+	return u.URL
+}
+
+func TestPool(t *testing.T) {
+	var readyA, readyB atomic.Bool
+	readyA.Store(true)
+	readyB.Store(true)
+
+	_, addressA := newReadyServer(t, &readyA)
+	_, addressB := newReadyServer(t, &readyB)
+	urlA, urlB := "http://" + addressA, "http://" + addressB
+
+	t.Run("new_upstreams_unhealthy_until_checked", func(t *testing.T) {
+		pool := newTestPool(RoundRobin, addressA, addressB)
+		require.False(t, pool.Ready())
+
+		_, err := pool.Acquire()
+		require.ErrorIs(t, err, ErrNoHealthyUpstream)
+
+		pool.checkAll()
+		require.True(t, pool.Ready())
+	})
+
+	t.Run("round_robin", func(t *testing.T) {
+		pool := newTestPool(RoundRobin, addressA, addressB)
+		pool.checkAll()
+
+		urls := []string{}
+
+		for i := 0; i < 4; i++ {
+			urls = append(urls, acquireURL(t, pool))
+		}
+
+		require.Equal(t, []string{urlA, urlB, urlA, urlB}, urls)
+	})
+
+	t.Run("least_connections", func(t *testing.T) {
+		pool := newTestPool(LeastConnections, addressA, addressB)
+		pool.checkAll()
+
+		first, err := pool.Acquire()
+		require.NoError(t, err)
+		require.Equal(t, urlA, first.URL)
+
+		// A is busy, so B gets the next requests until A is released
+		require.Equal(t, urlB, acquireURL(t, pool))
+		require.Equal(t, urlB, acquireURL(t, pool))
+
+		first.Release()
+		require.Equal(t, urlA, acquireURL(t, pool))
+	})
+
+	t.Run("ejection_and_recovery", func(t *testing.T) {
+		pool := newTestPool(RoundRobin, addressA, addressB)
+		readyB.Store(false)
+		defer readyB.Store(true)
+
+		pool.checkAll()
+
+		for i := 0; i < 3; i++ {
+			require.Equal(t, urlA, acquireURL(t, pool))
+		}
+
+		// Still ejected, so not probed again even though it has recovered
+		readyB.Store(true)
+		pool.checkAll()
+		require.Equal(t, urlA, acquireURL(t, pool))
+		require.Equal(t, urlA, acquireURL(t, pool))
+
+		pool.mu.Lock()
+		pool.upstreams[1].ejectedUntil = time.Now().Add(-time.Second)
+		pool.mu.Unlock()
+
+		pool.checkAll()
+
+		urls := map[string]bool{}
+
+		for i := 0; i < 2; i++ {
+			urls[acquireURL(t, pool)] = true
+		}
+
+		require.Equal(t, map[string]bool{urlA: true, urlB: true}, urls)
+	})
+
+	t.Run("reload_keeps_health_state", func(t *testing.T) {
+		pool := newTestPool(RoundRobin, addressA)
+		pool.checkAll()
+
+		pool.Reload([]string{addressA, " ", addressB})
+		status := pool.Status()
+		require.Len(t, status, 2)
+		require.True(t, status[0].Healthy)
+		require.False(t, status[1].Healthy)
+	})
+
+	t.Run("status_shared_between_processes", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "status.json")
+
+		parent := newTestPool(RoundRobin, addressA, addressB)
+		parent.statusPath = path
+		readyB.Store(false)
+		parent.checkAll()
+		readyB.Store(true)
+		require.NoError(t, parent.publishStatus())
+
+		child := &Pool{balancer: RoundRobin, statusPath: path}
+		require.NoError(t, child.syncStatus())
+		require.Equal(t, []UpstreamStatus{
+			{URL: urlA, Healthy: true},
+			{URL: urlB, Healthy: false},
+		}, child.Status())
+
+		// The child keeps its own connection counts across syncs
+		u, err := child.Acquire()
+		require.NoError(t, err)
+		require.NoError(t, child.syncStatus())
+		require.Equal(t, int64(1), child.Status()[0].Connections)
+		u.Release()
+	})
+}
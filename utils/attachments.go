@@ -0,0 +1,57 @@
+package utils
+
+import (
+	"net/http"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	AttachmentMaxSize       int64 = 10 << 20
+	AttachmentsQuota        int64 = 100 << 20
+	AttachmentNameMaxLength int   = 255
+	AttachmentSniffLength   int   = 512
+)
+
+// Types a browser would render or run if an attachment were ever opened inline
+var blockedAttachmentTypes = []string{"text/html", "text/xml", "application/xml"}
+
+// SniffAttachmentType returns the content type of an attachment from its first bytes, never
+// from what the client claimed. Returns false for types that browsers can script.
+func SniffAttachmentType(head []byte) (string, bool) {
+	contentType := http.DetectContentType(head)
+	mediaType, _, _ := strings.Cut(contentType, ";")
+
+	for _, blocked := range blockedAttachmentTypes {
+		if mediaType == blocked {
+			return contentType, false
+		}
+	}
+
+	return contentType, true
+}
+
+// SanitizeAttachmentName drops any directories and control characters from a client's file
+// name, and shortens it to AttachmentNameMaxLength runes, keeping the extension
+func SanitizeAttachmentName(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.TrimSpace(ControlCharRegexp.ReplaceAllString(name, ""))
+
+	if name == "." || name == "/" || name == "" {
+		return "attachment"
+	}
+
+	if utf8.RuneCountInString(name) > AttachmentNameMaxLength {
+		ext := filepath.Ext(name)
+
+		if utf8.RuneCountInString(ext) > 16 {
+			ext = ""
+		}
+
+		runes := []rune(strings.TrimSuffix(name, ext))
+		name = string(runes[:AttachmentNameMaxLength - utf8.RuneCountInString(ext)]) + ext
+	}
+
+	return name
+}
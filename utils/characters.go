@@ -4,6 +4,7 @@ const UPPERCASE_LETTERS string = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
 const LOWERCASE_LETTERS string = "abcdefghijklmnopqrstuvwxyz"
 const DIGITS string = "0123456789"
 const SPECIAL_CHARS string = "`~!@#$%^&*()-=_+,./<>?;':\"[]\\{}|"
+const AMBIGUOUS_CHARS string = "Il1|O0o`'\".,;:"
 
 const OTP_ALPHABET = UPPERCASE_LETTERS + LOWERCASE_LETTERS + DIGITS
 const SLUG_ALPHABET = OTP_ALPHABET + "_-"
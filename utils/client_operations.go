@@ -24,7 +24,73 @@ const (
 	UpdateEntry   string = "update_entry"
 	UpdateSecret  string = "update_secret"
 	MoveSecret		string = "move_secret"
+	MoveEntry			string = "move_entry"
 	DeleteVault   string = "delete_vault"
 	DeleteEntry   string = "delete_entry"
 	DeleteSecret  string = "delete_secret"
+	SearchEntries string = "search_entries"
+	ImportEntries string = "import_entries"
+	ExportVaults  string = "export_vaults"
+	VaultHealthReport string = "vault_health_report"
+	GetEntryTOTP  string = "get_entry_totp"
+	ListSecretVersions   string = "list_secret_versions"
+	RestoreSecretVersion string = "restore_secret_version"
+	ListTrash     string = "list_trash"
+	RestoreItem   string = "restore_item"
+	EmptyTrash    string = "empty_trash"
+	AddTag        string = "add_tag"
+	RemoveTag     string = "remove_tag"
+	ListTags      string = "list_tags"
+	SetFavorite   string = "set_favorite"
+	MatchURL      string = "match_url"
+	CreateShare   string = "create_share"
+	OpenShare     string = "open_share"
+	ListShares    string = "list_shares"
+	RevokeShare   string = "revoke_share"
+	UploadAttachment   string = "upload_attachment"
+	ListAttachments    string = "list_attachments"
+	DownloadAttachment string = "download_attachment"
+	DeleteAttachment   string = "delete_attachment"
+	SetKeyPair        string = "set_key_pair"
+	RetrieveKeyPair   string = "retrieve_key_pair"
+	InviteVaultMember string = "invite_vault_member"
+	RevokeInvitation  string = "revoke_invitation"
+	ListInvitations   string = "list_invitations"
+	AcceptInvitation  string = "accept_invitation"
+	DeclineInvitation string = "decline_invitation"
+	ListSharedVaults  string = "list_shared_vaults"
+	ListVaultMembers  string = "list_vault_members"
+	UpdateVaultMember string = "update_vault_member"
+	RemoveVaultMember string = "remove_vault_member"
+	RetrieveVaultKey  string = "retrieve_vault_key"
+	SetVaultKeys      string = "set_vault_keys"
+	CreateOrg            string = "create_org"
+	ListOrgs             string = "list_orgs"
+	InviteOrgMember      string = "invite_org_member"
+	RevokeOrgInvitation  string = "revoke_org_invitation"
+	AcceptOrgInvitation  string = "accept_org_invitation"
+	DeclineOrgInvitation string = "decline_org_invitation"
+	ListOrgMembers       string = "list_org_members"
+	UpdateOrgMember      string = "update_org_member"
+	RemoveOrgMember      string = "remove_org_member"
+	CreateOrgGroup       string = "create_org_group"
+	DeleteOrgGroup       string = "delete_org_group"
+	AddOrgGroupMember    string = "add_org_group_member"
+	RemoveOrgGroupMember string = "remove_org_group_member"
+	SetOrgVaultGrant     string = "set_org_vault_grant"
+	RemoveOrgVaultGrant  string = "remove_org_vault_grant"
+	ListOrgVaults        string = "list_org_vaults"
+	AddEmergencyContact    string = "add_emergency_contact"
+	ListEmergencyContacts  string = "list_emergency_contacts"
+	UpdateEmergencyContact string = "update_emergency_contact"
+	RemoveEmergencyContact string = "remove_emergency_contact"
+	RequestEmergencyAccess string = "request_emergency_access"
+	RejectEmergencyAccess  string = "reject_emergency_access"
+	PurgeTrash    string = "purge_trash"
+	EmergencyAccessNotices string = "emergency_access_notices"
+	Batch         string = "batch"
+
+	// generators
+	GeneratePasswordOp string = "generate_password"
+	CheckBreached      string = "check_breached"
 )
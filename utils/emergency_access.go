@@ -0,0 +1,30 @@
+package utils
+
+import (
+	"strconv"
+	"time"
+)
+
+const (
+	EmergencyContactSlugLength int = 16
+	EmergencyWaitingDaysMin    int = 1
+	EmergencyWaitingDaysMax    int = 90
+)
+
+// EmergencyAccessOpensAt is when a contact's request for emergency access is granted, unless
+// the grantor rejects it first
+func EmergencyAccessOpensAt(requestedAt time.Time, waitingDays int) time.Time {
+	return requestedAt.Add(time.Duration(waitingDays) * 24 * time.Hour)
+}
+
+// ValidateWaitingDays checks an emergency contact's waiting period, returning its field errors
+func ValidateWaitingDays(waitingDays int) []string {
+	if waitingDays < EmergencyWaitingDaysMin || waitingDays > EmergencyWaitingDaysMax {
+		return []string{
+			"Must be from " + strconv.Itoa(EmergencyWaitingDaysMin) + " to " +
+			strconv.Itoa(EmergencyWaitingDaysMax) + " days",
+		}
+	}
+
+	return nil
+}
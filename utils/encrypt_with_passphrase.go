@@ -0,0 +1,67 @@
+package utils
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+const encryptedArchiveVersion int = 1
+const encryptedArchiveAAD string = "simplepasswords-export-v1"
+
+type ArchiveKDF struct {
+	Name    string `json:"name"`
+	Salt    string `json:"salt"`
+	Time    uint32 `json:"time"`
+	Memory  uint32 `json:"memory"`
+	Threads uint8  `json:"threads"`
+}
+
+// EncryptedArchive is self-describing so that any client can decrypt it with the passphrase
+// alone: derive the key with the Argon2id parameters in KDF, then open Ciphertext with
+// XChaCha20-Poly1305 using Nonce and the archive AAD.
+type EncryptedArchive struct {
+	Version    int        `json:"version"`
+	KDF        ArchiveKDF `json:"kdf"`
+	Cipher     string     `json:"cipher"`
+	AAD        string     `json:"aad"`
+	Nonce      string     `json:"nonce"`
+	Ciphertext string     `json:"ciphertext"`
+}
+
+func EncryptWithPassphrase(plaintext, passphrase []byte) (*EncryptedArchive, error) {
+	kdf := ArchiveKDF{Name: "argon2id", Time: 3, Memory: 64 * 1024, Threads: 4}
+	salt := make([]byte, 16)
+
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+
+	key := argon2.IDKey(passphrase, salt, kdf.Time, kdf.Memory, kdf.Threads, chacha20poly1305.KeySize)
+	aead, err := chacha20poly1305.NewX(key)
+
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, chacha20poly1305.NonceSizeX)
+
+	if _, err = rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	kdf.Salt = base64.StdEncoding.EncodeToString(salt)
+
+	return &EncryptedArchive{
+		Version:    encryptedArchiveVersion,
+		KDF:        kdf,
+		Cipher:     "xchacha20poly1305",
+		AAD:        encryptedArchiveAAD,
+		Nonce:      base64.StdEncoding.EncodeToString(nonce),
+		Ciphertext: base64.StdEncoding.EncodeToString(
+			aead.Seal(nil, nonce, plaintext, []byte(encryptedArchiveAAD)),
+		),
+	}, nil
+}
@@ -0,0 +1,43 @@
+package utils
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+func TestEncryptWithPassphrase(t *testing.T) {
+	passphrase := []byte("correct horse battery staple")
+	archive, err := EncryptWithPassphrase([]byte(`{"vaults":[]}`), passphrase)
+	require.NoError(t, err)
+	require.Equal(t, "argon2id", archive.KDF.Name)
+	require.Equal(t, "xchacha20poly1305", archive.Cipher)
+
+	salt, _ := base64.StdEncoding.DecodeString(archive.KDF.Salt)
+	nonce, _ := base64.StdEncoding.DecodeString(archive.Nonce)
+	ciphertext, _ := base64.StdEncoding.DecodeString(archive.Ciphertext)
+
+	key := argon2.IDKey(
+		passphrase, salt, archive.KDF.Time, archive.KDF.Memory, archive.KDF.Threads,
+		chacha20poly1305.KeySize,
+	)
+
+	aead, err := chacha20poly1305.NewX(key)
+	require.NoError(t, err)
+
+	plaintext, err := aead.Open(nil, nonce, ciphertext, []byte(archive.AAD))
+	require.NoError(t, err)
+	require.Equal(t, `{"vaults":[]}`, string(plaintext))
+
+	wrongKey := argon2.IDKey(
+		[]byte("wrong passphrase"), salt, archive.KDF.Time, archive.KDF.Memory,
+		archive.KDF.Threads, chacha20poly1305.KeySize,
+	)
+
+	aead, _ = chacha20poly1305.NewX(wrongKey)
+	_, err = aead.Open(nil, nonce, ciphertext, []byte(archive.AAD))
+	require.Error(t, err)
+}
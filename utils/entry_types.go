@@ -0,0 +1,239 @@
+package utils
+
+import (
+	"errors"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	EntryTypeLogin      string = "login"
+	EntryTypeCard       string = "card"
+	EntryTypeIdentity   string = "identity"
+	EntryTypeSecureNote string = "secure_note"
+	EntryTypeSSHKey     string = "ssh_key"
+)
+
+// An EntryField is matched case-insensitively against secret labels, so "Password" and
+// "password" fill the same field. Kind, if set, is the secret_kind the field is stored as and
+// checked against. Validate returns an error message, or "" if the value is fine.
+type EntryField struct {
+	Name     string
+	Required bool
+	Kind     string
+	Validate func(value string) string
+}
+
+type EntryFieldValue struct {
+	Label string
+	Value string
+}
+
+// EntryTypes lists each entry type's fields. Secrets whose labels match none of them are kept
+// as free-form custom fields, and an entry without a type has no schema at all.
+var EntryTypes = map[string][]EntryField{
+	EntryTypeLogin: {
+		{Name: "username"},
+		{Name: "password", Required: true},
+		{Name: "url", Validate: validateEntryURL},
+		{Name: "totp", Kind: SecretKindTOTP},
+	},
+	EntryTypeCard: {
+		{Name: "cardholder_name", Required: true},
+		{Name: "card_number", Required: true, Validate: validateCardNumber},
+		{Name: "expiry", Required: true, Validate: validateCardExpiry},
+		{Name: "cvv", Validate: validateCardCVV},
+		{Name: "pin", Validate: validateCardPIN},
+	},
+	EntryTypeIdentity: {
+		{Name: "full_name", Required: true},
+		{Name: "email", Validate: validateEntryEmail},
+		{Name: "phone", Validate: validateEntryPhone},
+		{Name: "address"},
+		{Name: "date_of_birth", Validate: validateDateOfBirth},
+		{Name: "passport_number"},
+		{Name: "license_number"},
+	},
+	EntryTypeSecureNote: {
+		{Name: "note", Required: true},
+	},
+	EntryTypeSSHKey: {
+		{Name: "private_key", Required: true, Validate: validateSSHPrivateKey},
+		{Name: "public_key", Validate: validateSSHPublicKey},
+		{Name: "passphrase"},
+	},
+}
+
+var EntryTypeNames = []string{
+	EntryTypeLogin, EntryTypeCard, EntryTypeIdentity, EntryTypeSecureNote, EntryTypeSSHKey,
+}
+
+var cardExpiryRegexp = regexp.MustCompile(`^(0[1-9]|1[0-2])/([0-9]{2}|[0-9]{4})$`)
+var cardCVVRegexp = regexp.MustCompile(`^[0-9]{3,4}$`)
+var cardPINRegexp = regexp.MustCompile(`^[0-9]{4,12}$`)
+
+// ValidateEntryFields checks fields against the schema of entryType. Errors are keyed by the
+// index of the offending field; missing lists required fields that weren't given.
+func ValidateEntryFields(entryType string, fields []EntryFieldValue) (
+	fieldErrors map[int]string, missing []string,
+) {
+	fieldErrors = map[int]string{}
+	seen := map[string]bool{}
+
+	for i, field := range fields {
+		schemaField, ok := EntryTypeField(entryType, field.Label)
+
+		if !ok {
+			continue
+		}
+
+		if seen[schemaField.Name] {
+			fieldErrors[i] = "Duplicate " + schemaField.Name + " field"
+		} else if schemaField.Required && strings.TrimSpace(field.Value) == "" {
+			fieldErrors[i] = "Required"
+		} else if schemaField.Validate != nil && field.Value != "" {
+			if message := schemaField.Validate(field.Value); message != "" {
+				fieldErrors[i] = message
+			}
+		}
+
+		seen[schemaField.Name] = true
+	}
+
+	for _, schemaField := range EntryTypes[entryType] {
+		if schemaField.Required && !seen[schemaField.Name] {
+			missing = append(missing, schemaField.Name)
+		}
+	}
+
+	return fieldErrors, missing
+}
+
+// EntryTypeField finds the field of entryType that label fills, if any
+func EntryTypeField(entryType, label string) (EntryField, bool) {
+	label = strings.ReplaceAll(strings.ToLower(strings.TrimSpace(label)), " ", "_")
+
+	for _, field := range EntryTypes[entryType] {
+		if field.Name == label {
+			return field, true
+		}
+	}
+
+	return EntryField{}, false
+}
+
+func validateEntryURL(value string) string {
+	if u, err := url.Parse(value); err != nil || (u.Scheme != "http" && u.Scheme != "https") ||
+	u.Host == "" {
+		return "Must be an http or https URL"
+	}
+
+	return ""
+}
+
+// Card numbers may be grouped with spaces or dashes, and must pass the Luhn checksum
+func validateCardNumber(value string) string {
+	digits := strings.NewReplacer(" ", "", "-", "").Replace(value)
+
+	if len(digits) < 12 || len(digits) > 19 || !LuhnValid(digits) {
+		return "Must be a valid card number"
+	}
+
+	return ""
+}
+
+func LuhnValid(digits string) bool {
+	sum := 0
+	double := false
+
+	for i := len(digits) - 1; i >= 0; i-- {
+		if digits[i] < '0' || digits[i] > '9' {
+			return false
+		}
+
+		d := int(digits[i] - '0')
+
+		if double {
+			if d *= 2; d > 9 {
+				d -= 9
+			}
+		}
+
+		sum += d
+		double = !double
+	}
+
+	return len(digits) > 0 && sum % 10 == 0
+}
+
+func validateCardExpiry(value string) string {
+	if !cardExpiryRegexp.MatchString(value) {
+		return "Must be MM/YY or MM/YYYY"
+	}
+
+	return ""
+}
+
+func validateCardCVV(value string) string {
+	if !cardCVVRegexp.MatchString(value) {
+		return "Must be 3 or 4 digits"
+	}
+
+	return ""
+}
+
+func validateCardPIN(value string) string {
+	if !cardPINRegexp.MatchString(value) {
+		return "Must be 4 to 12 digits"
+	}
+
+	return ""
+}
+
+func validateEntryEmail(value string) string {
+	if !EmailRegexp.MatchString(value) {
+		return "Must be an email address"
+	}
+
+	return ""
+}
+
+func validateEntryPhone(value string) string {
+	if !PhoneRegexp.MatchString(value) {
+		return "Must be an international phone number, e.g. +12125551234"
+	}
+
+	return ""
+}
+
+func validateDateOfBirth(value string) string {
+	if date, err := time.Parse("2006-01-02", value); err != nil || date.After(time.Now()) {
+		return "Must be a past date as YYYY-MM-DD"
+	}
+
+	return ""
+}
+
+// Passphrase-protected keys can't be parsed without the passphrase, but are still keys
+func validateSSHPrivateKey(value string) string {
+	var missing *ssh.PassphraseMissingError
+
+	if _, err := ssh.ParseRawPrivateKey([]byte(value)); err != nil && !errors.As(err, &missing) {
+		return "Must be a PEM or OpenSSH private key"
+	}
+
+	return ""
+}
+
+func validateSSHPublicKey(value string) string {
+	if _, _, _, rest, err := ssh.ParseAuthorizedKey([]byte(value)); err != nil ||
+	strings.TrimSpace(string(rest)) != "" {
+		return "Must be a single public key in authorized_keys format"
+	}
+
+	return ""
+}
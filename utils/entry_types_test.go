@@ -0,0 +1,121 @@
+package utils
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+)
+
+func entryFields(pairs ...string) []EntryFieldValue {
+	values := []EntryFieldValue{}
+
+	for i := 0; i < len(pairs); i += 2 {
+		values = append(values, EntryFieldValue{Label: pairs[i], Value: pairs[i + 1]})
+	}
+
+	return values
+}
+
+func TestLuhnValid(t *testing.T) {
+	for _, valid := range []string{"4111111111111111", "79927398713", "5555555555554444"} {
+		require.True(t, LuhnValid(valid), valid)
+	}
+
+	for _, invalid := range []string{"4111111111111112", "79927398710", "4111a11111111111", ""} {
+		require.False(t, LuhnValid(invalid), invalid)
+	}
+}
+
+func TestValidateEntryFields(t *testing.T) {
+	t.Run("card", func(t *testing.T) {
+		errs, missing := ValidateEntryFields(EntryTypeCard, entryFields(
+			"Cardholder Name", "Jane Doe", "Card Number", "4111 1111 1111 1111", "Expiry", "09/2031",
+			"CVV", "123", "Bank phone", "anything goes",
+		))
+		require.Empty(t, errs)
+		require.Empty(t, missing)
+
+		errs, missing = ValidateEntryFields(EntryTypeCard, entryFields(
+			"card_number", "4111 1111 1111 1112", "expiry", "13/31", "cvv", "12", "pin", "12a4",
+		))
+		require.Equal(t, map[int]string{
+			0: "Must be a valid card number",
+			1: "Must be MM/YY or MM/YYYY",
+			2: "Must be 3 or 4 digits",
+			3: "Must be 4 to 12 digits",
+		}, errs)
+		require.Equal(t, []string{"cardholder_name"}, missing)
+	})
+
+	t.Run("login", func(t *testing.T) {
+		errs, missing := ValidateEntryFields(EntryTypeLogin, entryFields(
+			"Username", "jane", "Password", "hunter2", "URL", "https://example.com/login",
+		))
+		require.Empty(t, errs)
+		require.Empty(t, missing)
+
+		invalidURLs := []string{"example.com", "ftp://example.com", "https://", "javascript:x"}
+
+		for _, invalid := range invalidURLs {
+			errs, _ = ValidateEntryFields(EntryTypeLogin, entryFields(
+				"password", "hunter2", "url", invalid,
+			))
+			require.Equal(t, map[int]string{1: "Must be an http or https URL"}, errs, invalid)
+		}
+
+		errs, missing = ValidateEntryFields(EntryTypeLogin, entryFields(
+			"password", " ", "Password", "hunter2",
+		))
+		require.Equal(t, map[int]string{0: "Required", 1: "Duplicate password field"}, errs)
+		require.Empty(t, missing)
+	})
+
+	t.Run("identity", func(t *testing.T) {
+		errs, missing := ValidateEntryFields(EntryTypeIdentity, entryFields(
+			"Full Name", "Jane Doe", "Email", "jane@example.com", "Phone", "+12125551234",
+			"Date of birth", "1990-02-28",
+		))
+		require.Empty(t, errs)
+		require.Empty(t, missing)
+
+		errs, _ = ValidateEntryFields(EntryTypeIdentity, entryFields(
+			"full_name", "Jane Doe", "email", "jane", "phone", "555-1234", "date_of_birth", "28/02/1990",
+		))
+		require.Len(t, errs, 3)
+	})
+
+	t.Run("ssh_key", func(t *testing.T) {
+		publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		block, err := ssh.MarshalPrivateKey(privateKey, "")
+		require.NoError(t, err)
+
+		encrypted, err := ssh.MarshalPrivateKeyWithPassphrase(privateKey, "", []byte("secret"))
+		require.NoError(t, err)
+
+		sshPublicKey, err := ssh.NewPublicKey(publicKey)
+		require.NoError(t, err)
+
+		for _, key := range []*pem.Block{block, encrypted} {
+			errs, missing := ValidateEntryFields(EntryTypeSSHKey, entryFields(
+				"private_key", string(pem.EncodeToMemory(key)),
+				"public_key", string(ssh.MarshalAuthorizedKey(sshPublicKey)),
+			))
+			require.Empty(t, errs)
+			require.Empty(t, missing)
+		}
+
+		errs, _ := ValidateEntryFields(EntryTypeSSHKey, entryFields(
+			"private_key", "not a key", "public_key", "ssh-ed25519 AAAA",
+		))
+		require.Equal(t, map[int]string{
+			0: "Must be a PEM or OpenSSH private key",
+			1: "Must be a single public key in authorized_keys format",
+		}, errs)
+	})
+}
@@ -1,5 +1,7 @@
 package utils
 
+import "strconv"
+
 const (
 	ErrorBadRequest  	string = "Bad request."
 	ErrorToken       	string = "Invalid token."
@@ -9,4 +11,32 @@ const (
 	ErrorDiffEmail   	string = "Oops, failed to create account - try using a different email address or phone number."
 	ErrorFailedLogin 	string = "Oops, failed to log in - try again!"
 	ErrorAuthenticate	string = "Oops, failed to authenticate - try again!"
+	ErrorNoTOTPSecret	string = "This entry has no TOTP secret."
+	ErrorSecretVersion	string = "This version is no longer available."
+	ErrorNotFound				string = "Not found."
+	ErrorPreconditionRequired	string = "Missing If-Match header - retrieve the item for its current ETag."
+	ErrorPreconditionFailed		string = "This item was changed elsewhere - retrieve it again before saving."
+	ErrorIdempotencyKeyReused			string = "This Idempotency-Key was already used for a different request."
+	ErrorIdempotencyKeyInProgress	string = "A request with this Idempotency-Key is still in progress - try again shortly."
+	ErrorRequestTooLarge					string = "This request is too large."
+	ErrorAttachmentUnsupported		string = "This file type can't be attached."
+	ErrorVaultPermission					string = "You don't have permission to do this in this vault."
+	ErrorNoKeyPairDetail					string = "Set up your encryption keys before joining a shared vault."
+	ErrorAlreadyMember						string = "This person is already a member of the vault."
+	ErrorRekeyRequired						string = "A member has left this vault - rotate its key first."
+	ErrorOrgPermission						string = "You don't have permission to do this in this organization."
+	ErrorAlreadyOrgMember					string = "This person is already a member of the organization."
+	ErrorLastOrgOwner							string = "An organization must keep at least one owner."
+	ErrorAlreadyEmergencyContact	string = "This person is already one of your emergency contacts."
+	ErrorEmergencyRequested				string = "Emergency access has already been requested."
+	ErrorNoEmergencyRequest				string = "There's no emergency access request to reject."
+)
+
+// Built from the attachment limits so the message can't drift from what's enforced
+var (
+	ErrorAttachmentTooLarge string = "Attachments can be at most " +
+		strconv.FormatInt(AttachmentMaxSize >> 20, 10) + " MB."
+	ErrorAttachmentQuotaExceeded string = "You've used all of your " +
+		strconv.FormatInt(AttachmentsQuota >> 20, 10) +
+		" MB of attachment storage - delete some attachments first."
 )
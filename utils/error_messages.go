@@ -22,8 +22,84 @@ const (
 	ErrorVaultsCreateSecret		string = "Failed vaults API create_secret."
 	ErrorVaultsUpdateSecret		string = "Failed vaults API update_secret."
 	ErrorVaultsMoveSecret			string = "Failed vaults API move_secret."
+	ErrorVaultsMoveEntry			string = "Failed vaults API move_entry."
+	ErrorMoveEntry						string = "Invalid move_entry target vault."
 	ErrorVaultsDeleteSecret		string = "Failed vaults API delete_secret."
 	ErrorVaultsDeleteUser			string = "Failed vaults API delete_user."
+	ErrorVaultsSearchEntries	string = "Failed vaults API search_entries."
+	ErrorImportEntries				string = "Invalid import_entries request."
+	ErrorExportVaults					string = "Failed export_vaults."
+	ErrorVaultsExportVaults		string = "Failed vaults API export_vaults."
+	InfoExportVaults					string = "Vaults exported."
+	ErrorGeneratePassword		string = "Failed generate_password."
+	ErrorCheckBreached				string = "Failed check_breached."
+	ErrorHealthReport				string = "Failed vault_health_report."
+	ErrorVaultsHealthReport	string = "Failed vaults API vault_health_report."
+	ErrorSecretKind					string = "Invalid secret_kind or secret_string."
+	ErrorEntryType					string = "Invalid entry_type or entry fields."
+	ErrorEntryTOTP					string = "Failed get_entry_totp."
+	ErrorVaultsEntryTOTP		string = "Failed vaults API get_entry_totp."
+	ErrorRestoreSecretVersion				string = "Secret version not retained."
+	ErrorVaultsListSecretVersions		string = "Failed vaults API list_secret_versions."
+	ErrorVaultsRestoreSecretVersion	string = "Failed vaults API restore_secret_version."
+	ErrorVaultsListTrash		string = "Failed vaults API list_trash."
+	ErrorVaultsRestoreItem	string = "Failed vaults API restore_item."
+	ErrorVaultsEmptyTrash		string = "Failed vaults API empty_trash."
+	ErrorBatch							string = "Invalid batch request."
+	ErrorTag								string = "Invalid tag."
+	ErrorTagLimit						string = "Tag limit reached."
+	ErrorSetFavorite				string = "Invalid set_favorite request."
+	ErrorVaultsAddTag				string = "Failed vaults API add_tag."
+	ErrorVaultsRemoveTag		string = "Failed vaults API remove_tag."
+	ErrorVaultsListTags			string = "Failed vaults API list_tags."
+	ErrorVaultsSetFavorite	string = "Failed vaults API set_favorite."
+	ErrorVaultsMatchURL			string = "Failed vaults API match_url."
+	ErrorURLMatch						string = "Invalid url_match rule."
+	ErrorShare							string = "Invalid share."
+	ErrorShareLimit					string = "Share limit reached."
+	ErrorShareNotFound			string = "Share not found, expired or used up."
+	ErrorAttachment					string = "Invalid attachment."
+	ErrorAttachmentSize			string = "Attachment too large."
+	ErrorBodyLimit					string = "Request body over limit."
+	ErrorAttachmentType			string = "Attachment type not allowed."
+	ErrorAttachmentQuota		string = "Attachment storage quota exceeded."
+	ErrorVaultsUploadAttachment		string = "Failed vaults API upload_attachment."
+	ErrorVaultsListAttachments		string = "Failed vaults API list_attachments."
+	ErrorVaultsDownloadAttachment	string = "Failed vaults API download_attachment."
+	ErrorVaultsDeleteAttachment		string = "Failed vaults API delete_attachment."
+	ErrorKeyPair						string = "Invalid key pair."
+	ErrorNoKeyPair					string = "User has no key pair."
+	ErrorVaultRole					string = "Insufficient vault role."
+	ErrorVaultMember				string = "Invalid vault member request."
+	ErrorVaultKeys					string = "Invalid vault keys."
+	ErrorInvitation					string = "Invalid invitation."
+	ErrorInvitationNotFound	string = "Invitation not found or expired."
+	ErrorVaultNotShared			string = "Vault not shared or user not a member."
+	ErrorOrg								string = "Invalid organization request."
+	ErrorOrgNotFound				string = "Organization not found or user not a member."
+	ErrorOrgRole						string = "Insufficient organization role."
+	ErrorOrgMember					string = "Invalid organization member request."
+	ErrorOrgGroupNotFound		string = "Organization group not found."
+	ErrorOrgVaultGrant			string = "Invalid organization vault grant."
+	ErrorEmergencyContact		string = "Invalid emergency contact request."
+	ErrorEmergencyNotFound	string = "Emergency contact not found."
+	InfoEmergencyAdded			string = "Emergency contact added."
+	InfoEmergencyUpdated		string = "Emergency contact updated."
+	InfoEmergencyRemoved		string = "Emergency contact removed."
+	InfoEmergencyRequested	string = "Emergency access requested."
+	InfoEmergencyRejected		string = "Emergency access rejected."
+	InfoEmergencyAccess			string = "Emergency access used."
+	InfoEmergencyReminded		string = "Emergency access reminder sent."
+	InfoEmergencyOpened			string = "Emergency access opened."
+	ErrorVaultsBatch				string = "Failed vaults API batch."
+	ErrorIfMatch						string = "Missing or invalid If-Match header."
+	ErrorStaleIfMatch				string = "Stale If-Match header."
+	ErrorIdempotencyKey					string = "Invalid Idempotency-Key header."
+	ErrorIdempotencyMismatch		string = "Idempotency-Key reused with a different request."
+	ErrorIdempotencyInProgress	string = "Idempotency-Key request still in progress."
+	ErrorIdempotency						string = "Failed idempotency store operation."
+	ErrorVaultsToken					string = "Failed sign vaults service token."
+	ErrorVaultsUpstream				string = "No healthy vaults upstream."
 	ErrorFailedDB       			string = "Failed DB operation."
 	ErrorNoRowsAffected 			string = "result.RowsAffected == 0"
 	ErrorIPMismatch 					string = "Different IP addresses."
@@ -9,9 +9,7 @@ import (
 	"golang.org/x/crypto/argon2"
 )
 
-var lettersSize = big.NewInt(int64(len(UPPERCASE_LETTERS)))
 var digitSize = big.NewInt(int64(len(DIGITS)))
-var specialCharsSize = big.NewInt(int64(len(SPECIAL_CHARS)))
 var slugAlphabetSize = big.NewInt(int64(len(SLUG_ALPHABET)))
 var passwordAlphabetSize = big.NewInt(int64(len(PASSWORD_ALPHABET)))
 var otpAlphabetSize = big.NewInt(int64(len(OTP_ALPHABET)))
@@ -25,56 +23,17 @@ func init() {
 }
 
 func GeneratePassword(n int) (string, error) {
-	byte_password := make([]byte, n)
-	firstChars := make([]byte, 4)
-
-	var num *big.Int
-	var err error
-
-	if num, err = rand.Int(rand.Reader, lettersSize); err != nil {
-		return "", err
-	} else {
-		firstChars[0] = UPPERCASE_LETTERS[num.Int64()]
-	}
-
-	if num, err = rand.Int(rand.Reader, lettersSize); err != nil {
-		return "", err
-	} else {
-		firstChars[1] = LOWERCASE_LETTERS[num.Int64()]
-	}
-
-	if num, err = rand.Int(rand.Reader, digitSize); err != nil {
-		return "", err
-	} else {
-		firstChars[2] = DIGITS[num.Int64()]
-	}
-
-	if num, err = rand.Int(rand.Reader, specialCharsSize); err != nil {
-		return "", err
-	} else {
-		firstChars[3] = SPECIAL_CHARS[num.Int64()]
-	}
-
-	for i := 0; i < 4; {
-		if num, err = rand.Int(rand.Reader, big.NewInt(int64(n))); err != nil {
-			return "", err
-		} else if index := num.Int64(); byte_password[index] == 0 {
-			byte_password[index] = firstChars[i]
-			i++
-		}
-	}
-
-	for i := 0; i < n; i++ {
-		if byte_password[i] != 0 {
-			continue
-		} else if num, err = rand.Int(rand.Reader, passwordAlphabetSize); err != nil {
-			return "", err
-		} else {
-			byte_password[i] = PASSWORD_ALPHABET[num.Int64()]
-		}
-	}
-
-	return string(byte_password), nil
+	return GeneratePasswordWithOptions(&PasswordOptions{
+		Length:       n,
+		Uppercase:    true,
+		Lowercase:    true,
+		Digits:       true,
+		Special:      true,
+		MinUppercase: 1,
+		MinLowercase: 1,
+		MinDigits:    1,
+		MinSpecial:   1,
+	})
 }
 
 func GenerateSlug(n int) (string, error) {
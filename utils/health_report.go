@@ -0,0 +1,249 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const HealthStaleAfter = time.Duration(365 * 24) * time.Hour
+
+const healthMinLength int = 8
+const healthWeakScore int = 2
+
+// Words that mark a secret's label as a password worth scoring, rather than a username or note
+var healthPasswordLabels = []string{"password", "passphrase", "passcode", "pin"}
+
+// HealthSecret is one password-like secret of an entry. The report never copies String out.
+// UpdatedAt is when the secret itself last changed, zero if vaults didn't say.
+type HealthSecret struct {
+	EntrySlug string
+	Label     string
+	String    string
+	UpdatedAt time.Time
+}
+
+type HealthWeakEntry struct {
+	EntrySlug string `json:"entry_slug"`
+	Score     int    `json:"score"`
+}
+
+type HealthStaleEntry struct {
+	EntrySlug string `json:"entry_slug"`
+	AgeDays   int    `json:"age_days"`
+}
+
+type HealthReport struct {
+	GeneratedAt    time.Time          `json:"generated_at"`
+	TotalEntries   int                `json:"total_entries"`
+	TotalPasswords int                `json:"total_passwords"`
+	ReusedCount    int                `json:"reused_count"`
+	ReusedGroups   [][]string         `json:"reused_groups"`
+	WeakCount      int                `json:"weak_count"`
+	WeakEntries    []HealthWeakEntry  `json:"weak_entries"`
+	StaleCount     int                `json:"stale_count"`
+	StaleEntries   []HealthStaleEntry `json:"stale_entries"`
+}
+
+// IsPasswordLabel matches whole words of the label, split at anything but a letter and at
+// camelCase humps, so "Bank PIN" and "adminPassword" count but "Shipping address" doesn't.
+func IsPasswordLabel(label string) bool {
+	for _, word := range labelWords(label) {
+		for _, l := range healthPasswordLabels {
+			if word == l {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func labelWords(label string) []string {
+	var words []string
+	var word []rune
+
+	for _, r := range label {
+		if !unicode.IsLetter(r) || unicode.IsUpper(r) && len(word) > 0 &&
+		unicode.IsLower(word[len(word) - 1]) {
+			if len(word) > 0 {
+				words = append(words, strings.ToLower(string(word)))
+				word = nil
+			}
+		}
+
+		if unicode.IsLetter(r) {
+			word = append(word, r)
+		}
+	}
+
+	if len(word) > 0 {
+		words = append(words, strings.ToLower(string(word)))
+	}
+
+	return words
+}
+
+// EstimateEntropy treats s as drawn uniformly from the union of the character classes it uses.
+func EstimateEntropy(s string) float64 {
+	pool := 0
+
+	if ContainsLowercase(s) {
+		pool += len(LOWERCASE_LETTERS)
+	}
+
+	if ContainsUppercase(s) {
+		pool += len(UPPERCASE_LETTERS)
+	}
+
+	if ContainsNumber(s) {
+		pool += len(DIGITS)
+	}
+
+	if ContainsSpecialChar(s) || ContainsWhitespace(s) {
+		pool += len(SPECIAL_CHARS) + 1
+	}
+
+	if pool == 0 {
+		// Only characters outside the known classes, e.g. non-Latin letters
+		pool = len(LOWERCASE_LETTERS)
+	}
+
+	return float64(len([]rune(s))) * math.Log2(float64(pool))
+}
+
+// PasswordStrengthScore rates s from 0 (trivial) to 4 (strong). Anything under healthMinLength
+// characters, or made of a single character class, is capped at 1.
+func PasswordStrengthScore(s string) int {
+	var score int
+
+	switch bits := EstimateEntropy(s); {
+	case bits < 28:
+		score = 0
+	case bits < 36:
+		score = 1
+	case bits < 60:
+		score = 2
+	case bits < 128:
+		score = 3
+	default:
+		score = 4
+	}
+
+	classes := 0
+
+	for _, contains := range []bool{
+		ContainsUppercase(s), ContainsLowercase(s), ContainsNumber(s), ContainsSpecialChar(s),
+	} {
+		if contains {
+			classes++
+		}
+	}
+
+	if (len([]rune(s)) < healthMinLength || classes < 2) && score > 1 {
+		score = 1
+	}
+
+	return score
+}
+
+// BuildHealthReport groups reused secrets by HMAC-SHA256 under a random key that only lives for
+// this call, so the digests can't be correlated across reports or brute forced after the fact.
+func BuildHealthReport(
+	secrets []HealthSecret, totalEntries int, now time.Time, staleAfter time.Duration,
+) (*HealthReport, error) {
+	key := make([]byte, 32)
+
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+
+	report := HealthReport{
+		GeneratedAt:    now,
+		TotalEntries:   totalEntries,
+		TotalPasswords: len(secrets),
+		ReusedGroups:   [][]string{},
+		WeakEntries:    []HealthWeakEntry{},
+		StaleEntries:   []HealthStaleEntry{},
+	}
+
+	groups := map[string][]string{}
+	weak := map[string]int{}
+	stale := map[string]int{}
+
+	for _, secret := range secrets {
+		mac := hmac.New(sha256.New, key)
+		mac.Write([]byte(secret.String))
+		digest := string(mac.Sum(nil))
+
+		if !containsString(groups[digest], secret.EntrySlug) {
+			groups[digest] = append(groups[digest], secret.EntrySlug)
+		}
+
+		if score := PasswordStrengthScore(secret.String); score < healthWeakScore {
+			if prev, ok := weak[secret.EntrySlug]; !ok || score < prev {
+				weak[secret.EntrySlug] = score
+			}
+		}
+
+		if !secret.UpdatedAt.IsZero() && now.Sub(secret.UpdatedAt) > staleAfter {
+			if days := int(now.Sub(secret.UpdatedAt).Hours() / 24); days > stale[secret.EntrySlug] {
+				stale[secret.EntrySlug] = days
+			}
+		}
+	}
+
+	reused := map[string]bool{}
+
+	for _, slugs := range groups {
+		if len(slugs) > 1 {
+			sort.Strings(slugs)
+			report.ReusedGroups = append(report.ReusedGroups, slugs)
+
+			for _, slug := range slugs {
+				reused[slug] = true
+			}
+		}
+	}
+
+	sort.Slice(report.ReusedGroups, func(i, j int) bool {
+		return report.ReusedGroups[i][0] < report.ReusedGroups[j][0]
+	})
+
+	for slug, score := range weak {
+		report.WeakEntries = append(report.WeakEntries, HealthWeakEntry{slug, score})
+	}
+
+	sort.Slice(report.WeakEntries, func(i, j int) bool {
+		return report.WeakEntries[i].EntrySlug < report.WeakEntries[j].EntrySlug
+	})
+
+	for slug, days := range stale {
+		report.StaleEntries = append(report.StaleEntries, HealthStaleEntry{slug, days})
+	}
+
+	sort.Slice(report.StaleEntries, func(i, j int) bool {
+		return report.StaleEntries[i].EntrySlug < report.StaleEntries[j].EntrySlug
+	})
+
+	report.ReusedCount = len(reused)
+	report.WeakCount = len(report.WeakEntries)
+	report.StaleCount = len(report.StaleEntries)
+
+	return &report, nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+
+	return false
+}
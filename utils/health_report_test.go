@@ -0,0 +1,79 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPasswordStrengthScore(t *testing.T) {
+	require.Equal(t, 0, PasswordStrengthScore("123456"))
+	require.Equal(t, 1, PasswordStrengthScore("password"))
+	require.Equal(t, 1, PasswordStrengthScore("abcdefghijklmnopqrstuvwxyz"))
+	require.Equal(t, 1, PasswordStrengthScore("Pa55!"))
+	require.Equal(t, 2, PasswordStrengthScore("Password1"))
+	require.Equal(t, 3, PasswordStrengthScore("correct-horse-battery"))
+	require.Equal(t, 4, PasswordStrengthScore("kP9#vW2!qZ7$mL4@xR8&nT3*bY6^hJ1%"))
+}
+
+func TestIsPasswordLabel(t *testing.T) {
+	require.True(t, IsPasswordLabel("Password"))
+	require.True(t, IsPasswordLabel("Wi-Fi passphrase"))
+	require.True(t, IsPasswordLabel("PIN"))
+	require.True(t, IsPasswordLabel("Bank PIN"))
+	require.True(t, IsPasswordLabel("DB_PASSWORD"))
+	require.True(t, IsPasswordLabel("adminPassword"))
+	require.False(t, IsPasswordLabel("Username"))
+	require.False(t, IsPasswordLabel("URL"))
+	require.False(t, IsPasswordLabel("Shipping address"))
+	require.False(t, IsPasswordLabel("Pinterest"))
+	require.False(t, IsPasswordLabel("Spinner"))
+}
+
+func TestBuildHealthReport(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	secrets := []HealthSecret{
+		{EntrySlug: "a", Label: "Password", String: "hunter2", UpdatedAt: now.AddDate(-2, 0, 0)},
+		{EntrySlug: "b", Label: "Password", String: "hunter2", UpdatedAt: now.AddDate(0, -1, 0)},
+		{EntrySlug: "c", Label: "Password", String: "kP9#vW2!qZ7$mL4@", UpdatedAt: now},
+		{EntrySlug: "c", Label: "PIN", String: "kP9#vW2!qZ7$mL4@", UpdatedAt: now},
+		{EntrySlug: "d", Label: "Password", String: "tR5^yU8*iO1(pA3)", UpdatedAt: now},
+		{EntrySlug: "e", Label: "Password", String: "tR5^yU8*iO1(pA3)"},
+	}
+
+	t.Run("report", func(t *testing.T) {
+		report, err := BuildHealthReport(secrets, 6, now, HealthStaleAfter)
+		require.NoError(t, err)
+		require.Equal(t, 6, report.TotalEntries)
+		require.Equal(t, 6, report.TotalPasswords)
+
+		// "c" reuses its own password under two labels, which isn't cross-entry reuse
+		require.Equal(t, [][]string{{"a", "b"}, {"d", "e"}}, report.ReusedGroups)
+		require.Equal(t, 4, report.ReusedCount)
+
+		require.Equal(t, []HealthWeakEntry{
+			{EntrySlug: "a", Score: 1}, {EntrySlug: "b", Score: 1},
+		}, report.WeakEntries)
+		require.Equal(t, 2, report.WeakCount)
+
+		// "e" has no timestamp, so its age is unknown rather than stale
+		require.Equal(t, []HealthStaleEntry{
+			{EntrySlug: "a", AgeDays: 731},
+		}, report.StaleEntries)
+		require.Equal(t, 1, report.StaleCount)
+	})
+
+	t.Run("report_never_contains_plaintext", func(t *testing.T) {
+		report, err := BuildHealthReport(secrets, 6, now, HealthStaleAfter)
+		require.NoError(t, err)
+
+		body, err := json.Marshal(report)
+		require.NoError(t, err)
+
+		for _, secret := range secrets {
+			require.NotContains(t, string(body), secret.String)
+		}
+	})
+}
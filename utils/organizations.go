@@ -0,0 +1,47 @@
+package utils
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	OrgRoleOwner  string = "owner"
+	OrgRoleAdmin  string = "admin"
+	OrgRoleMember string = "member"
+)
+
+const (
+	OrgSlugLength           int           = 16
+	OrgGroupSlugLength      int           = 16
+	OrgNameMaxLength        int           = 64
+	OrgInvitationSlugLength int           = 32
+	OrgInvitationTTL        time.Duration = 7 * 24 * time.Hour
+)
+
+var OrgRoles = []string{OrgRoleOwner, OrgRoleAdmin, OrgRoleMember}
+
+var orgRoleRanks = map[string]int{OrgRoleMember: 1, OrgRoleAdmin: 2, OrgRoleOwner: 3}
+
+func ValidOrgRole(role string) bool {
+	return orgRoleRanks[role] > 0
+}
+
+// OrgRoleAtLeast reports whether role grants everything required does: owner > admin > member.
+// Owners and admins may do anything in the org's vaults; members only what their groups were
+// granted.
+func OrgRoleAtLeast(role, required string) bool {
+	return ValidOrgRole(role) && orgRoleRanks[role] >= orgRoleRanks[required]
+}
+
+// ValidateOrgName checks the name of an organization or group, returning its field errors
+func ValidateOrgName(name string) []string {
+	if strings.TrimSpace(name) == "" {
+		return []string{"Required"}
+	} else if utf8.RuneCountInString(name) > OrgNameMaxLength || ControlCharRegexp.MatchString(name) {
+		return []string{"Must be at most 64 characters, without control characters"}
+	}
+
+	return nil
+}
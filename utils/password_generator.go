@@ -0,0 +1,227 @@
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	_ "embed"
+	"errors"
+	"math"
+	"math/big"
+	"strings"
+)
+
+//go:embed wordlists/eff_large_wordlist.txt
+var effLargeWordlist []byte
+
+// EFF large wordlist, one word per 5-dice roll, less the few hyphenated words (drop-down,
+// felt-tip, t-shirt, yo-yo) that would be indistinguishable from the "-" separator
+var passphraseWords = loadWordlist(effLargeWordlist)
+
+var ErrPasswordOptions = errors.New("invalid password options")
+
+type PasswordOptions struct {
+	Length           int
+	Uppercase        bool
+	Lowercase        bool
+	Digits           bool
+	Special          bool
+	ExcludeAmbiguous bool
+	MinUppercase     int
+	MinLowercase     int
+	MinDigits        int
+	MinSpecial       int
+}
+
+type PassphraseOptions struct {
+	Words         int
+	Separator     string
+	Capitalize    bool
+	IncludeNumber bool
+}
+
+type passwordClass struct {
+	alphabet string
+	min      int
+}
+
+func loadWordlist(data []byte) []string {
+	var words []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+
+	for scanner.Scan() {
+		if _, word, ok := strings.Cut(scanner.Text(), "\t"); ok && !strings.Contains(word, "-") {
+			words = append(words, word)
+		}
+	}
+
+	return words
+}
+
+func (opts *PasswordOptions) classes() []passwordClass {
+	var classes []passwordClass
+
+	add := func(enabled bool, alphabet string, min int) {
+		if !enabled {
+			return
+		}
+
+		if opts.ExcludeAmbiguous {
+			alphabet = strings.Map(func(r rune) rune {
+				if strings.ContainsRune(AMBIGUOUS_CHARS, r) {
+					return -1
+				}
+
+				return r
+			}, alphabet)
+		}
+
+		classes = append(classes, passwordClass{alphabet, min})
+	}
+
+	add(opts.Uppercase, UPPERCASE_LETTERS, opts.MinUppercase)
+	add(opts.Lowercase, LOWERCASE_LETTERS, opts.MinLowercase)
+	add(opts.Digits, DIGITS, opts.MinDigits)
+	add(opts.Special, SPECIAL_CHARS, opts.MinSpecial)
+
+	return classes
+}
+
+// PasswordEntropy estimates the entropy in bits of a password generated with opts, treating
+// every character as drawn uniformly from the combined alphabet.
+func PasswordEntropy(opts *PasswordOptions) float64 {
+	size := 0
+
+	for _, class := range opts.classes() {
+		size += len(class.alphabet)
+	}
+
+	if size == 0 {
+		return 0
+	}
+
+	return float64(opts.Length) * math.Log2(float64(size))
+}
+
+func PassphraseEntropy(opts *PassphraseOptions) float64 {
+	bits := float64(opts.Words) * math.Log2(float64(len(passphraseWords)))
+
+	if opts.IncludeNumber {
+		// Which word gets the digit, and which digit
+		bits += math.Log2(float64(opts.Words)) + math.Log2(10)
+	}
+
+	return bits
+}
+
+// GeneratePasswordWithOptions places the required minimum of each enabled class first, fills
+// the rest from the combined alphabet, then shuffles so the required characters can be anywhere.
+func GeneratePasswordWithOptions(opts *PasswordOptions) (string, error) {
+	classes := opts.classes()
+
+	if len(classes) == 0 || opts.Length < 1 {
+		return "", ErrPasswordOptions
+	}
+
+	var alphabet string
+	required := 0
+
+	for _, class := range classes {
+		if class.min < 0 {
+			return "", ErrPasswordOptions
+		}
+
+		alphabet += class.alphabet
+		required += class.min
+	}
+
+	if required > opts.Length {
+		return "", ErrPasswordOptions
+	}
+
+	password := make([]byte, 0, opts.Length)
+
+	for _, class := range classes {
+		for i := 0; i < class.min; i++ {
+			if c, err := randomChar(class.alphabet); err != nil {
+				return "", err
+			} else {
+				password = append(password, c)
+			}
+		}
+	}
+
+	for len(password) < opts.Length {
+		if c, err := randomChar(alphabet); err != nil {
+			return "", err
+		} else {
+			password = append(password, c)
+		}
+	}
+
+	// Fisher-Yates
+	for i := len(password) - 1; i > 0; i-- {
+		if num, err := rand.Int(rand.Reader, big.NewInt(int64(i + 1))); err != nil {
+			return "", err
+		} else {
+			j := num.Int64()
+			password[i], password[j] = password[j], password[i]
+		}
+	}
+
+	return string(password), nil
+}
+
+func GeneratePassphrase(opts *PassphraseOptions) (string, error) {
+	if opts.Words < 1 {
+		return "", ErrPasswordOptions
+	}
+
+	words := make([]string, opts.Words)
+	wordsSize := big.NewInt(int64(len(passphraseWords)))
+
+	for i := range words {
+		if num, err := rand.Int(rand.Reader, wordsSize); err != nil {
+			return "", err
+		} else {
+			words[i] = passphraseWords[num.Int64()]
+		}
+
+		if opts.Capitalize {
+			words[i] = strings.ToUpper(words[i][:1]) + words[i][1:]
+		}
+	}
+
+	if opts.IncludeNumber {
+		var i, digit *big.Int
+		var err error
+
+		if i, err = rand.Int(rand.Reader, big.NewInt(int64(opts.Words))); err != nil {
+			return "", err
+		} else if digit, err = rand.Int(rand.Reader, digitSize); err != nil {
+			return "", err
+		}
+
+		words[i.Int64()] += string(DIGITS[digit.Int64()])
+	}
+
+	return strings.Join(words, opts.Separator), nil
+}
+
+func PassphraseWordlistContains(word string) bool {
+	for _, w := range passphraseWords {
+		if w == word {
+			return true
+		}
+	}
+
+	return false
+}
+
+func randomChar(alphabet string) (byte, error) {
+	if num, err := rand.Int(rand.Reader, big.NewInt(int64(len(alphabet)))); err != nil {
+		return 0, err
+	} else {
+		return alphabet[num.Int64()], nil
+	}
+}
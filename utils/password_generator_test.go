@@ -0,0 +1,146 @@
+package utils
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGeneratePassword(t *testing.T) {
+	password, err := GeneratePassword(16)
+	require.NoError(t, err)
+	require.Len(t, password, 16)
+
+	for _, chars := range []string{UPPERCASE_LETTERS, LOWERCASE_LETTERS, DIGITS, SPECIAL_CHARS} {
+		require.True(t, strings.ContainsAny(password, chars), password)
+	}
+}
+
+func TestGeneratePasswordWithOptions(t *testing.T) {
+	t.Run("class_guarantees", func(t *testing.T) {
+		opts := PasswordOptions{
+			Length:           12,
+			Uppercase:        true,
+			Lowercase:        true,
+			Digits:           true,
+			Special:          true,
+			ExcludeAmbiguous: true,
+			MinUppercase:     2,
+			MinLowercase:     1,
+			MinDigits:        3,
+			MinSpecial:       4,
+		}
+
+		for i := 0; i < 500; i++ {
+			password, err := GeneratePasswordWithOptions(&opts)
+			require.NoError(t, err)
+			require.Len(t, password, 12)
+			require.False(t, strings.ContainsAny(password, AMBIGUOUS_CHARS))
+			require.GreaterOrEqual(t, countAny(password, UPPERCASE_LETTERS), 2)
+			require.GreaterOrEqual(t, countAny(password, LOWERCASE_LETTERS), 1)
+			require.GreaterOrEqual(t, countAny(password, DIGITS), 3)
+			require.GreaterOrEqual(t, countAny(password, SPECIAL_CHARS), 4)
+		}
+	})
+
+	t.Run("disabled_classes_absent", func(t *testing.T) {
+		password, err := GeneratePasswordWithOptions(&PasswordOptions{
+			Length: 64, Digits: true, MinDigits: 1,
+		})
+		require.NoError(t, err)
+		require.Equal(t, 64, countAny(password, DIGITS))
+	})
+
+	t.Run("impossible_options", func(t *testing.T) {
+		_, err := GeneratePasswordWithOptions(&PasswordOptions{Length: 8})
+		require.ErrorIs(t, err, ErrPasswordOptions)
+
+		_, err = GeneratePasswordWithOptions(&PasswordOptions{
+			Length: 4, Digits: true, MinDigits: 5,
+		})
+		require.ErrorIs(t, err, ErrPasswordOptions)
+	})
+
+	t.Run("uniform_distribution", func(t *testing.T) {
+		// Chi-squared over the digit alphabet - 9 degrees of freedom, p = 0.001 at 27.88
+		counts := map[rune]int{}
+		total := 0
+
+		for i := 0; i < 200; i++ {
+			password, err := GeneratePasswordWithOptions(&PasswordOptions{
+				Length: 100, Digits: true,
+			})
+			require.NoError(t, err)
+
+			for _, r := range password {
+				counts[r]++
+				total++
+			}
+		}
+
+		require.Len(t, counts, len(DIGITS))
+		expected := float64(total) / float64(len(DIGITS))
+		chiSquared := 0.0
+
+		for _, count := range counts {
+			chiSquared += math.Pow(float64(count) - expected, 2) / expected
+		}
+
+		require.Less(t, chiSquared, 27.88)
+	})
+
+	t.Run("required_positions_shuffled", func(t *testing.T) {
+		// Without the shuffle the single required digit would always lead
+		positions := map[int]bool{}
+
+		for i := 0; i < 200; i++ {
+			password, err := GeneratePasswordWithOptions(&PasswordOptions{
+				Length: 8, Lowercase: true, Digits: true, MinDigits: 1,
+			})
+			require.NoError(t, err)
+			positions[strings.IndexAny(password, DIGITS)] = true
+		}
+
+		require.Greater(t, len(positions), 4)
+	})
+}
+
+func TestGeneratePassphrase(t *testing.T) {
+	t.Run("passphrase_number_and_capitalize", func(t *testing.T) {
+		for i := 0; i < 200; i++ {
+			passphrase, err := GeneratePassphrase(&PassphraseOptions{
+				Words: 6, Separator: "-", Capitalize: true, IncludeNumber: true,
+			})
+			require.NoError(t, err)
+			require.Equal(t, 1, countAny(passphrase, DIGITS))
+
+			words := strings.Split(passphrase, "-")
+			require.Len(t, words, 6, passphrase)
+
+			for _, word := range words {
+				require.Contains(t, UPPERCASE_LETTERS, word[:1])
+			}
+		}
+	})
+
+	t.Run("no_hyphenated_words", func(t *testing.T) {
+		for _, word := range []string{"drop-down", "felt-tip", "t-shirt", "yo-yo"} {
+			require.False(t, PassphraseWordlistContains(word), word)
+		}
+
+		require.True(t, PassphraseWordlistContains("abacus"))
+	})
+}
+
+func countAny(s, chars string) (n int) {
+	for _, r := range s {
+		if strings.ContainsRune(chars, r) {
+			n++
+		}
+	}
+
+	return n
+}
+
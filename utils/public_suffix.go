@@ -0,0 +1,100 @@
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	_ "embed"
+	"net"
+	"strings"
+)
+
+//go:embed publicsuffix/public_suffix_list.dat
+var publicSuffixListData []byte
+
+// Mozilla's public suffix list, https://publicsuffix.org/list/public_suffix_list.dat
+var publicSuffixRules = loadPublicSuffixList(publicSuffixListData)
+
+type publicSuffixList struct {
+	rules      map[string]bool
+	wildcards  map[string]bool
+	exceptions map[string]bool
+}
+
+func loadPublicSuffixList(data []byte) *publicSuffixList {
+	list := &publicSuffixList{
+		rules:      map[string]bool{},
+		wildcards:  map[string]bool{},
+		exceptions: map[string]bool{},
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+
+	for scanner.Scan() {
+		rule := strings.ToLower(strings.TrimSpace(scanner.Text()))
+
+		if rule == "" || strings.HasPrefix(rule, "//") {
+			continue
+		}
+
+		if exception, ok := strings.CutPrefix(rule, "!"); ok {
+			list.exceptions[exception] = true
+		} else if parent, ok := strings.CutPrefix(rule, "*."); ok {
+			list.wildcards[parent] = true
+		} else {
+			list.rules[rule] = true
+		}
+	}
+
+	return list
+}
+
+// PublicSuffix returns the longest public suffix of host, following the list's wildcard and
+// exception rules. A TLD missing from the list still counts as a suffix, as the list's own
+// "*" default rule says.
+func PublicSuffix(host string) string {
+	labels := strings.Split(host, ".")
+
+	for i := range labels {
+		suffix := strings.Join(labels[i:], ".")
+
+		if publicSuffixRules.exceptions[suffix] {
+			return strings.Join(labels[i + 1:], ".")
+		}
+
+		// "*.suffix" matches one label more than a plain "suffix" rule, so it wins
+		if i > 0 && publicSuffixRules.wildcards[suffix] {
+			return strings.Join(labels[i - 1:], ".")
+		}
+
+		if publicSuffixRules.rules[suffix] {
+			return suffix
+		}
+	}
+
+	return labels[len(labels) - 1]
+}
+
+// RegistrableDomain returns the public suffix of host plus one more label, so
+// "login.example.co.uk" gives "example.co.uk". IP addresses are their own registrable domain.
+// Returns false if host is itself a public suffix, since nothing under it can be told apart.
+func RegistrableDomain(host string) (string, bool) {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+
+	if host == "" {
+		return "", false
+	}
+
+	if net.ParseIP(host) != nil {
+		return host, true
+	}
+
+	suffix := PublicSuffix(host)
+
+	if len(host) == len(suffix) {
+		return "", false
+	}
+
+	rest := strings.TrimSuffix(host, "." + suffix)
+
+	return rest[strings.LastIndex(rest, ".") + 1:] + "." + suffix, true
+}
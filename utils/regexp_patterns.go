@@ -8,9 +8,17 @@ var (
 	PhoneRegexp			 			 = regexp.MustCompile(`^\+[1-9]\d{1,14}$`)
 	HexEncodedKeyRegexp		 = regexp.MustCompile(`^[A-Fa-f0-9]{64}$`)
 	SlugRegexp       			 = regexp.MustCompile(`^[\w-]{16}$`)
+	ShareSlugRegexp				 = regexp.MustCompile(`^[\w-]{32}$`)
+	InvitationSlugRegexp	 = regexp.MustCompile(`^[\w-]{32}$`)
+	ShareCiphertextRegexp	 = regexp.MustCompile(`^[A-Za-z0-9+/_-]+={0,2}$`)
 	TokenRegexp      			 = regexp.MustCompile(`^[\w-]{80}$`)
 	RowsRegexp       			 = regexp.MustCompile(`^result.RowsAffected \([0-9]+\) > 1$`)
 	AuthHeaderRegexp 			 = regexp.MustCompile(`^[Tt]oken [\w-]{80}$`)
 	TokenNullRegexp  			 = regexp.MustCompile(`^[Tt]oken (null)?$`)
+	CursorRegexp					 = regexp.MustCompile(`^[\w-]{1,256}$`)
+	ControlCharRegexp			 = regexp.MustCompile(`[\x00-\x1F\x7F]`)
+	EntityTagsRegexp			 = regexp.MustCompile(`^(\*|(W/)?"[\x21\x23-\x7E]*"( *, *(W/)?"[\x21\x23-\x7E]*")*)$`)
+	HashPrefixRegexp			 = regexp.MustCompile(`^[A-Fa-f0-9]{5}$`)
+	IdempotencyKeyRegexp	 = regexp.MustCompile(`^[\x21-\x7E]{1,255}$`)
 	UniqueConstraintRegexp = regexp.MustCompile(`(UNIQUE constraint failed: users\.(email_address|phone_number)|ERROR: duplicate key value violates unique constraint "users_(email_address|phone_number)_key")`)
 )
@@ -0,0 +1,17 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEntityTagsRegexp(t *testing.T) {
+	for _, valid := range []string{`*`, `"abc"`, `W/"abc"`, `"a", W/"b"`, `""`} {
+		require.True(t, EntityTagsRegexp.MatchString(valid), valid)
+	}
+
+	for _, invalid := range []string{``, `abc`, `"a"b"`, `W/abc`, `*, "a"`} {
+		require.False(t, EntityTagsRegexp.MatchString(invalid), invalid)
+	}
+}
@@ -0,0 +1,194 @@
+package utils
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const (
+	SearchMatchPrefix string = "prefix"
+	SearchMatchFuzzy  string = "fuzzy"
+)
+
+const searchWeightTitle float64 = 1.0
+const searchWeightURL float64 = 0.8
+const searchWeightLabel float64 = 0.6
+
+// SearchDocument holds the searchable metadata of one entry. There is deliberately no field for
+// secret strings, so values can never be matched against or echoed back.
+type SearchDocument struct {
+	EntrySlug    string   `json:"entry_slug"`
+	VaultSlug    string   `json:"vault_slug"`
+	EntryTitle   string   `json:"entry_title"`
+	EntryURL     string   `json:"entry_url"`
+	SecretLabels []string `json:"secret_labels"`
+	Tags         []string `json:"tags"`
+	Favorite     bool     `json:"favorite"`
+	URLMatch     string   `json:"url_match"`
+}
+
+type SearchResult struct {
+	EntrySlug    string   `json:"entry_slug"`
+	VaultSlug    string   `json:"vault_slug"`
+	EntryTitle   string   `json:"entry_title"`
+	EntryURL     string   `json:"entry_url,omitempty"`
+	Tags         []string `json:"tags,omitempty"`
+	Favorite     bool     `json:"favorite,omitempty"`
+	MatchedField string   `json:"matched_field"`
+	Score        float64  `json:"score"`
+}
+
+// SearchDocuments ranks docs against query. Every field is scored, weighted by how much it
+// says about the entry (title > URL > secret label), and the best field wins.
+func SearchDocuments(docs []SearchDocument, query, match string, limit int) []SearchResult {
+	query = normalizeSearchText(query)
+	fuzzy := match != SearchMatchPrefix
+	results := []SearchResult{}
+
+	for _, doc := range docs {
+		best := SearchResult{}
+
+		if score := searchWeightTitle * ScoreSearchMatch(query, doc.EntryTitle, fuzzy);
+		score > best.Score {
+			best.Score = score
+			best.MatchedField = "entry_title"
+		}
+
+		if score := searchWeightURL * ScoreSearchMatch(query, doc.EntryURL, fuzzy);
+		score > best.Score {
+			best.Score = score
+			best.MatchedField = "entry_url"
+		}
+
+		for _, label := range doc.SecretLabels {
+			if score := searchWeightLabel * ScoreSearchMatch(query, label, fuzzy); score > best.Score {
+				best.Score = score
+				best.MatchedField = "secret_label"
+			}
+		}
+
+		if best.Score > 0 {
+			best.EntrySlug = doc.EntrySlug
+			best.VaultSlug = doc.VaultSlug
+			best.EntryTitle = doc.EntryTitle
+			best.EntryURL = doc.EntryURL
+			best.Tags = doc.Tags
+			best.Favorite = doc.Favorite
+			results = append(results, best)
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+
+		return strings.ToLower(results[i].EntryTitle) < strings.ToLower(results[j].EntryTitle)
+	})
+
+	if len(results) > limit {
+		results = results[:limit]
+	}
+
+	return results
+}
+
+// ScoreSearchMatch returns a score in [0, 1]: exact, prefix, word prefix and substring matches
+// first, then (if fuzzy) the closest word or prefix within a small edit distance.
+func ScoreSearchMatch(query, text string, fuzzy bool) float64 {
+	text = normalizeSearchText(text)
+
+	if query == "" || text == "" {
+		return 0
+	}
+
+	if text == query {
+		return 1.0
+	}
+
+	if strings.HasPrefix(text, query) {
+		return 0.9
+	}
+
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+
+	for _, word := range words {
+		if strings.HasPrefix(word, query) {
+			return 0.8
+		}
+	}
+
+	if strings.Contains(text, query) {
+		return 0.6
+	}
+
+	if !fuzzy {
+		return 0
+	}
+
+	queryRunes := []rune(query)
+	maxEdits := len(queryRunes) / 4
+
+	if maxEdits < 1 {
+		maxEdits = 1
+	}
+
+	if maxEdits > 2 {
+		maxEdits = 2
+	}
+
+	best := maxEdits + 1
+
+	for _, candidate := range append(words, text) {
+		candidateRunes := []rune(candidate)
+
+		// Compare against the candidate's prefix too, so "gitub" still finds "github.com"
+		if len(candidateRunes) > len(queryRunes) + maxEdits {
+			candidateRunes = candidateRunes[:len(queryRunes)]
+		}
+
+		if d := levenshtein(queryRunes, candidateRunes); d < best {
+			best = d
+		}
+	}
+
+	if best > maxEdits {
+		return 0
+	}
+
+	return 0.5 * (1 - float64(best) / float64(len(queryRunes) + 1))
+}
+
+func normalizeSearchText(s string) string {
+	return strings.ToLower(strings.TrimSpace(s))
+}
+
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b) + 1)
+	curr := make([]int, len(b) + 1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+
+			if a[i - 1] == b[j - 1] {
+				cost = 0
+			}
+
+			curr[j] = min(prev[j] + 1, curr[j - 1] + 1, prev[j - 1] + cost)
+		}
+
+		prev, curr = curr, prev
+	}
+
+	return prev[len(b)]
+}
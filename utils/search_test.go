@@ -0,0 +1,83 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchDocuments(t *testing.T) {
+	docs := []SearchDocument{
+		{EntrySlug: "1", EntryTitle: "Work email", SecretLabels: []string{"github backup codes"}},
+		{EntrySlug: "2", EntryTitle: "GitHub", EntryURL: "https://github.com/login"},
+		{EntrySlug: "3", EntryTitle: "Bank", EntryURL: "https://mybank.example"},
+		{EntrySlug: "4", EntryTitle: "GitHub Enterprise"},
+	}
+
+	t.Run("ranking_title_before_label", func(t *testing.T) {
+		results := SearchDocuments(docs, "GitHub", SearchMatchFuzzy, 10)
+		require.Len(t, results, 3)
+		require.Equal(t, "2", results[0].EntrySlug)
+		require.Equal(t, "4", results[1].EntrySlug)
+		require.Equal(t, "1", results[2].EntrySlug)
+		require.Equal(t, "secret_label", results[2].MatchedField)
+	})
+
+	t.Run("fuzzy_typo", func(t *testing.T) {
+		results := SearchDocuments(docs, "githb", SearchMatchFuzzy, 10)
+		require.NotEmpty(t, results)
+		require.Equal(t, "2", results[0].EntrySlug)
+		require.Empty(t, SearchDocuments(docs, "githb", SearchMatchPrefix, 10))
+	})
+
+	t.Run("limit", func(t *testing.T) {
+		require.Len(t, SearchDocuments(docs, "git", SearchMatchPrefix, 1), 1)
+	})
+}
+
+func TestScoreSearchMatch(t *testing.T) {
+	cases := []struct {
+		query string
+		text  string
+		fuzzy bool
+		score float64
+	}{
+		{"github", "GitHub", false, 1.0},
+		{"git", "GitHub", false, 0.9},
+		{"hub", "Git hub", false, 0.8},
+		{"thu", "GitHub", false, 0.6},
+		{"gitub", "GitHub", false, 0},
+		{"gitub", "github.com", true, 0.5 * (1 - 1.0 / 6)},
+		{"gtb", "GitHub", true, 0},
+		{"", "GitHub", true, 0},
+		{"github", "  ", true, 0},
+	}
+
+	for _, tc := range cases {
+		require.Equal(
+			t, tc.score, ScoreSearchMatch(tc.query, tc.text, tc.fuzzy), tc.query + " " + tc.text,
+		)
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	cases := []struct {
+		a        string
+		b        string
+		distance int
+	}{
+		{"", "", 0},
+		{"", "abc", 3},
+		{"abc", "", 3},
+		{"github", "github", 0},
+		{"githb", "github", 1},
+		{"gihtub", "github", 2},
+		{"kitten", "sitting", 3},
+		{"café", "cafe", 1},
+	}
+
+	for _, tc := range cases {
+		require.Equal(t, tc.distance, levenshtein([]rune(tc.a), []rune(tc.b)), tc.a + " " + tc.b)
+		require.Equal(t, tc.distance, levenshtein([]rune(tc.b), []rune(tc.a)), tc.b + " " + tc.a)
+	}
+}
@@ -0,0 +1,71 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignServiceToken(t *testing.T) {
+	hexKey := strings.Repeat("ab", 32)
+
+	t.Run("header_claims_and_signature", func(t *testing.T) {
+		before := time.Now().UTC().Unix()
+		token, err := SignServiceToken(
+			"key-2", hexKey, "user_slug", "retrieve_vault", "request_id", 60 * time.Second,
+		)
+		require.NoError(t, err)
+
+		parts := strings.Split(token, ".")
+		require.Len(t, parts, 3)
+
+		headerJSON, err := base64.RawURLEncoding.DecodeString(parts[0])
+		require.NoError(t, err)
+
+		var header serviceTokenHeader
+		require.NoError(t, json.Unmarshal(headerJSON, &header))
+		require.Equal(t, serviceTokenHeader{Algorithm: "HS256", Type: "JWT", KeyID: "key-2"}, header)
+
+		claimsJSON, err := base64.RawURLEncoding.DecodeString(parts[1])
+		require.NoError(t, err)
+
+		var claims ServiceTokenClaims
+		require.NoError(t, json.Unmarshal(claimsJSON, &claims))
+		require.Equal(t, ServiceTokenIssuer, claims.Issuer)
+		require.Equal(t, ServiceTokenAudience, claims.Audience)
+		require.Equal(t, "user_slug", claims.UserSlug)
+		require.Equal(t, "retrieve_vault", claims.ClientOperation)
+		require.Equal(t, "request_id", claims.RequestID)
+		require.GreaterOrEqual(t, claims.IssuedAt, before)
+		require.Equal(t, claims.IssuedAt + 60, claims.ExpiresAt)
+
+		key, err := hex.DecodeString(hexKey)
+		require.NoError(t, err)
+
+		mac := hmac.New(sha256.New, key)
+		mac.Write([]byte(parts[0] + "." + parts[1]))
+		require.Equal(t, base64.RawURLEncoding.EncodeToString(mac.Sum(nil)), parts[2])
+	})
+
+	t.Run("other_key_doesn't_verify", func(t *testing.T) {
+		token, err := SignServiceToken("key-1", hexKey, "user_slug", "", "", time.Minute)
+		require.NoError(t, err)
+
+		parts := strings.Split(token, ".")
+		mac := hmac.New(sha256.New, []byte("some other key"))
+		mac.Write([]byte(parts[0] + "." + parts[1]))
+		require.NotEqual(t, base64.RawURLEncoding.EncodeToString(mac.Sum(nil)), parts[2])
+	})
+
+	t.Run("invalid_hex_key", func(t *testing.T) {
+		_, err := SignServiceToken("key-1", "not hex", "user_slug", "", "", time.Minute)
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,59 @@
+package utils
+
+import (
+	"strconv"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	ShareSlugLength          int           = 32
+	ShareCiphertextMaxLength int           = 16384
+	ShareLabelMaxLength      int           = 64
+	ShareMaxViewsMax         int           = 10
+	SharesPerUserMax         int           = 100
+	ShareDefaultExpiresIn    time.Duration = 24 * time.Hour
+	ShareMinExpiresIn        time.Duration = 5 * time.Minute
+	ShareMaxExpiresIn        time.Duration = 7 * 24 * time.Hour
+)
+
+// ValidateShare checks a create_share request, returning field errors keyed by JSON name, or
+// nil. A zero expiresIn or maxViews means the default of one day or one view.
+func ValidateShare(ciphertext, label string, expiresIn, maxViews int) map[string][]string {
+	fieldErrors := map[string][]string{}
+
+	if len(ciphertext) > ShareCiphertextMaxLength || !ShareCiphertextRegexp.MatchString(ciphertext) {
+		fieldErrors["ciphertext"] = append(
+			fieldErrors["ciphertext"],
+			"Must be base64, at most " + strconv.Itoa(ShareCiphertextMaxLength) + " characters",
+		)
+	}
+
+	if utf8.RuneCountInString(label) > ShareLabelMaxLength || ControlCharRegexp.MatchString(label) {
+		fieldErrors["label"] = append(
+			fieldErrors["label"],
+			"Must be at most " + strconv.Itoa(ShareLabelMaxLength) + " characters",
+		)
+	}
+
+	if expiresIn != 0 && (time.Duration(expiresIn) * time.Second < ShareMinExpiresIn ||
+	time.Duration(expiresIn) * time.Second > ShareMaxExpiresIn) {
+		fieldErrors["expires_in"] = append(
+			fieldErrors["expires_in"],
+			"Must be from " + strconv.Itoa(int(ShareMinExpiresIn.Seconds())) + " to " +
+			strconv.Itoa(int(ShareMaxExpiresIn.Seconds())) + " seconds",
+		)
+	}
+
+	if maxViews < 0 || maxViews > ShareMaxViewsMax {
+		fieldErrors["max_views"] = append(
+			fieldErrors["max_views"], "Must be from 1 to " + strconv.Itoa(ShareMaxViewsMax),
+		)
+	}
+
+	if len(fieldErrors) > 0 {
+		return fieldErrors
+	}
+
+	return nil
+}
@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	TagMaxLength    int = 32
+	TagsPerEntryMax int = 20
+	TagsPerUserMax  int = 200
+)
+
+// Letters or digits first, then letters, digits, spaces, '-' or '_'
+var tagRegexp = regexp.MustCompile(
+	`^[\p{L}\p{N}][\p{L}\p{N} _\-]{0,` + strconv.Itoa(TagMaxLength - 1) + `}$`,
+)
+
+// NormalizeTag trims and lower-cases tag, so "Work " and "work" name the same tag. Returns
+// false if the result isn't a valid tag name.
+func NormalizeTag(tag string) (string, bool) {
+	tag = strings.ToLower(strings.Join(strings.Fields(tag), " "))
+
+	return tag, tagRegexp.MatchString(tag)
+}
+
+// UserTags counts how many of the user's entries carry each tag
+func UserTags(docs []SearchDocument) map[string]int {
+	tags := map[string]int{}
+
+	for _, doc := range docs {
+		for _, tag := range doc.Tags {
+			tags[tag]++
+		}
+	}
+
+	return tags
+}
+
+// FilterDocuments keeps the docs tagged with tag (if not empty) and, if favoritesOnly, marked
+// as favorite
+func FilterDocuments(docs []SearchDocument, tag string, favoritesOnly bool) []SearchDocument {
+	if tag == "" && !favoritesOnly {
+		return docs
+	}
+
+	filtered := []SearchDocument{}
+
+	for _, doc := range docs {
+		if favoritesOnly && !doc.Favorite {
+			continue
+		}
+
+		if tag != "" && !doc.HasTag(tag) {
+			continue
+		}
+
+		filtered = append(filtered, doc)
+	}
+
+	return filtered
+}
+
+func (doc *SearchDocument) HasTag(tag string) bool {
+	for _, t := range doc.Tags {
+		if t == tag {
+			return true
+		}
+	}
+
+	return false
+}
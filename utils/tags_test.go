@@ -0,0 +1,45 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeTag(t *testing.T) {
+	valid := map[string]string{
+		"work":                  "work",
+		"  Work  ":              "work",
+		"Side   Projects":       "side projects",
+		"2fa_backup-codes":      "2fa_backup-codes",
+		"Café":                  "café",
+		strings.Repeat("a", 32): strings.Repeat("a", 32),
+	}
+
+	for tag, expected := range valid {
+		normalized, ok := NormalizeTag(tag)
+		require.True(t, ok, tag)
+		require.Equal(t, expected, normalized)
+	}
+
+	for _, tag := range []string{"", "   ", "-work", "a/b", "tag\x00", strings.Repeat("a", 33)} {
+		_, ok := NormalizeTag(tag)
+		require.False(t, ok, tag)
+	}
+}
+
+func TestFilterDocuments(t *testing.T) {
+	docs := []SearchDocument{
+		{EntrySlug: "a", Tags: []string{"work", "bank"}, Favorite: true},
+		{EntrySlug: "b", Tags: []string{"work"}},
+		{EntrySlug: "c", Favorite: true},
+	}
+
+	require.Len(t, FilterDocuments(docs, "", false), 3)
+	require.Len(t, FilterDocuments(docs, "work", false), 2)
+	require.Len(t, FilterDocuments(docs, "", true), 2)
+	require.Equal(t, "a", FilterDocuments(docs, "work", true)[0].EntrySlug)
+	require.Empty(t, FilterDocuments(docs, "home", false))
+	require.Equal(t, map[string]int{"work": 2, "bank": 1}, UserTags(docs))
+}
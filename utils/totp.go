@@ -0,0 +1,123 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"hash"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	SecretKindText string = "text"
+	SecretKindTOTP string = "totp"
+)
+
+const totpDefaultDigits int = 6
+const totpDefaultPeriod int = 30
+const totpMinSecretBytes int = 10
+
+var ErrTOTPSecret = errors.New("invalid TOTP secret")
+
+type TOTPParams struct {
+	Secret    []byte
+	Algorithm string
+	Digits    int
+	Period    int
+}
+
+// ParseTOTP accepts either an otpauth://totp/ URI, as encoded in authenticator QR codes, or a
+// bare base32 seed. Spaces, lowercase and missing padding are tolerated in the seed.
+func ParseTOTP(s string) (*TOTPParams, error) {
+	params := TOTPParams{Algorithm: "SHA1", Digits: totpDefaultDigits, Period: totpDefaultPeriod}
+	seed := s
+
+	if strings.HasPrefix(strings.ToLower(s), "otpauth://") {
+		u, err := url.Parse(s)
+
+		if err != nil || !strings.EqualFold(u.Host, "totp") {
+			return nil, ErrTOTPSecret
+		}
+
+		query := u.Query()
+		seed = query.Get("secret")
+
+		if algorithm := query.Get("algorithm"); algorithm != "" {
+			params.Algorithm = strings.ToUpper(algorithm)
+		}
+
+		if digits := query.Get("digits"); digits != "" {
+			if params.Digits, err = strconv.Atoi(digits); err != nil {
+				return nil, ErrTOTPSecret
+			}
+		}
+
+		if period := query.Get("period"); period != "" {
+			if params.Period, err = strconv.Atoi(period); err != nil {
+				return nil, ErrTOTPSecret
+			}
+		}
+	}
+
+	seed = strings.ToUpper(strings.ReplaceAll(seed, " ", ""))
+	seed = strings.TrimRight(seed, "=")
+	secret, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(seed)
+
+	if err != nil || len(secret) < totpMinSecretBytes {
+		return nil, ErrTOTPSecret
+	}
+
+	params.Secret = secret
+
+	if params.hash() == nil || params.Digits < 6 || params.Digits > 8 || params.Period < 1 ||
+	params.Period > 300 {
+		return nil, ErrTOTPSecret
+	}
+
+	return &params, nil
+}
+
+func (P *TOTPParams) hash() func() hash.Hash {
+	switch P.Algorithm {
+	case "SHA1":
+		return sha1.New
+	case "SHA256":
+		return sha256.New
+	case "SHA512":
+		return sha512.New
+	}
+
+	return nil
+}
+
+// Code computes the RFC 6238 code at t and the seconds until it rolls over.
+func (P *TOTPParams) Code(t time.Time) (code string, secondsRemaining int) {
+	unix := t.Unix()
+	counter := make([]byte, 8)
+	binary.BigEndian.PutUint64(counter, uint64(unix / int64(P.Period)))
+
+	mac := hmac.New(P.hash(), P.Secret)
+	mac.Write(counter)
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum) - 1] & 0x0F
+	value := binary.BigEndian.Uint32(sum[offset:offset + 4]) & 0x7FFFFFFF
+
+	mod := uint32(1)
+
+	for i := 0; i < P.Digits; i++ {
+		mod *= 10
+	}
+
+	code = strconv.FormatUint(uint64(value % mod), 10)
+	code = strings.Repeat("0", P.Digits - len(code)) + code
+
+	return code, P.Period - int(unix % int64(P.Period))
+}
@@ -0,0 +1,79 @@
+package utils
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTOTP(t *testing.T) {
+	encode := func(s string) string {
+		return base32.StdEncoding.EncodeToString([]byte(s))
+	}
+
+	t.Run("rfc6238_vectors", func(t *testing.T) {
+		seeds := map[string]string{
+			"SHA1":   encode("12345678901234567890"),
+			"SHA256": encode("12345678901234567890123456789012"),
+			"SHA512": encode(strings.Repeat("1234567890", 6) + "1234"),
+		}
+
+		vectors := []struct {
+			unix  int64
+			codes map[string]string
+		}{
+			{59, map[string]string{"SHA1": "94287082", "SHA256": "46119246", "SHA512": "90693936"}},
+			{1111111109, map[string]string{
+				"SHA1": "07081804", "SHA256": "68084774", "SHA512": "25091201",
+			}},
+			{1234567890, map[string]string{
+				"SHA1": "89005924", "SHA256": "91819424", "SHA512": "93441116",
+			}},
+			{2000000000, map[string]string{
+				"SHA1": "69279037", "SHA256": "90698825", "SHA512": "38618901",
+			}},
+		}
+
+		for _, vector := range vectors {
+			for algorithm, expected := range vector.codes {
+				params, err := ParseTOTP(
+					"otpauth://totp/Example:alice@example.com?secret=" + seeds[algorithm] +
+					"&algorithm=" + algorithm + "&digits=8&period=30",
+				)
+				require.NoError(t, err)
+
+				code, remaining := params.Code(time.Unix(vector.unix, 0))
+				require.Equal(t, expected, code, algorithm)
+				require.Equal(t, 30 - int(vector.unix % 30), remaining)
+			}
+		}
+	})
+
+	t.Run("bare_base32_seed", func(t *testing.T) {
+		seed := strings.ToLower(strings.TrimRight(encode("12345678901234567890"), "="))
+		params, err := ParseTOTP(seed[:8] + " " + seed[8:])
+		require.NoError(t, err)
+		require.Equal(t, 6, params.Digits)
+		require.Equal(t, 30, params.Period)
+
+		code, _ := params.Code(time.Unix(59, 0))
+		require.Equal(t, "287082", code)
+	})
+
+	t.Run("invalid_secrets", func(t *testing.T) {
+		for _, invalid := range []string{
+			"",
+			"not base32!",
+			"JBSWY3DP",
+			"otpauth://hotp/Example?secret=" + encode("12345678901234567890"),
+			"otpauth://totp/Example?secret=" + encode("12345678901234567890") + "&digits=4",
+			"otpauth://totp/Example?secret=" + encode("12345678901234567890") + "&algorithm=MD5",
+		} {
+			_, err := ParseTOTP(invalid)
+			require.ErrorIs(t, err, ErrTOTPSecret, invalid)
+		}
+	})
+}
@@ -0,0 +1,157 @@
+package utils
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+)
+
+const (
+	URLMatchDomain string = "domain"
+	URLMatchHost   string = "host"
+	URLMatchPrefix string = "prefix"
+	URLMatchNever  string = "never"
+)
+
+const URLMatchMaxLength int = 2048
+
+var URLMatchModes = []string{URLMatchDomain, URLMatchHost, URLMatchPrefix, URLMatchNever}
+
+// A NormalizedURL is an http(s) URL reduced to what matching looks at: lower-cased scheme and
+// host, no default port, no trailing dot and no fragment
+type NormalizedURL struct {
+	Scheme string
+	Host   string
+	Path   string
+}
+
+type URLMatchResult struct {
+	EntrySlug  string `json:"entry_slug"`
+	EntryTitle string `json:"entry_title"`
+}
+
+func ValidURLMatch(mode string) bool {
+	for _, m := range URLMatchModes {
+		if m == mode {
+			return true
+		}
+	}
+
+	return false
+}
+
+// NormalizeURL parses raw as an http or https URL. Returns false for anything else.
+func NormalizeURL(raw string) (NormalizedURL, bool) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return NormalizedURL{}, false
+	}
+
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+
+	if strings.Contains(host, ":") {
+		host = "[" + host + "]"
+	}
+
+	if port := u.Port(); port != "" &&
+	!(u.Scheme == "http" && port == "80") && !(u.Scheme == "https" && port == "443") {
+		host += ":" + port
+	}
+
+	path := u.EscapedPath()
+
+	if path == "" {
+		path = "/"
+	}
+
+	if u.RawQuery != "" {
+		path += "?" + u.RawQuery
+	}
+
+	return NormalizedURL{Scheme: u.Scheme, Host: host, Path: path}, true
+}
+
+func (u NormalizedURL) String() string {
+	return u.Scheme + "://" + u.Host + u.Path
+}
+
+func (u NormalizedURL) Hostname() string {
+	if i := strings.LastIndex(u.Host, ":"); i > strings.LastIndex(u.Host, "]") {
+		return strings.Trim(u.Host[:i], "[]")
+	}
+
+	return strings.Trim(u.Host, "[]")
+}
+
+// MatchDocuments returns the docs whose entry URL matches target under the entry's url_match
+// rule, defaulting to the registrable domain. Entry URLs saved without a scheme are read as
+// https. Entries on target's exact host come first.
+func MatchDocuments(docs []SearchDocument, target NormalizedURL) []URLMatchResult {
+	type match struct {
+		result    URLMatchResult
+		exactHost bool
+	}
+
+	matches := []match{}
+	targetDomain, targetHasDomain := RegistrableDomain(target.Hostname())
+
+	for _, doc := range docs {
+		raw := doc.EntryURL
+
+		if raw == "" || doc.URLMatch == URLMatchNever {
+			continue
+		}
+
+		if !strings.Contains(raw, "://") {
+			raw = "https://" + raw
+		}
+
+		entryURL, ok := NormalizeURL(raw)
+
+		if !ok {
+			continue
+		}
+
+		switch doc.URLMatch {
+		case URLMatchHost:
+			ok = entryURL.Host == target.Host
+		case URLMatchPrefix:
+			ok = hasURLPrefix(target.String(), entryURL.String())
+		default:
+			domain, hasDomain := RegistrableDomain(entryURL.Hostname())
+			ok = hasDomain && targetHasDomain && domain == targetDomain
+		}
+
+		if ok {
+			matches = append(matches, match{
+				result:    URLMatchResult{EntrySlug: doc.EntrySlug, EntryTitle: doc.EntryTitle},
+				exactHost: entryURL.Host == target.Host,
+			})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].exactHost && !matches[j].exactHost
+	})
+
+	results := make([]URLMatchResult, len(matches))
+
+	for i, m := range matches {
+		results[i] = m.result
+	}
+
+	return results
+}
+
+// A prefix only matches whole path segments, so "/login" matches "/login/2fa" and "/login?next=/"
+// but not "/login-evil".
+func hasURLPrefix(target, prefix string) bool {
+	if !strings.HasPrefix(target, prefix) {
+		return false
+	}
+
+	rest := target[len(prefix):]
+
+	return rest == "" || strings.HasSuffix(prefix, "/") || rest[0] == '/' || rest[0] == '?'
+}
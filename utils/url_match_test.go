@@ -0,0 +1,90 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistrableDomain(t *testing.T) {
+	domains := map[string]string{
+		"example.com":              "example.com",
+		"login.example.com":        "example.com",
+		"a.b.example.co.uk":        "example.co.uk",
+		"foo.github.io":            "foo.github.io",
+		"city.kawasaki.jp":         "city.kawasaki.jp",
+		"www.city.kawasaki.jp":     "city.kawasaki.jp",
+		"ward.kawasaki.jp":         "",
+		"www.ward.kawasaki.jp":     "www.ward.kawasaki.jp",
+		"shop.example.unknowntld":  "example.unknowntld",
+		"Example.COM.":             "example.com",
+		"192.168.0.1":              "192.168.0.1",
+		"co.uk":                    "",
+	}
+
+	for host, expected := range domains {
+		domain, ok := RegistrableDomain(host)
+		require.Equal(t, expected != "", ok, host)
+		require.Equal(t, expected, domain, host)
+	}
+}
+
+func TestNormalizeURL(t *testing.T) {
+	u, ok := NormalizeURL("HTTPS://Login.Example.com.:443/path?next=1#frag")
+	require.True(t, ok)
+	require.Equal(t, "https://login.example.com/path?next=1", u.String())
+
+	u, ok = NormalizeURL("http://[::1]:8080")
+	require.True(t, ok)
+	require.Equal(t, "[::1]:8080", u.Host)
+	require.Equal(t, "::1", u.Hostname())
+
+	for _, invalid := range []string{
+		"", "example.com", "ftp://example.com", "https://", "javascript:x",
+	} {
+		_, ok = NormalizeURL(invalid)
+		require.False(t, ok, invalid)
+	}
+}
+
+func TestMatchDocuments(t *testing.T) {
+	docs := []SearchDocument{
+		{EntrySlug: "1", EntryTitle: "Example", EntryURL: "https://example.com/login"},
+		{EntrySlug: "2", EntryTitle: "Example mail", EntryURL: "mail.example.com",
+		URLMatch: URLMatchHost},
+		{EntrySlug: "3", EntryTitle: "Example admin", EntryURL: "https://example.com/admin",
+		URLMatch: URLMatchPrefix},
+		{EntrySlug: "4", EntryTitle: "Example hidden", EntryURL: "https://example.com",
+		URLMatch: URLMatchNever},
+		{EntrySlug: "5", EntryTitle: "Other pages", EntryURL: "https://other.github.io"},
+		{EntrySlug: "6", EntryTitle: "No URL"},
+	}
+
+	matches := map[string][]string{
+		"https://mail.example.com/inbox":     {"2", "1"},
+		"https://www.example.com":            {"1"},
+		"https://example.com/admin/users":    {"1", "3"},
+		"https://example.com/admin?tab=1":    {"1", "3"},
+		"https://example.com/administrator":  {"1"},
+		"https://example.com/admin-evil":     {"1"},
+		"http://example.com/admin":           {"1"},
+		"https://example.com.evil.net/admin": {},
+		"https://foo.github.io":              {},
+		"https://other.github.io/page":       {"5"},
+	}
+
+	for target, expected := range matches {
+		t.Run("match_documents_" + target, func(t *testing.T) {
+			u, ok := NormalizeURL(target)
+			require.True(t, ok)
+
+			slugs := []string{}
+
+			for _, result := range MatchDocuments(docs, u) {
+				slugs = append(slugs, result.EntrySlug)
+			}
+
+			require.Equal(t, expected, slugs)
+		})
+	}
+}
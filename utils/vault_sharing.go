@@ -0,0 +1,54 @@
+package utils
+
+import (
+	"encoding/base64"
+	"time"
+)
+
+const (
+	VaultRoleRead   string = "read"
+	VaultRoleWrite  string = "write"
+	VaultRoleManage string = "manage"
+)
+
+const (
+	PublicKeyLength              int           = 32
+	WrappedKeyMaxLength          int           = 1024
+	EncryptedPrivateKeyMaxLength int           = 4096
+	VaultInvitationTTL           time.Duration = 7 * 24 * time.Hour
+	InvitationSlugLength         int           = 32
+)
+
+var VaultRoles = []string{VaultRoleRead, VaultRoleWrite, VaultRoleManage}
+
+var vaultRoleRanks = map[string]int{VaultRoleRead: 1, VaultRoleWrite: 2, VaultRoleManage: 3}
+
+func ValidVaultRole(role string) bool {
+	return vaultRoleRanks[role] > 0
+}
+
+// VaultRoleAtLeast reports whether role grants everything required does. Each role includes
+// the ones below it: manage > write > read.
+func VaultRoleAtLeast(role, required string) bool {
+	return ValidVaultRole(role) && vaultRoleRanks[role] >= vaultRoleRanks[required]
+}
+
+// Key wrapping: each user's client generates an X25519 key pair and uploads the public key with
+// the private key encrypted under a key only the client can derive. To give a member a vault's
+// key, a manager's client seals it to the member's public key (a NaCl sealed box) and uploads
+// the result as that member's wrapped key. The gateway stores and relays these blobs but can
+// never open them.
+
+// DecodePublicKey decodes a base64 X25519 public key, returning false if it isn't one
+func DecodePublicKey(encoded string) ([]byte, bool) {
+	key, err := base64.StdEncoding.DecodeString(encoded)
+
+	return key, err == nil && len(key) == PublicKeyLength
+}
+
+// DecodeKeyBlob decodes a base64 wrapped or encrypted key of at most maxLength bytes
+func DecodeKeyBlob(encoded string, maxLength int) ([]byte, bool) {
+	blob, err := base64.StdEncoding.DecodeString(encoded)
+
+	return blob, err == nil && len(blob) > 0 && len(blob) <= maxLength
+}